@@ -11,7 +11,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/go-redis/redis/v8"
 )
@@ -69,6 +74,20 @@ func InitRedis() error {
 	return nil
 }
 
+// SetClient replaces the client of the redis instance, e.g. with a client of an instance started by tests.
+// Unlike InitRedis, the configuration of the instance is not changed.
+func SetClient(client *redis.Client) {
+	redisClient = client
+}
+
+// Ping checks if the redis instance can be reached.
+func Ping(ctx context.Context) error {
+	if redisClient == nil {
+		return errors.New("redis connection not initialized")
+	}
+	return redisClient.Ping(ctx).Err()
+}
+
 // CloseRedis closes the connection to the redis instance.
 func CloseRedis() error {
 	if redisClient == nil {
@@ -82,30 +101,48 @@ func CloseRedis() error {
 	return nil
 }
 
+// responseKeyPrefix is the prefix of the keys of cached responses, separating them from other entries.
+const responseKeyPrefix = "response:"
+
+// responseKey returns the key of the cached response for the URL.
+func responseKey(url string) string {
+	return responseKeyPrefix + url
+}
+
+// NormalizeURL returns the URL with its query parameters sorted by their names and encoded
+// consistently, so requests differing only in the order or encoding of their parameters use the
+// same cache entry. The order of repeated values of a parameter is kept.
+func NormalizeURL(u *url.URL) string {
+	normalized := *u
+	normalized.RawQuery = u.Query().Encode()
+	return normalized.String()
+}
+
 // responseHash represents a response entry in the redis cache
 // and is used for scanning redis results.
 type responseHash struct {
 	HeaderBytes []byte `redis:"header"`
 	Json        []byte `redis:"json"`
+	ETag        string `redis:"etag"`
 }
 
 // GetCachedResponse fetches the redis cache entry for the url as the key
-// and returns the decoded http.Header and json. If no entry is found, a
+// and returns the decoded http.Header, json and ETag. If no entry is found, a
 // CacheMissError will be returned.
-func GetCachedResponse(url string) (http.Header, []byte, error) {
+func GetCachedResponse(url string) (http.Header, []byte, string, error) {
 	if redisClient == nil {
-		return nil, nil, errors.New("redis connection not initialized")
+		return nil, nil, "", errors.New("redis connection not initialized")
 	}
-	// Read the hash from redis: HMGET <url> header json
-	readResult := redisClient.HMGet(context.Background(), url, "header", "json")
+	// Read the hash from redis: HMGET response:<url> header json etag
+	readResult := redisClient.HMGet(context.Background(), responseKey(url), "header", "json", "etag")
 	// Store the data into an intermediate struct
 	var result responseHash
 	if err := readResult.Scan(&result); err != nil {
-		return nil, nil, err
+		return nil, nil, "", err
 	}
 	// If both byte slices are empty, a cache miss occurred
 	if len(result.HeaderBytes) == 0 && len(result.Json) == 0 {
-		return nil, nil, &CacheMissError{url}
+		return nil, nil, "", &CacheMissError{url}
 	}
 	// Deserialize []byte header to http.Header
 	var header http.Header
@@ -113,34 +150,91 @@ func GetCachedResponse(url string) (http.Header, []byte, error) {
 	decoder := gob.NewDecoder(buffer)
 	err := decoder.Decode(&header)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, "", err
 	}
-	return header, result.Json, nil
+	return header, result.Json, result.ETag, nil
 }
 
-// CacheResponseRecorder is a custom http.ResponseWriter recording the
-// json/body and the status code of a HTTP response for caching purposes.
+// CacheResponseRecorder is a custom http.ResponseWriter recording the header, json/body
+// and the status code of a HTTP response for caching purposes. The response is not
+// written to a client until WriteResponse is called, so it can be shared between requests.
 type CacheResponseRecorder struct {
-	http.ResponseWriter
+	header http.Header
 	Json   []byte
 	Status int
 }
 
+// NewCacheResponseRecorder creates a CacheResponseRecorder with an empty header.
+func NewCacheResponseRecorder() *CacheResponseRecorder {
+	return &CacheResponseRecorder{header: make(http.Header)}
+}
+
+// Header - implementation of http.ResponseWriter interface returning the recorded header.
+func (c *CacheResponseRecorder) Header() http.Header {
+	return c.header
+}
+
 // Write - implementation of http.ResponseWriter interface storing the body/json.
 func (c *CacheResponseRecorder) Write(b []byte) (int, error) {
-	c.Json = b
-	return c.ResponseWriter.Write(b)
+	if c.Status == 0 {
+		c.Status = http.StatusOK
+	}
+	c.Json = append(c.Json, b...)
+	return len(b), nil
 }
 
 // WriteHeader - implementation of http.ResponseWriter interface storing the status code.
 func (c *CacheResponseRecorder) WriteHeader(status int) {
 	c.Status = status
-	c.ResponseWriter.WriteHeader(status)
 }
 
-// StoreResponse stores the header and json of a HTTP response in the redis
-// cache, using the URL as the key.
-func StoreResponse(url string, header http.Header, json []byte) error {
+// WriteResponse writes the recorded header, status code and body to the http.ResponseWriter.
+func (c *CacheResponseRecorder) WriteResponse(w http.ResponseWriter) {
+	CopyHeader(w.Header(), c.header)
+	if c.Status == 0 {
+		c.Status = http.StatusOK
+	}
+	w.WriteHeader(c.Status)
+	w.Write(c.Json)
+}
+
+// CopyHeader copies the fields of src into dst, replacing the values of fields that are already set.
+// Only the tokens of Vary are added to the ones of dst, since the response still depends on them,
+// e.g. on the origin for CORS headers set on dst before.
+func CopyHeader(dst http.Header, src http.Header) {
+	for k, v := range src {
+		if k == "Vary" {
+			AddVary(dst, v...)
+			continue
+		}
+		dst[k] = append([]string(nil), v...)
+	}
+}
+
+// AddVary adds the comma-separated tokens of the values to the Vary header, skipping
+// the tokens that are already listed. All tokens are kept in a single Vary value.
+func AddVary(header http.Header, values ...string) {
+	var tokens []string
+	listed := make(map[string]bool)
+	for _, value := range append(header.Values("Vary"), values...) {
+		for _, token := range strings.Split(value, ",") {
+			token = strings.TrimSpace(token)
+			if token == "" || listed[strings.ToLower(token)] {
+				continue
+			}
+			listed[strings.ToLower(token)] = true
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) > 0 {
+		header.Set("Vary", strings.Join(tokens, ", "))
+	}
+}
+
+// StoreResponse stores the header, json and ETag of a HTTP response in the
+// redis cache, using the prefixed URL as the key. The entry expires after the
+// TTL, a TTL of 0 keeps it until it is evicted or deleted.
+func StoreResponse(url string, header http.Header, json []byte, etag string, ttl time.Duration) error {
 	if redisClient == nil {
 		return errors.New("redis connection not initialized")
 	}
@@ -151,7 +245,288 @@ func StoreResponse(url string, header http.Header, json []byte) error {
 	if err != nil {
 		return err
 	}
-	// Store the values as Hash in redis: HSET <url> header <header> json <json>
-	redisClient.HSet(context.Background(), url, "header", buffer.Bytes(), "json", json)
-	return nil
+	// Store the values as Hash in redis: HSET response:<url> header <header> json <json> etag <etag>
+	// and set the expiry in the same transaction, so no entry without the TTL is left if it fails
+	_, err = redisClient.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		pipe.HSet(context.Background(), responseKey(url), "header", buffer.Bytes(), "json", json, "etag", etag)
+		if ttl > 0 {
+			pipe.Expire(context.Background(), responseKey(url), ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// InvalidateResource deletes all cached responses containing the resource after it was updated:
+// its detail responses requested by ID or name (including subroutes like '/defenses') and all
+// responses of the resource list. Responses of other resources referencing it, e.g. the pokemon
+// of an ability, are not deleted. Returns the number of deleted entries.
+func InvalidateResource(resourceTypeName string, id int, name string) (int, error) {
+	if redisClient == nil {
+		return 0, errors.New("redis connection not initialized")
+	}
+	// The responses are cached for each base URL they were requested with, e.g. "https://<host>"
+	listPath := "*/v1/" + globEscape(resourceTypeName)
+	patterns := []string{listPath, listPath + "[?]*"}
+	// The name is matched regardless of its case since the handlers accept any case
+	for _, searchArg := range []string{strconv.Itoa(id), caseInsensitiveGlob(url.PathEscape(name))} {
+		detailPath := listPath + "/" + searchArg
+		patterns = append(patterns, detailPath, detailPath+"[?]*", detailPath+"/*")
+	}
+	deleted := 0
+	for _, pattern := range patterns {
+		n, err := deleteMatching(responseKey(pattern))
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// deleteMatching deletes all keys matching the glob pattern, using SCAN
+// instead of KEYS to avoid blocking redis. Returns the number of deleted keys.
+func deleteMatching(pattern string) (int, error) {
+	deleted := 0
+	iter := redisClient.Scan(context.Background(), 0, pattern, 100).Iterator()
+	for iter.Next(context.Background()) {
+		n, err := redisClient.Del(context.Background(), iter.Val()).Result()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += int(n)
+	}
+	return deleted, iter.Err()
+}
+
+// CacheStats contains the statistics of the redis cache.
+type CacheStats struct {
+	// Keys is the number of all keys, including the entries of the rate limiting
+	Keys int64
+	// Responses is the number of cached responses
+	Responses int
+	// UsedMemory is the memory used by redis in bytes
+	UsedMemory int64
+	// MaxMemory is the memory limit of redis in bytes, 0 if it is unlimited
+	MaxMemory int64
+}
+
+// Stats returns the number of keys and cached responses and the memory usage of the redis instance.
+func Stats(ctx context.Context) (CacheStats, error) {
+	var stats CacheStats
+	if redisClient == nil {
+		return stats, errors.New("redis connection not initialized")
+	}
+	keys, err := redisClient.DBSize(ctx).Result()
+	if err != nil {
+		return stats, err
+	}
+	stats.Keys = keys
+	// Count the responses with SCAN instead of KEYS to avoid blocking redis
+	iter := redisClient.Scan(ctx, 0, responseKey("*"), 100).Iterator()
+	for iter.Next(ctx) {
+		stats.Responses++
+	}
+	if err := iter.Err(); err != nil {
+		return stats, err
+	}
+	info, err := redisClient.Info(ctx, "memory").Result()
+	if err != nil {
+		return stats, err
+	}
+	// The info is returned as lines of "<field>:<value>"
+	for _, line := range strings.Split(info, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		switch parts[0] {
+		case "used_memory":
+			stats.UsedMemory, _ = strconv.ParseInt(parts[1], 10, 64)
+		case "maxmemory":
+			stats.MaxMemory, _ = strconv.ParseInt(parts[1], 10, 64)
+		}
+	}
+	return stats, nil
+}
+
+// PurgeAll deletes all cached responses and response bodies, other entries like the token
+// buckets of the rate limiting are kept. Returns the number of deleted entries.
+func PurgeAll() (int, error) {
+	if redisClient == nil {
+		return 0, errors.New("redis connection not initialized")
+	}
+	deleted := 0
+	for _, pattern := range []string{responseKey("*"), "body:*"} {
+		n, err := deleteMatching(pattern)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// PurgeURL deletes the cached responses for the path and query of the URL, which is normalized like the
+// URLs of the requests. The responses of all base URLs they were requested with are deleted.
+// Returns the number of deleted entries, which is 0 if the response was not cached.
+func PurgeURL(u *url.URL) (int, error) {
+	if redisClient == nil {
+		return 0, errors.New("redis connection not initialized")
+	}
+	return deleteMatching(responseKey("*" + globEscape(NormalizeURL(&url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: u.RawQuery}))))
+}
+
+// globEscape escapes the special characters of redis glob patterns in the string.
+func globEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`).Replace(s)
+}
+
+// caseInsensitiveGlob returns an escaped glob pattern matching the string regardless of its case.
+func caseInsensitiveGlob(s string) string {
+	var pattern strings.Builder
+	for _, r := range s {
+		lower, upper := unicode.ToLower(r), unicode.ToUpper(r)
+		if lower != upper {
+			pattern.WriteString("[" + string(lower) + string(upper) + "]")
+		} else {
+			pattern.WriteString(globEscape(string(r)))
+		}
+	}
+	return pattern.String()
+}
+
+// bodyTTL is the duration response bodies stored by their ETag are kept in the redis cache.
+const bodyTTL = 24 * time.Hour
+
+// GetBody fetches the response body stored for the ETag from the redis cache.
+// If no entry is found, a CacheMissError will be returned.
+func GetBody(etag string) ([]byte, error) {
+	if redisClient == nil {
+		return nil, errors.New("redis connection not initialized")
+	}
+	key := "body:" + etag
+	body, err := redisClient.Get(context.Background(), key).Bytes()
+	if err == redis.Nil {
+		return nil, &CacheMissError{key}
+	} else if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// StoreBody stores a response body in the redis cache, using the ETag as the key.
+func StoreBody(etag string, body []byte) error {
+	if redisClient == nil {
+		return errors.New("redis connection not initialized")
+	}
+	return redisClient.Set(context.Background(), "body:"+etag, body, bodyTTL).Err()
+}
+
+// dataVersionChannel is the redis pub/sub channel notifying about changes of the data version.
+const dataVersionChannel = "data-version"
+
+// PublishDataVersion notifies all subscribers that the data changed to the version.
+// It should be called by every path updating the data.
+func PublishDataVersion(version string) error {
+	if redisClient == nil {
+		return errors.New("redis connection not initialized")
+	}
+	return redisClient.Publish(context.Background(), dataVersionChannel, version).Err()
+}
+
+// DataVersionSubscription receives the data versions published with PublishDataVersion.
+type DataVersionSubscription struct {
+	pubsub   *redis.PubSub
+	versions chan string
+	done     chan struct{}
+}
+
+// SubscribeDataVersion subscribes to the changes of the data version. The
+// subscription needs to be closed with Close once it is not needed anymore.
+func SubscribeDataVersion(ctx context.Context) (*DataVersionSubscription, error) {
+	if redisClient == nil {
+		return nil, errors.New("redis connection not initialized")
+	}
+	pubsub := redisClient.Subscribe(ctx, dataVersionChannel)
+	// Wait for the confirmation so no version published afterwards is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+	subscription := &DataVersionSubscription{pubsub: pubsub, versions: make(chan string), done: make(chan struct{})}
+	// Forward the payloads of the messages until the subscription is closed
+	go func() {
+		defer close(subscription.versions)
+		for message := range pubsub.Channel() {
+			select {
+			case subscription.versions <- message.Payload:
+			case <-subscription.done:
+				return
+			}
+		}
+	}()
+	return subscription, nil
+}
+
+// Versions returns the channel receiving the published data versions.
+// The channel is closed when the subscription is closed.
+func (s *DataVersionSubscription) Versions() <-chan string {
+	return s.versions
+}
+
+// Close ends the subscription.
+func (s *DataVersionSubscription) Close() error {
+	close(s.done)
+	return s.pubsub.Close()
+}
+
+// tokenBucketScript takes a token from the bucket stored in the hash of KEYS[1] atomically. The bucket
+// is refilled with ARGV[1] tokens per second up to ARGV[2] tokens, ARGV[3] is the current time in ms.
+// Returns 1 if a token was taken (0 otherwise) and the remaining tokens as a string to keep the fraction.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1]) or burst
+local ts = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// TakeToken takes a token from the token bucket of the key, which is shared by all instances using the
+// redis instance. The bucket holds at most burst tokens and is refilled with rate tokens per second.
+// Returns if a token was available and the number of remaining tokens.
+func TakeToken(key string, rate float64, burst int) (allowed bool, remaining float64, err error) {
+	if redisClient == nil {
+		return false, 0, errors.New("redis connection not initialized")
+	}
+	result, err := tokenBucketScript.Run(context.Background(), redisClient, []string{"ratelimit:" + key}, rate, burst, time.Now().UnixMilli()).Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	if len(result) != 2 {
+		return false, 0, fmt.Errorf("unexpected result of the token bucket script: %v", result)
+	}
+	allowedValue, ok := result[0].(int64)
+	if !ok {
+		return false, 0, fmt.Errorf("unexpected result of the token bucket script: %v", result)
+	}
+	remainingValue, ok := result[1].(string)
+	if !ok {
+		return false, 0, fmt.Errorf("unexpected result of the token bucket script: %v", result)
+	}
+	remaining, err = strconv.ParseFloat(remainingValue, 64)
+	if err != nil {
+		return false, 0, err
+	}
+	return allowedValue == 1, remaining, nil
 }
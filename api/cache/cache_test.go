@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// startTestRedis starts an in-memory redis instance for the package and stops it after the test.
+func startTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	SetClient(client)
+	t.Cleanup(func() {
+		SetClient(nil)
+		client.Close()
+	})
+	return server
+}
+
+// storeResponses stores a response for each of the URLs.
+func storeResponses(t *testing.T, urls []string) {
+	t.Helper()
+	for _, u := range urls {
+		if err := StoreResponse(u, http.Header{}, []byte("{}"), "", 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// cachedURLs returns the sorted URLs of all cached responses.
+func cachedURLs(server *miniredis.Miniredis) []string {
+	var urls []string
+	for _, key := range server.Keys() {
+		urls = append(urls, key[len(responseKeyPrefix):])
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+func TestInvalidateResource(t *testing.T) {
+	server := startTestRedis(t)
+	invalidated := []string{
+		"http://api.test/v1/pokemon",
+		"http://api.test/v1/pokemon?page=2",
+		"http://api.test/v1/pokemon/1",
+		"http://api.test/v1/pokemon/1?fields=name",
+		"http://api.test/v1/pokemon/1/defenses",
+		"http://api.test/v1/pokemon/bulbasaur",
+		"http://api.test/v1/pokemon/BULBASAUR?fields=name",
+		"https://other.test/v1/pokemon/1",
+	}
+	kept := []string{
+		"http://api.test/v1/abilities/1",
+		"http://api.test/v1/pokemon-forms",
+		"http://api.test/v1/pokemon/10",
+		"http://api.test/v1/pokemon/10?fields=name",
+		"http://api.test/v1/pokemon/100/defenses",
+		"http://api.test/v1/pokemon/bulbasaurs",
+		"http://api.test/v1/pokemon/ivysaur",
+		"http://api.test/v1/pokemonx",
+	}
+	storeResponses(t, append(append([]string{}, invalidated...), kept...))
+	deleted, err := InvalidateResource("pokemon", 1, "Bulbasaur")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != len(invalidated) {
+		t.Errorf("deleted %v entries, want %v", deleted, len(invalidated))
+	}
+	sort.Strings(kept)
+	if got := cachedURLs(server); !reflect.DeepEqual(got, kept) {
+		t.Errorf("remaining entries = %v, want %v", got, kept)
+	}
+}
+
+func TestAddVary(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		values   []string
+		want     string
+	}{
+		{name: "empty header", values: []string{"Accept"}, want: "Accept"},
+		{name: "missing token", existing: []string{"Origin"}, values: []string{"Accept"}, want: "Origin, Accept"},
+		{name: "listed token", existing: []string{"Origin, Accept"}, values: []string{"accept"}, want: "Origin, Accept"},
+		{name: "multiple values", existing: []string{"Origin", "Accept"}, values: []string{"Accept-Encoding, Origin"}, want: "Origin, Accept, Accept-Encoding"},
+	}
+	for _, tt := range tests {
+		header := http.Header{"Vary": tt.existing}
+		AddVary(header, tt.values...)
+		if got := header.Values("Vary"); len(got) != 1 || got[0] != tt.want {
+			t.Errorf("%v: Vary = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCopyHeaderMergesVary(t *testing.T) {
+	dst := http.Header{"Vary": {"Origin"}, "Content-Type": {"text/plain"}}
+	src := http.Header{"Vary": {"Accept"}, "Content-Type": {"application/json"}, "Link": {"<a>", "<b>"}}
+	CopyHeader(dst, src)
+	want := http.Header{"Vary": {"Origin, Accept"}, "Content-Type": {"application/json"}, "Link": {"<a>", "<b>"}}
+	if !reflect.DeepEqual(dst, want) {
+		t.Errorf("header = %v, want %v", dst, want)
+	}
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/janek64/pmd-dx-api/api/logger"
+)
+
+// WarmConfig configures how fast the cache is warmed, so warming after a start
+// does not overload the database with all requests at once.
+type WarmConfig struct {
+	// BatchSize is the number of URLs that are fetched at the same time.
+	BatchSize int
+	// Delay is the time waited after each batch before the next one is fetched.
+	Delay time.Duration
+	// Progress is called after each batch with the number of processed and failed URLs, if it is set.
+	Progress func(processed int, failed int)
+}
+
+// Warm fills the cache by fetching the URLs with fetch, which should request them through the
+// caching middleware. The URLs are fetched in batches of config.BatchSize, waiting config.Delay
+// between the batches. The progress is reported to config.Progress after each batch and failed
+// URLs are logged as warnings without stopping the warming. Returns the number of successfully
+// fetched URLs and the error of the context if it is canceled before all batches are fetched.
+func Warm(ctx context.Context, urls []string, config WarmConfig, fetch func(ctx context.Context, url string) error) (int, error) {
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	fetched := 0
+	for start := 0; start < len(urls); start += batchSize {
+		// Wait before each batch except the first one
+		if start > 0 && config.Delay > 0 {
+			timer := time.NewTimer(config.Delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fetched, ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return fetched, err
+		}
+		end := start + batchSize
+		if end > len(urls) {
+			end = len(urls)
+		}
+		// Fetch the URLs of the batch concurrently
+		errs := make([]error, end-start)
+		var wg sync.WaitGroup
+		for i, url := range urls[start:end] {
+			wg.Add(1)
+			go func(i int, url string) {
+				defer wg.Done()
+				errs[i] = fetch(ctx, url)
+			}(i, url)
+		}
+		wg.Wait()
+		for i, err := range errs {
+			if err != nil {
+				logWarmError(fmt.Errorf("warming the cache for '%v' failed: %w", urls[start+i], err))
+				continue
+			}
+			fetched++
+		}
+		if config.Progress != nil {
+			config.Progress(end, end-fetched)
+		}
+	}
+	return fetched, nil
+}
+
+// logWarmError logs the error of a failed URL as a warning to the error log.
+func logWarmError(err error) {
+	pc, file, line, ok := runtime.Caller(1)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Warm: failed to fetch caller information")
+		return
+	}
+	caller := logger.CallerInformation{Pc: pc, File: file, Line: line}
+	logger.LogWarning(err, caller)
+}
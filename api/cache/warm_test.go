@@ -0,0 +1,185 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testURLs returns n URLs to warm.
+func testURLs(n int) []string {
+	urls := make([]string, n)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("/v1/pokemon/%v", i+1)
+	}
+	return urls
+}
+
+func TestWarmBatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		batchSize int
+		urls      int
+		want      int
+	}{
+		{name: "default batch size", batchSize: 0, urls: 3, want: 1},
+		{name: "single URLs", batchSize: 1, urls: 3, want: 1},
+		{name: "partial last batch", batchSize: 3, urls: 7, want: 3},
+		{name: "single batch", batchSize: 10, urls: 7, want: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls := testURLs(tt.urls)
+			index := make(map[string]int)
+			for i, url := range urls {
+				index[url] = i
+			}
+			// Each fetch waits until all URLs of its batch are fetched at the same time
+			var mu sync.Mutex
+			batchStarted := make(map[int]int)
+			batchReady := make(map[int]chan struct{})
+			completed := 0
+			fetch := func(ctx context.Context, url string) error {
+				batch := index[url] / tt.want
+				size := tt.want
+				if remaining := tt.urls - batch*tt.want; remaining < size {
+					size = remaining
+				}
+				mu.Lock()
+				if completed != batch*tt.want {
+					mu.Unlock()
+					return fmt.Errorf("%v started before the previous batch completed", url)
+				}
+				if batchReady[batch] == nil {
+					batchReady[batch] = make(chan struct{})
+				}
+				ready := batchReady[batch]
+				batchStarted[batch]++
+				if batchStarted[batch] == size {
+					close(ready)
+				}
+				mu.Unlock()
+				select {
+				case <-ready:
+				case <-time.After(time.Second):
+					return fmt.Errorf("%v was not fetched together with its batch", url)
+				}
+				mu.Lock()
+				completed++
+				mu.Unlock()
+				return nil
+			}
+			fetched, err := Warm(context.Background(), urls, WarmConfig{BatchSize: tt.batchSize}, fetch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fetched != tt.urls {
+				t.Errorf("fetched %v URLs, want %v", fetched, tt.urls)
+			}
+			for batch, started := range batchStarted {
+				if started > tt.want {
+					t.Errorf("batch %v fetched %v URLs at once, want at most %v", batch, started, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWarmDelay(t *testing.T) {
+	const delay = 20 * time.Millisecond
+	var mu sync.Mutex
+	var starts []time.Time
+	fetch := func(ctx context.Context, url string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		starts = append(starts, time.Now())
+		return nil
+	}
+	begin := time.Now()
+	if _, err := Warm(context.Background(), testURLs(3), WarmConfig{BatchSize: 1, Delay: delay}, fetch); err != nil {
+		t.Fatal(err)
+	}
+	if wait := starts[0].Sub(begin); wait >= delay {
+		t.Errorf("the first batch waited %v", wait)
+	}
+	for i := 1; i < len(starts); i++ {
+		if wait := starts[i].Sub(starts[i-1]); wait < delay {
+			t.Errorf("batch %v started %v after the previous one, want at least %v", i, wait, delay)
+		}
+	}
+}
+
+func TestWarmStopsOnCancellation(t *testing.T) {
+	for _, delay := range []time.Duration{0, time.Hour} {
+		t.Run(fmt.Sprintf("delay %v", delay), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			calls := 0
+			fetch := func(ctx context.Context, url string) error {
+				calls++
+				// Cancel the warming during the first batch
+				cancel()
+				return nil
+			}
+			done := make(chan struct{})
+			var fetched int
+			var err error
+			go func() {
+				defer close(done)
+				fetched, err = Warm(ctx, testURLs(5), WarmConfig{BatchSize: 1, Delay: delay}, fetch)
+			}()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Warm did not stop after the cancellation")
+			}
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("err = %v, want %v", err, context.Canceled)
+			}
+			if calls != 1 || fetched != 1 {
+				t.Errorf("fetched %v of %v URLs after the cancellation, want only the first batch", fetched, calls)
+			}
+		})
+	}
+}
+
+func TestWarmContinuesAfterFailures(t *testing.T) {
+	fetch := func(ctx context.Context, url string) error {
+		if url == "/v1/pokemon/2" {
+			return errors.New("handler failed")
+		}
+		return nil
+	}
+	fetched, err := Warm(context.Background(), testURLs(4), WarmConfig{BatchSize: 2}, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched != 3 {
+		t.Errorf("fetched %v URLs, want 3", fetched)
+	}
+}
+
+func TestWarmReportsProgress(t *testing.T) {
+	fetch := func(ctx context.Context, url string) error {
+		if url == "/v1/pokemon/2" || url == "/v1/pokemon/5" {
+			return errors.New("handler failed")
+		}
+		return nil
+	}
+	type progress struct{ processed, failed int }
+	var reported []progress
+	config := WarmConfig{BatchSize: 2, Progress: func(processed int, failed int) {
+		reported = append(reported, progress{processed, failed})
+	}}
+	if _, err := Warm(context.Background(), testURLs(5), config, fetch); err != nil {
+		t.Fatal(err)
+	}
+	want := []progress{{processed: 2, failed: 1}, {processed: 4, failed: 1}, {processed: 5, failed: 2}}
+	if !reflect.DeepEqual(reported, want) {
+		t.Errorf("reported progress %+v, want %+v", reported, want)
+	}
+}
@@ -8,8 +8,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/janek64/pmd-dx-api/api/debug"
 )
 
 // DBConnectionError- type for database connection error.
@@ -25,6 +28,33 @@ func (e *DBConnectionError) Error() string {
 // dbpool is the global connection pool for the database.
 var dbpool *pgxpool.Pool
 
+// dbquerier executes the queries of the package. It is the connection pool once it is connected.
+var dbquerier querier
+
+// explainQueries enables the diagnostic mode that logs the plan of slow queries.
+var explainQueries bool
+
+// slowQueryThreshold is the duration after which a query is considered slow.
+var slowQueryThreshold = 500 * time.Millisecond
+
+// useMaterializedViews enables reading expensive derived data from materialized views.
+var useMaterializedViews bool
+
+// PokemonQueryMode represents the valid modes for executing the queries of a single pokemon.
+type PokemonQueryMode string
+
+const (
+	// Concurrent runs all queries at once on separate connections of the pool
+	Concurrent = "concurrent"
+	// Sequential runs all queries one after another on a single connection
+	Sequential = "sequential"
+	// Auto runs the queries sequentially if more than half of the pool connections are in use
+	Auto = "auto"
+)
+
+// pokemonQueryMode is the mode used by GetPokemon.
+var pokemonQueryMode PokemonQueryMode = Concurrent
+
 // InitDB connects to the database and sets the connection pool global variable.
 func InitDB() error {
 	// Get connection data from environment
@@ -44,6 +74,33 @@ func InitDB() error {
 	if !ok {
 		return &DBConnectionError{"DB_NAME"}
 	}
+	// Get the optional diagnostic and performance settings from environment
+	// Invalid values are ignored and the defaults are used instead
+	// EXPLAIN logging is a debug feature and always disabled in release builds
+	if value, ok := os.LookupEnv("EXPLAIN_QUERIES"); ok && debug.Enabled {
+		explainQueries, _ = strconv.ParseBool(value)
+	}
+	if value, ok := os.LookupEnv("SLOW_QUERY_THRESHOLD"); ok {
+		if threshold, err := time.ParseDuration(value); err == nil {
+			slowQueryThreshold = threshold
+		}
+	}
+	if value, ok := os.LookupEnv("USE_MATERIALIZED_VIEWS"); ok {
+		useMaterializedViews, _ = strconv.ParseBool(value)
+	}
+	if value, ok := os.LookupEnv("DB_QUERY_TIMEOUT"); ok {
+		if timeout, err := time.ParseDuration(value); err == nil && timeout >= 0 {
+			queryTimeout = timeout
+		}
+	}
+	if value, ok := os.LookupEnv("DATA_VERSION_REFRESH_INTERVAL"); ok {
+		if interval, err := time.ParseDuration(value); err == nil && interval > 0 {
+			dataVersionRefreshInterval = interval
+		}
+	}
+	if value, ok := os.LookupEnv("POKEMON_QUERY_MODE"); ok && (value == Concurrent || value == Sequential || value == Auto) {
+		pokemonQueryMode = PokemonQueryMode(value)
+	}
 
 	// Establish the database connection
 	databaseURL := fmt.Sprintf("postgres://%v:%v@%v/%v", dbuser, dbpassword, dburl, dbname)
@@ -52,8 +109,22 @@ func InitDB() error {
 	if err != nil {
 		return err
 	}
-	// Test the connection pool and return the result
-	return dbpool.Ping(context.Background())
+	// Test the connection pool
+	if err = dbpool.Ping(context.Background()); err != nil {
+		return err
+	}
+	dbquerier = dbpool
+	// Keep the data version of the responses up to date
+	startDataVersionRefresh(dbpool)
+	return nil
+}
+
+// Ping checks if the database can be reached with a connection of the pool.
+func Ping(ctx context.Context) error {
+	if dbpool == nil {
+		return errors.New("database connection not initialized")
+	}
+	return dbpool.Ping(ctx)
 }
 
 // CloseDB closes the connection pool to the database stored in the global variable.
@@ -61,7 +132,12 @@ func CloseDB() error {
 	if dbpool == nil {
 		return errors.New("no connection pool to close")
 	}
+	if stopDataVersionRefresh != nil {
+		close(stopDataVersionRefresh)
+		stopDataVersionRefresh = nil
+	}
 	dbpool.Close()
 	dbpool = nil
+	dbquerier = nil
 	return nil
 }
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/janek64/pmd-dx-api/api/logger"
+)
+
+// SlowQueryError - type for queries exceeding the slow query threshold.
+type SlowQueryError struct {
+	Query    string
+	Duration time.Duration
+	Plan     string
+}
+
+// Error - implementation of the error interface.
+func (e *SlowQueryError) Error() string {
+	return fmt.Sprintf("slow query (%v): %v\n%v", e.Duration, strings.Join(strings.Fields(e.Query), " "), e.Plan)
+}
+
+// querier is implemented by the connection pool and single connections acquired from it.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// query executes a query on the connection pool and returns the resulting rows.
+// In diagnostic mode, the plan of the query is logged if it exceeded the slow query threshold.
+func query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return queryWith(ctx, dbquerier, sql, args...)
+}
+
+// queryWith executes a query with the querier and returns the resulting rows. The query is aborted
+// after the query timeout. In diagnostic mode, the plan of the query is logged if it exceeded the slow query threshold.
+func queryWith(ctx context.Context, q querier, sql string, args ...interface{}) (pgx.Rows, error) {
+	queryCtx, cancel := withQueryTimeout(ctx)
+	start := time.Now()
+	rows, err := q.Query(queryCtx, sql, args...)
+	logIfSlow(time.Since(start), sql, args...)
+	if err != nil {
+		err = wrapContextError(queryCtx, err)
+		cancel()
+		return rows, err
+	}
+	return &timeoutRows{Rows: rows, ctx: queryCtx, cancel: cancel}, nil
+}
+
+// queryRow executes a query that is expected to return at most one row on the connection pool. The query is aborted
+// after the query timeout. In diagnostic mode, the plan of the query is logged if it exceeded the slow query threshold.
+func queryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	queryCtx, cancel := withQueryTimeout(ctx)
+	start := time.Now()
+	row := dbquerier.QueryRow(queryCtx, sql, args...)
+	logIfSlow(time.Since(start), sql, args...)
+	return &timeoutRow{row: row, ctx: queryCtx, cancel: cancel}
+}
+
+// logIfSlow checks if the diagnostic mode is enabled and the query was slow. If so,
+// it runs EXPLAIN for the query in the background and writes the plan to the error log.
+func logIfSlow(duration time.Duration, sql string, args ...interface{}) {
+	if !explainQueries || duration < slowQueryThreshold {
+		return
+	}
+	go func() {
+		plan, err := explain(sql, args...)
+		if err != nil {
+			plan = fmt.Sprintf("EXPLAIN failed: %v", err)
+		}
+		// Log the plan to the error log
+		pc, file, line, ok := runtime.Caller(0)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "logIfSlow: failed to fetch caller information")
+			return
+		}
+		caller := logger.CallerInformation{Pc: pc, File: file, Line: line}
+		logger.LogInfo(&SlowQueryError{Query: sql, Duration: duration, Plan: plan}, caller)
+	}()
+}
+
+// explain runs EXPLAIN for the query with the given arguments and returns the plan.
+func explain(sql string, args ...interface{}) (string, error) {
+	if dbpool == nil {
+		return "", fmt.Errorf("database connection not initialized")
+	}
+	rows, err := dbpool.Query(context.Background(), "EXPLAIN "+sql, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	// Each row of the result contains one line of the plan
+	var plan []string
+	for rows.Next() {
+		var line string
+		err = rows.Scan(&line)
+		if err != nil {
+			return "", err
+		}
+		plan = append(plan, line)
+	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(plan, "\n"), nil
+}
@@ -4,8 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v4"
+	"github.com/janek64/pmd-dx-api/api/logger"
 	"github.com/janek64/pmd-dx-api/api/models"
 	"golang.org/x/sync/errgroup"
 )
@@ -16,6 +22,9 @@ type SearchType string
 const (
 	ID   = "ID"
 	Name = "name"
+	// Prefix and Fuzzy search candidates for a name that did not match exactly
+	Prefix = "prefix"
+	Fuzzy  = "fuzzy"
 )
 
 // SearchInput is the input for a query searching a resource by ID or name.
@@ -29,22 +38,214 @@ type SearchInput struct {
 type SortType string
 
 const (
-	IDAsc    = "id_asc"
-	IDDesc   = "id_desc"
-	NameAsc  = "name_asc"
-	NameDesc = "name_desc"
+	IDAsc       = "id_asc"
+	IDDesc      = "id_desc"
+	NameAsc     = "name_asc"
+	NameDesc    = "name_desc"
+	UpdatedAsc  = "updated_asc"
+	UpdatedDesc = "updated_desc"
+	// Sorting by power, accuracy and PP is only supported by move lists
+	PowerAsc     = "power_asc"
+	PowerDesc    = "power_desc"
+	AccuracyAsc  = "accuracy_asc"
+	AccuracyDesc = "accuracy_desc"
+	PPAsc        = "pp_asc"
+	PPDesc       = "pp_desc"
 )
 
 // SearchInput is an input for resource lists, specifing if a specific sorting is requested.
 type SortInput struct {
-	SortEnabled bool
-	SortType    SortType
+	// SortTypes are applied in their order, later types only sort entries that are equal for the previous ones.
+	// The list is sorted by ID ascending if it is empty.
+	SortTypes []SortType
+}
+
+// sortColumns maps the sort keys supported by all resource lists to their columns,
+// the keys "id" and "name" use the ID and name column of the table.
+var sortColumns = map[string]string{
+	"updated": "updated_at",
+}
+
+// tableSortColumns maps the sort keys only supported by the lists of a table to their columns.
+// Together with sortColumns, it is the allowlist of columns that can be used for sorting.
+var tableSortColumns = map[ListTable]map[string]string{
+	MoveTable: {
+		"power":    "initial_power",
+		"accuracy": "accuracy",
+		"pp":       "initial_pp",
+	},
 }
 
 // SearchInput is an input for resource lists, specifing how many and which results should be queried.
 type Pagination struct {
 	PerPage int
 	Page    int
+	// SkipCount skips the count query, the list functions return a count of -1 instead.
+	// The count is also -1 if the count query failed
+	SkipCount bool
+}
+
+// GroupBy represents the valid dimensions for grouping pokemon counts.
+type GroupBy string
+
+const (
+	GroupByType           = "type"
+	GroupByCamp           = "camp"
+	GroupByEvolutionStage = "evolution_stage"
+)
+
+// MoveCategory represents the valid categories of moves.
+type MoveCategory string
+
+const (
+	Physical = "Physical"
+	Special  = "Special"
+	Status   = "Status"
+)
+
+// LearnType represents the valid ways of pokemon to learn moves.
+type LearnType string
+
+const (
+	LearnByLevel = "level"
+	LearnByTutor = "tutor"
+	LearnByTM    = "tm"
+)
+
+// ListFilter is an input for resource lists, specifing which entries should be included.
+type ListFilter struct {
+	// UpdatedSince only includes entries updated after the timestamp if it is not zero
+	UpdatedSince time.Time
+	// Category only includes moves of the category if it is not empty, ignored for other resources
+	Category MoveCategory
+	// MinPower, MaxPower, MinAccuracy and MaxAccuracy limit the stats of moves
+	// to an inclusive range if they are not nil, ignored for other resources
+	MinPower    *int
+	MaxPower    *int
+	MinAccuracy *int
+	MaxAccuracy *int
+	// LearnType only includes moves that at least one pokemon learns this way if it is not empty, ignored for other resources
+	LearnType LearnType
+	// Type only includes pokemon of the type with the ID or name if it is not nil, ignored for other resources
+	Type *SearchInput
+	// MinEvolveCrystals and MaxEvolveCrystals limit the crystals needed for the evolution of pokemon
+	// to an inclusive range if they are not nil, ignored for other resources
+	MinEvolveCrystals *int
+	MaxEvolveCrystals *int
+	// MaxStartLevel only includes dungeons starting at this level or below if it is not nil, ignored for other resources
+	MaxStartLevel *int
+	// HasPokemon only includes entries with (true) or without (false) any pokemon if it is not nil,
+	// ignored for resources without a subquery in hasPokemonQueries
+	HasPokemon *bool
+}
+
+// hasPokemonQueries contains the subqueries selecting the pokemon of an entry of the table for the HasPokemon filter.
+var hasPokemonQueries = map[ListTable]string{
+	AbilityTable: "SELECT 1 FROM pokemon_has_ability R WHERE R.ability_ID = ability.ability_ID",
+	MoveTable:    "SELECT 1 FROM learns R WHERE R.move_ID = attack_move.move_ID",
+	TypeTable:    "SELECT 1 FROM pokemon_has_type R WHERE R.type_ID = pokemon_type.type_ID",
+}
+
+// ListTable represents the tables of the resource lists.
+type ListTable string
+
+const (
+	AbilityTable ListTable = "ability"
+	CampTable    ListTable = "camp"
+	DungeonTable ListTable = "dungeon"
+	MoveTable    ListTable = "attack_move"
+	PokemonTable ListTable = "pokemon"
+	TypeTable    ListTable = "pokemon_type"
+)
+
+// whereClause collects the conditions and arguments of the WHERE clause for a resource list query.
+type whereClause struct {
+	conditions []string
+	args       []interface{}
+}
+
+// add adds a condition comparing the column to the value with the operator.
+func (w *whereClause) add(column string, operator string, value interface{}) {
+	w.args = append(w.args, value)
+	w.conditions = append(w.conditions, fmt.Sprintf("%v %v $%v", column, operator, len(w.args)))
+}
+
+// addf adds a condition containing a placeholder for the value, e.g. "column IN (SELECT ... WHERE x = %v)".
+func (w *whereClause) addf(format string, value interface{}) {
+	w.args = append(w.args, value)
+	w.conditions = append(w.conditions, fmt.Sprintf(format, fmt.Sprintf("$%v", len(w.args))))
+}
+
+// addCondition adds a condition without a value, e.g. a subquery only referencing columns.
+func (w *whereClause) addCondition(condition string) {
+	w.conditions = append(w.conditions, condition)
+}
+
+// String returns the WHERE clause with all conditions or an empty string if there are none.
+func (w *whereClause) String() string {
+	if len(w.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(w.conditions, " AND ")
+}
+
+// buildWhereClause builds the WHERE clause for the filters of the provided ListFilter.
+// Filters that do not apply to the table are ignored.
+func buildWhereClause(table ListTable, filter ListFilter) whereClause {
+	var where whereClause
+	if !filter.UpdatedSince.IsZero() {
+		where.add("updated_at", ">", filter.UpdatedSince)
+	}
+	if table == MoveTable {
+		if filter.Category != "" {
+			where.add("category", "=", string(filter.Category))
+		}
+		if filter.MinPower != nil {
+			where.add("initial_power", ">=", *filter.MinPower)
+		}
+		if filter.MaxPower != nil {
+			where.add("initial_power", "<=", *filter.MaxPower)
+		}
+		if filter.MinAccuracy != nil {
+			where.add("accuracy", ">=", *filter.MinAccuracy)
+		}
+		if filter.MaxAccuracy != nil {
+			where.add("accuracy", "<=", *filter.MaxAccuracy)
+		}
+		if filter.LearnType != "" {
+			// A subquery keeps each move once even if multiple pokemon learn it
+			where.addf("move_ID IN (SELECT move_ID FROM learns WHERE learn_type = %v)", string(filter.LearnType))
+		}
+	}
+	if subquery, ok := hasPokemonQueries[table]; ok && filter.HasPokemon != nil {
+		if *filter.HasPokemon {
+			where.addCondition(fmt.Sprintf("EXISTS (%v)", subquery))
+		} else {
+			where.addCondition(fmt.Sprintf("NOT EXISTS (%v)", subquery))
+		}
+	}
+	if table == DungeonTable && filter.MaxStartLevel != nil {
+		// Dungeons without a start level are excluded since the comparison with NULL is never true
+		where.add("start_level", "<=", *filter.MaxStartLevel)
+	}
+	if table == PokemonTable {
+		// Pokemon that do not evolve with crystals are excluded since the comparison with NULL is never true
+		if filter.MinEvolveCrystals != nil {
+			where.add("evolve_crystals", ">=", *filter.MinEvolveCrystals)
+		}
+		if filter.MaxEvolveCrystals != nil {
+			where.add("evolve_crystals", "<=", *filter.MaxEvolveCrystals)
+		}
+	}
+	if table == PokemonTable && filter.Type != nil {
+		if filter.Type.SearchType == ID {
+			where.addf("dex_number IN (SELECT dex_number FROM pokemon_has_type WHERE type_ID = %v)", filter.Type.ID)
+		} else {
+			where.addf(`dex_number IN (SELECT PT.dex_number FROM pokemon_has_type PT
+			JOIN pokemon_type T ON PT.type_ID = T.type_ID WHERE T.type_name = %v)`, filter.Type.Name)
+		}
+	}
+	return where
 }
 
 // ResourceNotFoundError - error if a requested resource was not found.
@@ -66,49 +267,323 @@ func (e *ResourceNotFoundError) Error() string {
 	}
 }
 
-// buildQuery builds the complete query for the provided values. It checks if the provided SortInput requires
-// any sorting and returns a modified query that sorts by idColumn or nameColumn if required. It also adds
-// LIMIT and OFFSET based on the given Pagination object.
-func buildQuery(query string, sort SortInput, idColumn string, nameColumn string, pagination Pagination) string {
-	// Set default ordering to ID ascending
-	sortQuery := fmt.Sprintf("ORDER BY %v ASC", idColumn)
-	// Check if any sorting is required and switch for the sorting type
-	if sort.SortEnabled {
-		switch sort.SortType {
-		case IDDesc:
-			sortQuery = fmt.Sprintf("ORDER BY %v DESC", idColumn)
-		case NameAsc:
-			sortQuery = fmt.Sprintf("ORDER BY %v ASC", nameColumn)
-		case NameDesc:
-			sortQuery = fmt.Sprintf("ORDER BY %v DESC", nameColumn)
+// buildOrderBy builds the ORDER BY clause for the SortTypes of the SortInput. Each SortType consists of a sort key
+// and the direction, e.g. "power_desc". Only columns of the allowlist of the table are used and invalid or repeated
+// keys are ignored. The ID is always the last column, so entries with equal values have a stable order.
+func buildOrderBy(sort SortInput, table ListTable, idColumn string, nameColumn string) string {
+	var orderings []string
+	used := make(map[string]bool)
+	for _, sortType := range sort.SortTypes {
+		separator := strings.LastIndex(string(sortType), "_")
+		if separator < 0 {
+			continue
 		}
+		key, direction := string(sortType[:separator]), strings.ToUpper(string(sortType[separator+1:]))
+		if direction != "ASC" && direction != "DESC" {
+			continue
+		}
+		var column string
+		switch key {
+		case "id":
+			column = idColumn
+		case "name":
+			column = nameColumn
+		default:
+			var ok bool
+			if column, ok = sortColumns[key]; !ok {
+				if column, ok = tableSortColumns[table][key]; !ok {
+					continue
+				}
+			}
+		}
+		if used[column] {
+			continue
+		}
+		used[column] = true
+		orderings = append(orderings, fmt.Sprintf("%v %v", column, direction))
 	}
+	if !used[idColumn] {
+		orderings = append(orderings, fmt.Sprintf("%v ASC", idColumn))
+	}
+	return "ORDER BY " + strings.Join(orderings, ", ")
+}
+
+// buildQuery builds the complete query for the provided values. It adds the conditions of the whereClause and
+// the ORDER BY clause for the SortInput, which sorts by ID ascending by default. It also adds LIMIT and OFFSET
+// based on the given Pagination object.
+func buildQuery(query string, where whereClause, sort SortInput, table ListTable, idColumn string, nameColumn string, pagination Pagination) string {
+	sortQuery := buildOrderBy(sort, table, idColumn, nameColumn)
 	limitQuery := fmt.Sprintf("LIMIT %v OFFSET %v", pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if len(where.conditions) > 0 {
+		query = fmt.Sprintf("%v %v", query, where.String())
+	}
 	return fmt.Sprintf("%v %v %v;", query, sortQuery, limitQuery)
 }
 
-// getCount queries the COUNT(*) for the given table and returns it as an int.
-func getCount(table string) (int, error) {
-	if dbpool == nil {
+// getCount queries the COUNT(*) for the given table with the conditions of the whereClause and returns it as an int.
+func getCount(ctx context.Context, table string, where whereClause) (int, error) {
+	if dbquerier == nil {
 		return 0, errors.New("database connection not initialized")
 	}
 	var count int
 	queryString := fmt.Sprintf("SELECT COUNT(*) AS count FROM %v;", table)
-	err := dbpool.QueryRow(context.Background(), queryString).Scan(&count)
+	if len(where.conditions) > 0 {
+		queryString = fmt.Sprintf("SELECT COUNT(*) AS count FROM %v %v;", table, where.String())
+	}
+	err := queryRow(ctx, queryString, where.args...).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
+// getCountOrUnknown queries the count like getCount for a list that was already fetched successfully.
+// A failing count does not invalidate the list, so the error is only logged and a count of -1 is returned.
+func getCountOrUnknown(ctx context.Context, table string, where whereClause) int {
+	count, err := getCount(ctx, table, where)
+	if err != nil {
+		pc, file, line, ok := runtime.Caller(0)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "getCountOrUnknown: failed to fetch caller information")
+			return -1
+		}
+		caller := logger.CallerInformation{Pc: pc, File: file, Line: line}
+		logger.LogWarning(fmt.Errorf("count of the list failed, returning the list without it: %w", err), caller)
+		return -1
+	}
+	return count
+}
+
+// GetListCount fetches the number of entries of the resource list table matching the ListFilter.
+// Uses the same conditions as the list queries, so the count always matches the full list.
+func GetListCount(ctx context.Context, table ListTable, filter ListFilter) (int, error) {
+	return getCount(ctx, string(table), buildWhereClause(table, filter))
+}
+
+// searchTables defines the tables searched by SearchAll with the resource type name used for their URLs.
+var searchTables = []struct {
+	resourceType string
+	table        ListTable
+	idColumn     string
+	nameColumn   string
+}{
+	{"pokemon", "pokemon", "dex_number", "pokemon_name"},
+	{"moves", "attack_move", "move_ID", "move_name"},
+	{"abilities", "ability", "ability_ID", "ability_name"},
+	{"dungeons", "dungeon", "dungeon_ID", "dungeon_name"},
+	{"camps", "camp", "camp_ID", "camp_name"},
+	{"types", "pokemon_type", "type_ID", "type_name"},
+}
+
+// reverseCounts defines the COUNT queries of the reverse relationships of each table. The
+// placeholder of each query is replaced with the expression selecting the ID of the resource.
+var reverseCounts = map[ListTable][]struct {
+	name  string
+	query string
+}{
+	AbilityTable: {{"pokemonCount", "SELECT COUNT(*) FROM pokemon_has_ability WHERE ability_ID = %v"}},
+	CampTable:    {{"pokemonCount", "SELECT COUNT(*) FROM pokemon WHERE camp_id = %v"}},
+	DungeonTable: {{"pokemonCount", "SELECT COUNT(*) FROM encountered_in WHERE dungeon_ID = %v"}},
+	MoveTable:    {{"pokemonCount", "SELECT COUNT(DISTINCT dex_number) FROM learns WHERE move_ID = %v"}},
+	TypeTable: {
+		{"moveCount", "SELECT COUNT(*) FROM attack_move WHERE type_ID = %v"},
+		{"pokemonCount", "SELECT COUNT(*) FROM pokemon_has_type WHERE type_ID = %v"},
+	},
+}
+
+// GetReverseCounts fetches the number of resources referencing the resource of the table with the
+// ID or name through each of its reverse relationships. The counts are queried concurrently and
+// independently of the resource itself, so they are all 0 if the resource does not exist.
+func GetReverseCounts(ctx context.Context, table ListTable, input SearchInput) ([]models.RelationshipCount, error) {
+	if dbquerier == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+	var idColumn, nameColumn string
+	for _, t := range searchTables {
+		if t.table == table {
+			idColumn, nameColumn = t.idColumn, t.nameColumn
+		}
+	}
+	relationships, ok := reverseCounts[table]
+	if !ok || idColumn == "" {
+		return nil, fmt.Errorf("illegal table %v", table)
+	}
+	// Select the ID directly or by the name of the resource
+	var resourceID string
+	var arg interface{}
+	switch input.SearchType {
+	case ID:
+		resourceID, arg = "$1", input.ID
+	case Name:
+		resourceID, arg = fmt.Sprintf("(SELECT %v FROM %v WHERE %v = $1)", idColumn, table, nameColumn), input.Name
+	default:
+		return nil, fmt.Errorf("illegal search type %v", input.SearchType)
+	}
+	counts := make([]models.RelationshipCount, len(relationships))
+	g, groupCtx := errgroup.WithContext(ctx)
+	for i, relationship := range relationships {
+		i, relationship := i, relationship
+		counts[i].Name = relationship.name
+		g.Go(func() error {
+			return queryRow(groupCtx, fmt.Sprintf(relationship.query, resourceID), arg).Scan(&counts[i].Count)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// escapeLikePattern escapes the wildcards of LIKE in the term.
+func escapeLikePattern(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
+
+// maxCandidates is the maximum number of candidates returned by GetCandidates.
+const maxCandidates = 10
+
+// GetCandidates fetches the resources of the table whose name is similar to the name of the SearchInput,
+// ignoring the case. SearchType Prefix returns the names starting with the name in alphabetical order.
+// SearchType Fuzzy returns the names with a Levenshtein distance of at most a third of the name's length
+// (at least 1), ordered by their distance. At most maxCandidates resources are returned.
+func GetCandidates(ctx context.Context, table ListTable, input SearchInput) ([]models.NamedResourceID, error) {
+	if dbquerier == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+	var idColumn, nameColumn string
+	for _, t := range searchTables {
+		if t.table == table {
+			idColumn, nameColumn = t.idColumn, t.nameColumn
+		}
+	}
+	if idColumn == "" {
+		return nil, fmt.Errorf("illegal table %v", table)
+	}
+	switch input.SearchType {
+	case Prefix:
+		queryString := fmt.Sprintf("SELECT %[1]v, %[2]v FROM %[3]v WHERE %[2]v ILIKE $1 || '%%' ORDER BY %[2]v ASC LIMIT %[4]v;", idColumn, nameColumn, table, maxCandidates)
+		rows, err := query(ctx, queryString, escapeLikePattern(input.Name))
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		return readNamedResourceRows(rows)
+	case Fuzzy:
+		// The tables are small enough to compare the distance to all names on application level,
+		// which avoids depending on the fuzzystrmatch extension
+		rows, err := query(ctx, fmt.Sprintf("SELECT %v, %v FROM %v;", idColumn, nameColumn, table))
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		resources, err := readNamedResourceRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.ToLower(input.Name)
+		maxDistance := len([]rune(name)) / 3
+		if maxDistance < 1 {
+			maxDistance = 1
+		}
+		var candidates []models.NamedResourceID
+		distances := make(map[int]int)
+		for _, r := range resources {
+			if d := levenshtein(name, strings.ToLower(r.Name)); d <= maxDistance {
+				candidates = append(candidates, r)
+				distances[r.ID] = d
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return distances[candidates[i].ID] < distances[candidates[j].ID]
+		})
+		if len(candidates) > maxCandidates {
+			candidates = candidates[:maxCandidates]
+		}
+		return candidates, nil
+	default:
+		return nil, fmt.Errorf("illegal search type %v", input.SearchType)
+	}
+}
+
+// levenshtein calculates the Levenshtein distance between the two strings.
+func levenshtein(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// Only keep the previous and the current row of the distance matrix
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// min3 returns the smallest of the three integers.
+func min3(a int, b int, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// SearchAll fetches the resources of all types whose name contains the term, ignoring the case.
+// Names starting with the term are returned first. The Pagination is applied to each resource type.
+func SearchAll(ctx context.Context, term string, pagination Pagination) ([]models.SearchResultID, error) {
+	if dbquerier == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+	pattern := escapeLikePattern(term)
+	results := make([]models.SearchResultID, len(searchTables))
+	// Query all tables concurrently, each goroutine only writes its own result
+	errs, ctx := errgroup.WithContext(ctx)
+	for i, t := range searchTables {
+		i, t := i, t
+		errs.Go(func() error {
+			queryString := fmt.Sprintf(`SELECT %[1]v, %[2]v FROM %[3]v WHERE %[2]v ILIKE '%%' || $1 || '%%'
+			ORDER BY %[2]v ILIKE $1 || '%%' DESC, %[2]v ASC LIMIT %[4]v OFFSET %[5]v;`,
+				t.idColumn, t.nameColumn, t.table, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+			rows, err := query(ctx, queryString, pattern)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			resources, err := readNamedResourceRows(rows)
+			if err != nil {
+				return err
+			}
+			results[i] = models.SearchResultID{ResourceType: t.resourceType, Resources: resources}
+			return nil
+		})
+	}
+	// Wait for all Goroutines and check for any errors
+	if err := errs.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 // GetAbilityList fetches a slice of all ability entries from the database.
-func GetAbilityList(sort SortInput, pagination Pagination) (int, []models.NamedResourceID, error) {
-	if dbpool == nil {
+func GetAbilityList(ctx context.Context, sort SortInput, pagination Pagination, filter ListFilter) (int, []models.NamedResourceID, error) {
+	if dbquerier == nil {
 		return 0, nil, errors.New("database connection not initialized")
 	}
 	var abilities []models.NamedResourceID
-	queryString := buildQuery("SELECT ability_ID, ability_name FROM ability", sort, "ability_ID", "ability_name", pagination)
-	rows, err := dbpool.Query(context.Background(), queryString)
+	where := buildWhereClause(AbilityTable, filter)
+	queryString := buildQuery("SELECT ability_ID, ability_name FROM ability", where, sort, AbilityTable, "ability_ID", "ability_name", pagination)
+	rows, err := query(ctx, queryString, where.args...)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -122,33 +597,36 @@ func GetAbilityList(sort SortInput, pagination Pagination) (int, []models.NamedR
 		}
 		abilities = append(abilities, ability)
 	}
-	// Get the total count
-	count, err := getCount("ability")
-	if err != nil {
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
 		return 0, nil, err
 	}
-	return count, abilities, nil
+	// Get the total count unless it is skipped
+	if pagination.SkipCount {
+		return -1, abilities, nil
+	}
+	return getCountOrUnknown(ctx, "ability", where), abilities, nil
 }
 
 // GetAbility fetches an ability entry and all pokemon that have it from the database by its ID or name.
-func GetAbility(input SearchInput) (ability models.Ability, pokemon []models.NamedResourceID, err error) {
-	if dbpool == nil {
+func GetAbility(ctx context.Context, input SearchInput) (ability models.Ability, pokemon []models.NamedResourceID, err error) {
+	if dbquerier == nil {
 		return ability, nil, errors.New("database connection not initialized")
 	}
 	var rows pgx.Rows
 	// Use different query depending on search type
 	if input.SearchType == ID {
-		queryString := `SELECT A.*, P.dex_number, P.pokemon_name
+		queryString := `SELECT A.ability_ID, A.ability_name, A.description, P.dex_number, P.pokemon_name
 		FROM (SELECT * FROM ability WHERE ability_ID = $1) A
 		LEFT JOIN pokemon_has_ability PA ON A.ability_ID = PA.ability_ID
 		LEFT JOIN pokemon P on PA.dex_number = P.dex_number ORDER BY P.dex_number ASC;`
-		rows, err = dbpool.Query(context.Background(), queryString, input.ID)
+		rows, err = query(ctx, queryString, input.ID)
 	} else if input.SearchType == Name {
-		queryString := `SELECT A.*, P.dex_number, P.pokemon_name
+		queryString := `SELECT A.ability_ID, A.ability_name, A.description, P.dex_number, P.pokemon_name
 		FROM (SELECT * FROM ability WHERE ability_name = $1) A
 		LEFT JOIN pokemon_has_ability PA ON A.ability_ID = PA.ability_ID
 		LEFT JOIN pokemon P on PA.dex_number = P.dex_number ORDER BY P.dex_number ASC;`
-		rows, err = dbpool.Query(context.Background(), queryString, input.Name)
+		rows, err = query(ctx, queryString, input.Name)
 	} else {
 		return ability, nil, fmt.Errorf("illegal search type %v", input.SearchType)
 	}
@@ -176,6 +654,10 @@ func GetAbility(input SearchInput) (ability models.Ability, pokemon []models.Nam
 		// Checking for ID==0 is not necessary since all rows after the first will not have null values
 		pokemon = append(pokemon, p)
 	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return ability, nil, err
+	}
 	// If the AbilityID is zero, no entry was found
 	if ability.AbilityID == 0 {
 		if input.SearchType == ID {
@@ -188,13 +670,14 @@ func GetAbility(input SearchInput) (ability models.Ability, pokemon []models.Nam
 }
 
 // GetCampList fetches a slice of all camp entries from the database.
-func GetCampList(sort SortInput, pagination Pagination) (int, []models.NamedResourceID, error) {
-	if dbpool == nil {
+func GetCampList(ctx context.Context, sort SortInput, pagination Pagination, filter ListFilter) (int, []models.NamedResourceID, error) {
+	if dbquerier == nil {
 		return 0, nil, errors.New("database connection not initialized")
 	}
 	var camps []models.NamedResourceID
-	queryString := buildQuery("SELECT camp_ID, camp_name FROM camp", sort, "camp_ID", "camp_name", pagination)
-	rows, err := dbpool.Query(context.Background(), queryString)
+	where := buildWhereClause(CampTable, filter)
+	queryString := buildQuery("SELECT camp_ID, camp_name FROM camp", where, sort, CampTable, "camp_ID", "camp_name", pagination)
+	rows, err := query(ctx, queryString, where.args...)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -208,31 +691,34 @@ func GetCampList(sort SortInput, pagination Pagination) (int, []models.NamedReso
 		}
 		camps = append(camps, camp)
 	}
-	// Get the total count
-	count, err := getCount("camp")
-	if err != nil {
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
 		return 0, nil, err
 	}
-	return count, camps, nil
+	// Get the total count unless it is skipped
+	if pagination.SkipCount {
+		return -1, camps, nil
+	}
+	return getCountOrUnknown(ctx, "camp", where), camps, nil
 }
 
 // GetCamp fetches a camp entry and all pokemon living in it from the database by its ID or name.
-func GetCamp(input SearchInput) (camp models.Camp, pokemon []models.NamedResourceID, err error) {
-	if dbpool == nil {
+func GetCamp(ctx context.Context, input SearchInput) (camp models.Camp, pokemon []models.NamedResourceID, err error) {
+	if dbquerier == nil {
 		return camp, nil, errors.New("database connection not initialized")
 	}
 	var rows pgx.Rows
 	// Use different query depending on search type
 	if input.SearchType == ID {
-		queryString := `SELECT C.*, P.dex_number, P.pokemon_name
+		queryString := `SELECT C.camp_ID, C.camp_name, C.unlock_type, C.cost, C.description, P.dex_number, P.pokemon_name
 		FROM (SELECT * FROM camp WHERE camp_ID = $1) C
 		LEFT JOIN pokemon P ON C.camp_ID = P.camp_ID ORDER BY P.dex_number ASC;`
-		rows, err = dbpool.Query(context.Background(), queryString, input.ID)
+		rows, err = query(ctx, queryString, input.ID)
 	} else if input.SearchType == Name {
-		queryString := `SELECT C.*, P.dex_number, P.pokemon_name
+		queryString := `SELECT C.camp_ID, C.camp_name, C.unlock_type, C.cost, C.description, P.dex_number, P.pokemon_name
 		FROM (SELECT * FROM camp WHERE camp_name = $1) C
 		LEFT JOIN pokemon P ON C.camp_ID = P.camp_ID ORDER BY P.dex_number ASC;`
-		rows, err = dbpool.Query(context.Background(), queryString, input.Name)
+		rows, err = query(ctx, queryString, input.Name)
 	} else {
 		return camp, nil, fmt.Errorf("illegal search type %v", input.SearchType)
 	}
@@ -260,6 +746,10 @@ func GetCamp(input SearchInput) (camp models.Camp, pokemon []models.NamedResourc
 		// Checking for ID==0 is not necessary since all rows after the first will not have null values
 		pokemon = append(pokemon, p)
 	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return camp, nil, err
+	}
 	// If the CampID is zero, no entry was found
 	if camp.CampID == 0 {
 		if input.SearchType == ID {
@@ -272,13 +762,14 @@ func GetCamp(input SearchInput) (camp models.Camp, pokemon []models.NamedResourc
 }
 
 // GetDungeonList fetches a slice of all dungeon entries from the database.
-func GetDungeonList(sort SortInput, pagination Pagination) (int, []models.NamedResourceID, error) {
-	if dbpool == nil {
+func GetDungeonList(ctx context.Context, sort SortInput, pagination Pagination, filter ListFilter) (int, []models.NamedResourceID, error) {
+	if dbquerier == nil {
 		return 0, nil, errors.New("database connection not initialized")
 	}
 	var dungeons []models.NamedResourceID
-	queryString := buildQuery("SELECT dungeon_ID, dungeon_name FROM dungeon", sort, "dungeon_ID", "dungeon_name", pagination)
-	rows, err := dbpool.Query(context.Background(), queryString)
+	where := buildWhereClause(DungeonTable, filter)
+	queryString := buildQuery("SELECT dungeon_ID, dungeon_name FROM dungeon", where, sort, DungeonTable, "dungeon_ID", "dungeon_name", pagination)
+	rows, err := query(ctx, queryString, where.args...)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -292,33 +783,38 @@ func GetDungeonList(sort SortInput, pagination Pagination) (int, []models.NamedR
 		}
 		dungeons = append(dungeons, dungeon)
 	}
-	// Get the total count
-	count, err := getCount("dungeon")
-	if err != nil {
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
 		return 0, nil, err
 	}
-	return count, dungeons, nil
+	// Get the total count unless it is skipped
+	if pagination.SkipCount {
+		return -1, dungeons, nil
+	}
+	return getCountOrUnknown(ctx, "dungeon", where), dungeons, nil
 }
 
 // GetDungeon fetches a dungeon entry and all pokemon encountered in it from the database by its ID or name.
-func GetDungeon(input SearchInput) (dungeon models.Dungeon, pokemon []models.DungeonPokemonID, err error) {
-	if dbpool == nil {
+func GetDungeon(ctx context.Context, input SearchInput) (dungeon models.Dungeon, pokemon []models.DungeonPokemonID, err error) {
+	if dbquerier == nil {
 		return dungeon, nil, errors.New("database connection not initialized")
 	}
 	var rows pgx.Rows
 	// Use different query depending on search type
 	if input.SearchType == ID {
-		queryString := `SELECT D.*, DP.super_enemy, P.dex_number, P.pokemon_name
+		queryString := `SELECT D.dungeon_ID, D.dungeon_name, D.levels, D.start_level, D.team_size, D.items_allowed,
+		D.pokemon_joining, D.map_visible, DP.super_enemy, P.dex_number, P.pokemon_name
 		FROM (SELECT * FROM dungeon WHERE dungeon_ID = $1) D
 		LEFT JOIN encountered_in DP ON D.dungeon_ID = DP.dungeon_ID
 		LEFT JOIN pokemon P ON DP.dex_number = P.dex_number ORDER BY P.dex_number ASC;`
-		rows, err = dbpool.Query(context.Background(), queryString, input.ID)
+		rows, err = query(ctx, queryString, input.ID)
 	} else if input.SearchType == Name {
-		queryString := `SELECT D.*, DP.super_enemy, P.dex_number, P.pokemon_name
+		queryString := `SELECT D.dungeon_ID, D.dungeon_name, D.levels, D.start_level, D.team_size, D.items_allowed,
+		D.pokemon_joining, D.map_visible, DP.super_enemy, P.dex_number, P.pokemon_name
 		FROM (SELECT * FROM dungeon WHERE dungeon_name = $1) D
 		LEFT JOIN encountered_in DP ON D.dungeon_ID = DP.dungeon_ID
 		LEFT JOIN pokemon P ON DP.dex_number = P.dex_number ORDER BY P.dex_number ASC;`
-		rows, err = dbpool.Query(context.Background(), queryString, input.Name)
+		rows, err = query(ctx, queryString, input.Name)
 	} else {
 		return dungeon, nil, fmt.Errorf("illegal search type %v", input.SearchType)
 	}
@@ -346,6 +842,10 @@ func GetDungeon(input SearchInput) (dungeon models.Dungeon, pokemon []models.Dun
 		// Checking for ID==0 is not necessary since all rows after the first will not have null values
 		pokemon = append(pokemon, p)
 	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return dungeon, nil, err
+	}
 	// If the DungeonID is zero, no entry was found
 	if dungeon.DungeonID == 0 {
 		if input.SearchType == ID {
@@ -358,13 +858,14 @@ func GetDungeon(input SearchInput) (dungeon models.Dungeon, pokemon []models.Dun
 }
 
 // GetMoveList fetches a slice of all attack_move entries from the database.
-func GetMoveList(sort SortInput, pagination Pagination) (int, []models.NamedResourceID, error) {
-	if dbpool == nil {
+func GetMoveList(ctx context.Context, sort SortInput, pagination Pagination, filter ListFilter) (int, []models.NamedResourceID, error) {
+	if dbquerier == nil {
 		return 0, nil, errors.New("database connection not initialized")
 	}
 	var moves []models.NamedResourceID
-	queryString := buildQuery("SELECT move_ID, move_name FROM attack_move", sort, "move_ID", "move_name", pagination)
-	rows, err := dbpool.Query(context.Background(), queryString)
+	where := buildWhereClause(MoveTable, filter)
+	queryString := buildQuery("SELECT move_ID, move_name FROM attack_move", where, sort, MoveTable, "move_ID", "move_name", pagination)
+	rows, err := query(ctx, queryString, where.args...)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -378,35 +879,80 @@ func GetMoveList(sort SortInput, pagination Pagination) (int, []models.NamedReso
 		}
 		moves = append(moves, move)
 	}
-	// Get the total count
-	count, err := getCount("attack_move")
-	if err != nil {
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
 		return 0, nil, err
 	}
-	return count, moves, nil
+	// Get the total count unless it is skipped
+	if pagination.SkipCount {
+		return -1, moves, nil
+	}
+	return getCountOrUnknown(ctx, "attack_move", where), moves, nil
+}
+
+// GetMovesByType fetches all types with the moves of each type from the database.
+// Types without any moves are included with an empty slice.
+func GetMovesByType(ctx context.Context) ([]models.TypeMovesID, error) {
+	if dbquerier == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+	queryString := `SELECT T.type_ID, T.type_name, M.move_ID, M.move_name FROM pokemon_type T
+	LEFT JOIN attack_move M ON M.type_ID = T.type_ID ORDER BY T.type_ID ASC, M.move_ID ASC;`
+	rows, err := query(ctx, queryString)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var types []models.TypeMovesID
+	for rows.Next() {
+		var pokemonType models.NamedResourceID
+		// Types without moves have null values for the move
+		var moveID *int
+		var moveName *string
+		err = rows.Scan(&pokemonType.ID, &pokemonType.Name, &moveID, &moveName)
+		if err != nil {
+			return nil, err
+		}
+		// Rows are ordered by the type, so only the last entry needs to be checked
+		last := len(types) - 1
+		if last < 0 || types[last].Type.ID != pokemonType.ID {
+			types = append(types, models.TypeMovesID{Type: pokemonType, Moves: []models.NamedResourceID{}})
+			last++
+		}
+		if moveID != nil {
+			types[last].Moves = append(types[last].Moves, models.NamedResourceID{ID: *moveID, Name: *moveName})
+		}
+	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return types, nil
 }
 
 // GetMove fetches a move entry, its type and all pokemon learning it from the database by its ID or name.
-func GetMove(input SearchInput) (move models.AttackMove, moveType models.NamedResourceID, pokemon []models.MovePokemonID, err error) {
-	if dbpool == nil {
+func GetMove(ctx context.Context, input SearchInput) (move models.AttackMove, moveType models.NamedResourceID, pokemon []models.MovePokemonID, err error) {
+	if dbquerier == nil {
 		return move, moveType, nil, errors.New("database connection not initialized")
 	}
 	var rows pgx.Rows
 	// Use different query depending on search type
 	if input.SearchType == ID {
-		queryString := `SELECT M.*, T.type_name, MP.learn_type, MP.cost, MP.level,
+		queryString := `SELECT M.move_ID, M.move_name, M.category, M.move_range, M.target, M.initial_pp,
+		M.initial_power, M.accuracy, M.description, M.type_ID, T.type_name, MP.learn_type, MP.cost, MP.level,
 		P.dex_number, P.pokemon_name FROM attack_move M
 		INNER JOIN pokemon_type T ON M.move_ID = $1 AND M.type_ID = T.type_ID
 		LEFT JOIN learns MP ON MP.move_ID = M.move_ID
 		LEFT JOIN pokemon P ON MP.dex_number = P.dex_number ORDER BY P.dex_number ASC;`
-		rows, err = dbpool.Query(context.Background(), queryString, input.ID)
+		rows, err = query(ctx, queryString, input.ID)
 	} else if input.SearchType == Name {
-		queryString := `SELECT M.*, T.type_name, MP.learn_type, MP.cost, MP.level,
+		queryString := `SELECT M.move_ID, M.move_name, M.category, M.move_range, M.target, M.initial_pp,
+		M.initial_power, M.accuracy, M.description, M.type_ID, T.type_name, MP.learn_type, MP.cost, MP.level,
 		P.dex_number, P.pokemon_name FROM attack_move M
 		INNER JOIN pokemon_type T ON M.move_name = $1 AND M.type_ID = T.type_ID
 		LEFT JOIN learns MP ON MP.move_ID = M.move_ID
 		LEFT JOIN pokemon P ON MP.dex_number = P.dex_number ORDER BY P.dex_number ASC;`
-		rows, err = dbpool.Query(context.Background(), queryString, input.Name)
+		rows, err = query(ctx, queryString, input.Name)
 	} else {
 		return move, moveType, nil, fmt.Errorf("illegal search type %v", input.SearchType)
 	}
@@ -435,6 +981,10 @@ func GetMove(input SearchInput) (move models.AttackMove, moveType models.NamedRe
 		// Checking for ID==0 is not necessary since all rows after the first will not have null values
 		pokemon = append(pokemon, p)
 	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return move, moveType, nil, err
+	}
 	// If the MoveID is zero, no entry was found
 	if move.MoveID == 0 {
 		if input.SearchType == ID {
@@ -447,13 +997,14 @@ func GetMove(input SearchInput) (move models.AttackMove, moveType models.NamedRe
 }
 
 // GetPokemonList fetches a slice of all pokemon entries from the database.
-func GetPokemonList(sort SortInput, pagination Pagination) (int, []models.NamedResourceID, error) {
-	if dbpool == nil {
+func GetPokemonList(ctx context.Context, sort SortInput, pagination Pagination, filter ListFilter) (int, []models.NamedResourceID, error) {
+	if dbquerier == nil {
 		return 0, nil, errors.New("database connection not initialized")
 	}
 	var pokemonList []models.NamedResourceID
-	queryString := buildQuery("SELECT dex_number, pokemon_name FROM pokemon", sort, "dex_number", "pokemon_name", pagination)
-	rows, err := dbpool.Query(context.Background(), queryString)
+	where := buildWhereClause(PokemonTable, filter)
+	queryString := buildQuery("SELECT dex_number, pokemon_name FROM pokemon", where, sort, PokemonTable, "dex_number", "pokemon_name", pagination)
+	rows, err := query(ctx, queryString, where.args...)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -467,177 +1018,567 @@ func GetPokemonList(sort SortInput, pagination Pagination) (int, []models.NamedR
 		}
 		pokemonList = append(pokemonList, pokemon)
 	}
-	// Get the total count
-	count, err := getCount("pokemon")
-	if err != nil {
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
 		return 0, nil, err
 	}
-	return count, pokemonList, nil
+	// Get the total count unless it is skipped
+	if pagination.SkipCount {
+		return -1, pokemonList, nil
+	}
+	return getCountOrUnknown(ctx, "pokemon", where), pokemonList, nil
 }
 
-// GetMove fetches a move entry, its type and all pokemon learning it from the database by its ID or name.
-func GetPokemon(input SearchInput) (pokemon models.Pokemon, camp models.NamedResourceID, abilities []models.NamedResourceID, dungeons []models.PokemonDungeonID, moves []models.PokemonMoveID, types []models.NamedResourceID, err error) {
-	if dbpool == nil {
+// GetPokemonByNames fetches the pokemon entries with the provided names from the database.
+// Names without a matching pokemon are skipped.
+func GetPokemonByNames(ctx context.Context, names []string) ([]models.NamedResourceID, error) {
+	if dbquerier == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+	var pokemonList []models.NamedResourceID
+	rows, err := query(ctx, "SELECT dex_number, pokemon_name FROM pokemon WHERE pokemon_name = ANY($1) ORDER BY dex_number ASC;", names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	// Add all pokemon found to the slice
+	for rows.Next() {
+		var pokemon models.NamedResourceID
+		err = rows.Scan(&pokemon.ID, &pokemon.Name)
+		if err != nil {
+			return nil, err
+		}
+		pokemonList = append(pokemonList, pokemon)
+	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return pokemonList, nil
+}
+
+// GetPokemonForms fetches all forms of the pokemon species. Forms are stored with the form in
+// parentheses after the name of the species, e.g. "Deoxys (Attack)". Returns the forms ordered by dex number.
+func GetPokemonForms(ctx context.Context, species string) ([]models.NamedResourceID, error) {
+	if dbquerier == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+	var forms []models.NamedResourceID
+	rows, err := query(ctx, `SELECT dex_number, pokemon_name FROM pokemon WHERE pokemon_name LIKE $1 || ' (%' ORDER BY dex_number ASC;`, escapeLikePattern(species))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	// Add all forms found to the slice
+	for rows.Next() {
+		var form models.NamedResourceID
+		err = rows.Scan(&form.ID, &form.Name)
+		if err != nil {
+			return nil, err
+		}
+		forms = append(forms, form)
+	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return forms, nil
+}
+
+// GetTypesOfPokemon fetches the types of all pokemon with the provided dex numbers with a single query.
+// Returns the types ordered by their ID, using the dex number of the pokemon as the key.
+func GetTypesOfPokemon(ctx context.Context, dexNumbers []int) (map[int][]models.NamedResourceID, error) {
+	if dbquerier == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+	queryString := `SELECT PT.dex_number, T.type_ID, T.type_name FROM pokemon_has_type PT
+	INNER JOIN pokemon_type T ON PT.type_ID = T.type_ID WHERE PT.dex_number = ANY($1) ORDER BY T.type_ID ASC;`
+	rows, err := query(ctx, queryString, dexNumbers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	types := make(map[int][]models.NamedResourceID)
+	for rows.Next() {
+		var dexNumber int
+		var t models.NamedResourceID
+		if err = rows.Scan(&dexNumber, &t.ID, &t.Name); err != nil {
+			return nil, err
+		}
+		types[dexNumber] = append(types[dexNumber], t)
+	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+// pokemonQueries returns the four queries needed for a pokemon entry and the search argument:
+// pokemon with camp and dungeons, types, abilities and moves.
+func pokemonQueries(input SearchInput) (queries [4]string, arg interface{}, err error) {
+	if input.SearchType == ID {
+		queries[0] = `SELECT P.dex_number, P.pokemon_name, P.evolution_stage, P.evolve_condition, P.evolve_level,
+		P.evolve_crystals, P.classification, P.camp_ID, C.camp_name, D.dungeon_ID, D.dungeon_name, PD.super_enemy
+		FROM pokemon P INNER JOIN camp C ON P.dex_number = $1 AND P.camp_ID = C.camp_ID
+		LEFT JOIN encountered_in PD ON P.dex_number = PD.dex_number
+		LEFT JOIN dungeon D ON PD.dungeon_ID = D.dungeon_ID ORDER BY D.dungeon_ID ASC;`
+		queries[1] = `SELECT T.type_ID, T.type_name FROM pokemon_type T INNER JOIN pokemon_has_type PT
+		ON PT.dex_number = $1 AND PT.type_ID = T.type_ID ORDER BY T.type_ID ASC;`
+		queries[2] = `SELECT A.ability_ID, A.ability_name FROM ability A INNER JOIN pokemon_has_ability PA
+		ON PA.dex_number = $1 AND PA.ability_ID = A.ability_ID ORDER BY A.ability_ID ASC;`
+		queries[3] = `SELECT M.move_ID, M.move_name, PM.learn_type, PM.cost, PM.level FROM attack_move M
+		INNER JOIN learns PM ON PM.dex_number = $1 AND PM.move_ID = M.move_ID ORDER BY M.move_ID ASC;`
+		return queries, input.ID, nil
+	} else if input.SearchType == Name {
+		queries[0] = `SELECT P.dex_number, P.pokemon_name, P.evolution_stage, P.evolve_condition, P.evolve_level,
+		P.evolve_crystals, P.classification, P.camp_ID, C.camp_name, D.dungeon_ID, D.dungeon_name, PD.super_enemy
+		FROM pokemon P INNER JOIN camp C ON P.pokemon_name = $1 AND P.camp_ID = C.camp_ID
+		LEFT JOIN encountered_in PD ON P.dex_number = PD.dex_number
+		LEFT JOIN dungeon D ON PD.dungeon_ID = D.dungeon_ID ORDER BY D.dungeon_ID ASC;`
+		queries[1] = `SELECT T.type_ID, T.type_name FROM pokemon P
+		INNER JOIN pokemon_has_type PT ON P.pokemon_name = $1 AND P.dex_number = PT.dex_number
+		INNER JOIN pokemon_type T ON PT.type_ID = T.type_ID ORDER BY T.type_ID ASC;`
+		queries[2] = `SELECT A.ability_ID, A.ability_name FROM pokemon P
+		INNER JOIN pokemon_has_ability PA ON P.pokemon_name = $1 AND P.dex_number = PA.dex_number
+		INNER JOIN ability A ON PA.ability_ID = A.ability_ID ORDER BY A.ability_ID ASC;`
+		queries[3] = `SELECT M.move_ID, M.move_name, PM.learn_type, PM.cost, PM.level
+		FROM pokemon P INNER JOIN learns PM ON P.pokemon_name = $1 AND P.dex_number = PM.dex_number
+		INNER JOIN attack_move M ON PM.move_ID = M.move_ID ORDER BY M.move_ID ASC;`
+		return queries, input.Name, nil
+	}
+	return queries, nil, fmt.Errorf("illegal search type %v", input.SearchType)
+}
+
+// useSequentialPokemonQueries checks if GetPokemon should run its queries on a single connection.
+func useSequentialPokemonQueries() bool {
+	switch pokemonQueryMode {
+	case Sequential:
+		return true
+	case Auto:
+		stat := dbpool.Stat()
+		return stat.AcquiredConns()*2 > stat.MaxConns()
+	default:
+		return false
+	}
+}
+
+// GetPokemon fetches a pokemon entry, its camp and all its abilities, dungeons, moves and types from the database by its ID or name.
+// Depending on the PokemonQueryMode, the four queries run concurrently on separate connections or sequentially on a single one.
+func GetPokemon(ctx context.Context, input SearchInput) (pokemon models.Pokemon, camp models.NamedResourceID, abilities []models.NamedResourceID, dungeons []models.PokemonDungeonID, moves []models.PokemonMoveID, types []models.NamedResourceID, err error) {
+	if dbquerier == nil {
 		return pokemon, camp, nil, nil, nil, nil, errors.New("database connection not initialized")
 	}
-	// Create a pgx.Rows variable for each query to be executed
-	var rows [4]pgx.Rows
-	// Create an errgroup.Group to wait until the goroutines have finished
-	// Channels are not necessary since we work with closures
-	errs, _ := errgroup.WithContext(context.Background())
-	// Query 1 - pokemon, camp, dungeon
-	errs.Go(func() error {
-		// Use different query depending on search type
-		if input.SearchType == ID {
-			queryString := `SELECT P.*, C.camp_name, D.dungeon_ID, D.dungeon_name, PD.super_enemy
-			FROM pokemon P INNER JOIN camp C ON P.dex_number = $1 AND P.camp_ID = C.camp_ID
-			LEFT JOIN encountered_in PD ON P.dex_number = PD.dex_number
-			LEFT JOIN dungeon D ON PD.dungeon_ID = D.dungeon_ID ORDER BY D.dungeon_ID ASC;`
-			rows[0], err = dbpool.Query(context.Background(), queryString, input.ID)
+	queries, arg, err := pokemonQueries(input)
+	if err != nil {
+		return pokemon, camp, nil, nil, nil, nil, err
+	}
+	// Each query is read into its own result variables, so they can run concurrently
+	readers := [4]func(rows pgx.Rows) error{
+		func(rows pgx.Rows) (err error) {
+			pokemon, camp, dungeons, err = readPokemonRows(rows)
 			return err
-		} else if input.SearchType == Name {
-			queryString := `SELECT P.*, C.camp_name, D.dungeon_ID, D.dungeon_name, PD.super_enemy
-			FROM pokemon P INNER JOIN camp C ON P.pokemon_name = $1 AND P.camp_ID = C.camp_ID
-			LEFT JOIN encountered_in PD ON P.dex_number = PD.dex_number
-			LEFT JOIN dungeon D ON PD.dungeon_ID = D.dungeon_ID ORDER BY D.dungeon_ID ASC;`
-			rows[0], err = dbpool.Query(context.Background(), queryString, input.Name)
+		},
+		func(rows pgx.Rows) (err error) {
+			types, err = readNamedResourceRows(rows)
 			return err
-		} else {
-			return fmt.Errorf("illegal search type %v", input.SearchType)
-		}
-	})
-	// Query 2 - pokemonTypes
-	errs.Go(func() error {
-		// Use different query depending on search type
-		if input.SearchType == ID {
-			queryString := `SELECT T.* FROM pokemon_type T INNER JOIN pokemon_has_type PT
-			ON PT.dex_number = $1 AND PT.type_ID = T.type_ID ORDER BY T.type_ID ASC;`
-			rows[1], err = dbpool.Query(context.Background(), queryString, input.ID)
-			return err
-		} else if input.SearchType == Name {
-			queryString := `SELECT T.* FROM pokemon P
-			INNER JOIN pokemon_has_type PT ON P.pokemon_name = $1 AND P.dex_number = PT.dex_number
-			INNER JOIN pokemon_type T ON PT.type_ID = T.type_ID ORDER BY T.type_ID ASC;`
-			rows[1], err = dbpool.Query(context.Background(), queryString, input.Name)
+		},
+		func(rows pgx.Rows) (err error) {
+			abilities, err = readNamedResourceRows(rows)
 			return err
-		} else {
-			return fmt.Errorf("illegal search type %v", input.SearchType)
-		}
-	})
-	// Query 3 - abilities
-	errs.Go(func() error {
-		// Use different query depending on search type
-		if input.SearchType == ID {
-			queryString := `SELECT A.ability_ID, A.ability_name FROM ability A INNER JOIN pokemon_has_ability PA
-			ON PA.dex_number = $1 AND PA.ability_ID = A.ability_ID ORDER BY A.ability_ID ASC;`
-			rows[2], err = dbpool.Query(context.Background(), queryString, input.ID)
+		},
+		func(rows pgx.Rows) (err error) {
+			moves, err = readPokemonMoveRows(rows)
 			return err
-		} else if input.SearchType == Name {
-			queryString := `SELECT A.ability_ID, A.ability_name FROM pokemon P
-			INNER JOIN pokemon_has_ability PA ON P.pokemon_name = $1 AND P.dex_number = PA.dex_number
-			INNER JOIN ability A ON PA.ability_ID = A.ability_ID ORDER BY A.ability_ID ASC;`
-			rows[2], err = dbpool.Query(context.Background(), queryString, input.Name)
+		},
+	}
+	// runQuery executes a query with the querier and reads all of its rows
+	runQuery := func(ctx context.Context, q querier, i int) error {
+		rows, err := queryWith(ctx, q, queries[i], arg)
+		if err != nil {
 			return err
-		} else {
-			return fmt.Errorf("illegal search type %v", input.SearchType)
 		}
-	})
-	// Query 4 - moves
-	errs.Go(func() error {
-		// Use different query depending on search type
+		defer rows.Close()
+		return readers[i](rows)
+	}
+	if useSequentialPokemonQueries() {
+		// Use a single connection, each query needs to be read completely before the next one starts
+		conn, err := dbpool.Acquire(ctx)
+		if err != nil {
+			return pokemon, camp, nil, nil, nil, nil, err
+		}
+		defer conn.Release()
+		for i := range queries {
+			if err = runQuery(ctx, conn, i); err != nil {
+				return pokemon, camp, nil, nil, nil, nil, err
+			}
+		}
+	} else {
+		// Create an errgroup.Group to wait until the goroutines have finished
+		// Channels are not necessary since we work with closures
+		// The context of the group cancels the remaining queries if one of them fails
+		errs, groupCtx := errgroup.WithContext(ctx)
+		for i := range queries {
+			i := i
+			errs.Go(func() error {
+				return runQuery(groupCtx, dbquerier, i)
+			})
+		}
+		// Wait for all Goroutines and check for any errors
+		if err = errs.Wait(); err != nil {
+			return pokemon, camp, nil, nil, nil, nil, err
+		}
+	}
+	// If the DexNumber is zero, no entry was found
+	if pokemon.DexNumber == 0 {
 		if input.SearchType == ID {
-			queryString := `SELECT M.move_ID, M.move_name, PM.learn_type, PM.cost, PM.level FROM attack_move M
-			INNER JOIN learns PM ON PM.dex_number = $1 AND PM.move_ID = M.move_ID ORDER BY M.move_ID ASC;`
-			rows[3], err = dbpool.Query(context.Background(), queryString, input.ID)
-			return err
-		} else if input.SearchType == Name {
-			queryString := `SELECT M.move_ID, M.move_name, PM.learn_type, PM.cost, PM.level
-			FROM pokemon P INNER JOIN learns PM ON P.pokemon_name = $1 AND P.dex_number = PM.dex_number
-			INNER JOIN attack_move M ON PM.move_ID = M.move_ID ORDER BY M.move_ID ASC;`
-			rows[3], err = dbpool.Query(context.Background(), queryString, input.Name)
-			return err
-		} else {
-			return fmt.Errorf("illegal search type %v", input.SearchType)
+			return pokemon, camp, nil, nil, nil, nil, &ResourceNotFoundError{ResourceType: "pokemon", SearchType: input.SearchType, ID: input.ID}
 		}
-	})
-	// Wait for all Goroutines and check for any errors
-	if err := errs.Wait(); err != nil {
+		return pokemon, camp, nil, nil, nil, nil, &ResourceNotFoundError{ResourceType: "pokemon", SearchType: input.SearchType, Name: input.Name}
+	}
+	return pokemon, camp, abilities, dungeons, moves, types, nil
+}
+
+// GetRandomPokemon fetches a random pokemon matching the filters with its camp and all its abilities, dungeons, moves
+// and types from the database. Returns a ResourceNotFoundError if no pokemon matches the filters.
+func GetRandomPokemon(ctx context.Context, filter ListFilter) (pokemon models.Pokemon, camp models.NamedResourceID, abilities []models.NamedResourceID, dungeons []models.PokemonDungeonID, moves []models.PokemonMoveID, types []models.NamedResourceID, err error) {
+	if dbquerier == nil {
+		return pokemon, camp, nil, nil, nil, nil, errors.New("database connection not initialized")
+	}
+	// Only the dex number is chosen randomly, the entry is fetched like any other pokemon
+	where := buildWhereClause(PokemonTable, filter)
+	var dexNumber int
+	err = queryRow(ctx, fmt.Sprintf("SELECT dex_number FROM pokemon %v ORDER BY RANDOM() LIMIT 1;", where.String()), where.args...).Scan(&dexNumber)
+	if err == pgx.ErrNoRows {
+		return pokemon, camp, nil, nil, nil, nil, &ResourceNotFoundError{ResourceType: "pokemon"}
+	} else if err != nil {
 		return pokemon, camp, nil, nil, nil, nil, err
 	}
-	// Close all rows after the function finished
-	defer func() {
-		for i := range rows {
-			rows[i].Close()
+	return GetPokemon(ctx, SearchInput{SearchType: ID, ID: dexNumber})
+}
+
+// GetPokemonByIDs fetches the pokemon entries with the provided dex numbers with their camp and all their abilities,
+// dungeons, moves and types from the database. Dex numbers without a matching pokemon are skipped.
+// Returns the entries ordered by dex number.
+func GetPokemonByIDs(ctx context.Context, dexNumbers []int) ([]models.PokemonEntryID, error) {
+	if dbquerier == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+	queryString := `SELECT P.dex_number, P.pokemon_name, P.evolution_stage, P.evolve_condition, P.evolve_level,
+	P.evolve_crystals, P.classification, P.camp_ID, C.camp_name FROM pokemon P
+	INNER JOIN camp C ON P.camp_ID = C.camp_ID WHERE P.dex_number = ANY($1) ORDER BY P.dex_number ASC;`
+	rows, err := query(ctx, queryString, dexNumbers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var entries []models.PokemonEntryID
+	// indexes maps the dex numbers to the position of their entry
+	indexes := make(map[int]int)
+	for rows.Next() {
+		var entry models.PokemonEntryID
+		p := &entry.Pokemon
+		err = rows.Scan(&p.DexNumber, &p.PokemonName, &p.EvolutionStage, &p.EvolveCondition, &p.EvolveLevel, &p.EvolveCrystals, &p.Classification, &entry.Camp.ID, &entry.Camp.Name)
+		if err != nil {
+			return nil, err
+		}
+		indexes[p.DexNumber] = len(entries)
+		entries = append(entries, entry)
+	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+	// Fetch the related resources of all pokemon with a single query for each relationship
+	queries := []string{
+		`SELECT PD.dex_number, D.dungeon_ID, D.dungeon_name, PD.super_enemy FROM encountered_in PD
+		INNER JOIN dungeon D ON PD.dungeon_ID = D.dungeon_ID WHERE PD.dex_number = ANY($1) ORDER BY D.dungeon_ID ASC;`,
+		`SELECT PA.dex_number, A.ability_ID, A.ability_name FROM pokemon_has_ability PA
+		INNER JOIN ability A ON PA.ability_ID = A.ability_ID WHERE PA.dex_number = ANY($1) ORDER BY A.ability_ID ASC;`,
+		`SELECT PM.dex_number, M.move_ID, M.move_name, PM.learn_type, PM.cost, PM.level FROM learns PM
+		INNER JOIN attack_move M ON PM.move_ID = M.move_ID WHERE PM.dex_number = ANY($1) ORDER BY M.move_ID ASC;`,
+	}
+	// Each reader scans a row and adds the resource to the entry of its dex number
+	readers := []func(rows pgx.Rows) error{
+		func(rows pgx.Rows) error {
+			var dexNumber int
+			var d models.PokemonDungeonID
+			if err := rows.Scan(&dexNumber, &d.Dungeon.ID, &d.Dungeon.Name, &d.IsSuper); err != nil {
+				return err
+			}
+			entry := &entries[indexes[dexNumber]]
+			entry.Dungeons = addPokemonDungeon(entry.Dungeons, d)
+			return nil
+		},
+		func(rows pgx.Rows) error {
+			var dexNumber int
+			var a models.NamedResourceID
+			if err := rows.Scan(&dexNumber, &a.ID, &a.Name); err != nil {
+				return err
+			}
+			entry := &entries[indexes[dexNumber]]
+			entry.Abilities = append(entry.Abilities, a)
+			return nil
+		},
+		func(rows pgx.Rows) error {
+			var dexNumber int
+			var m models.PokemonMoveID
+			if err := rows.Scan(&dexNumber, &m.Move.ID, &m.Move.Name, &m.Method, &m.Cost, &m.Level); err != nil {
+				return err
+			}
+			entry := &entries[indexes[dexNumber]]
+			entry.Moves = append(entry.Moves, m)
+			return nil
+		},
+	}
+	// readAll executes the query and reads all of its rows
+	readAll := func(queryString string, read func(rows pgx.Rows) error) error {
+		rows, err := query(ctx, queryString, dexNumbers)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			if err = read(rows); err != nil {
+				return err
+			}
+		}
+		// Check for errors that occurred during the iteration
+		return rows.Err()
+	}
+	for i := range queries {
+		if err = readAll(queries[i], readers[i]); err != nil {
+			return nil, err
 		}
-	}()
-	// Read query 1
+	}
+	types, err := GetTypesOfPokemon(ctx, dexNumbers)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].Types = types[entries[i].Pokemon.DexNumber]
+	}
+	return entries, nil
+}
+
+// readPokemonRows reads the pokemon, its camp and its dungeons from the rows of the first GetPokemon query.
+// Each dungeon is listed once, see addPokemonDungeon.
+func readPokemonRows(rows pgx.Rows) (pokemon models.Pokemon, camp models.NamedResourceID, dungeons []models.PokemonDungeonID, err error) {
+	// Read the first row outside of the loop to extract pokemon and camp information and check for null dungeon
+	if !rows.Next() {
+		return pokemon, camp, nil, rows.Err()
+	}
 	var d models.PokemonDungeonID
-	// Read the first row of query 1 outside of the loop to extract pokemon and camp information and check for null dungeon
-	rows[0].Next()
-	err = rows[0].Scan(&pokemon.DexNumber, &pokemon.PokemonName, &pokemon.EvolutionStage, &pokemon.EvolveCondition, &pokemon.EvolveLevel, &pokemon.EvolveCrystals, &pokemon.Classification, &camp.ID, &camp.Name, &d.Dungeon.ID, &d.Dungeon.Name, &d.IsSuper)
+	err = rows.Scan(&pokemon.DexNumber, &pokemon.PokemonName, &pokemon.EvolutionStage, &pokemon.EvolveCondition, &pokemon.EvolveLevel, &pokemon.EvolveCrystals, &pokemon.Classification, &camp.ID, &camp.Name, &d.Dungeon.ID, &d.Dungeon.Name, &d.IsSuper)
+	if err != nil {
+		return pokemon, camp, nil, err
+	}
 	// Add the first dungeon to the slice
 	// Check if the dungeon is not null to find pokemon without dungeon
 	if d.Dungeon.ID != 0 {
-		dungeons = append(dungeons, d)
+		dungeons = addPokemonDungeon(dungeons, d)
 	}
-	// Add all other pokemon to the slice
-	for rows[0].Next() {
+	// Add all other dungeons to the slice
+	for rows.Next() {
 		// Use a throwaway models.Pokemon and models.NamedResourceID to ignore pokemon and camp data for all other rows
 		var emptyPokemon models.Pokemon
 		var emptyCamp models.NamedResourceID
-		err = rows[0].Scan(&emptyPokemon.DexNumber, &emptyPokemon.PokemonName, &emptyPokemon.EvolutionStage, &emptyPokemon.EvolveCondition, &emptyPokemon.EvolveLevel, &emptyPokemon.EvolveCrystals, &emptyPokemon.Classification, &emptyCamp.ID, &emptyCamp.Name, &d.Dungeon.ID, &d.Dungeon.Name, &d.IsSuper)
+		err = rows.Scan(&emptyPokemon.DexNumber, &emptyPokemon.PokemonName, &emptyPokemon.EvolutionStage, &emptyPokemon.EvolveCondition, &emptyPokemon.EvolveLevel, &emptyPokemon.EvolveCrystals, &emptyPokemon.Classification, &emptyCamp.ID, &emptyCamp.Name, &d.Dungeon.ID, &d.Dungeon.Name, &d.IsSuper)
 		if err != nil {
-			return pokemon, camp, nil, nil, nil, nil, err
+			return pokemon, camp, nil, err
 		}
 		// Checking for ID==0 is not necessary since all rows after the first will not have null values
-		dungeons = append(dungeons, d)
+		dungeons = addPokemonDungeon(dungeons, d)
 	}
-	// If the DexNumber is zero, no entry was found
-	if pokemon.DexNumber == 0 {
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return pokemon, camp, nil, err
+	}
+	return pokemon, camp, dungeons, nil
+}
+
+// addPokemonDungeon adds the dungeon to the dungeons of a pokemon, so each dungeon is listed only once.
+// A dungeon returned both for a normal and a super enemy is merged into a single entry marked as super.
+func addPokemonDungeon(dungeons []models.PokemonDungeonID, d models.PokemonDungeonID) []models.PokemonDungeonID {
+	for i := range dungeons {
+		if dungeons[i].Dungeon.ID == d.Dungeon.ID {
+			dungeons[i].IsSuper = dungeons[i].IsSuper || d.IsSuper
+			return dungeons
+		}
+	}
+	return append(dungeons, d)
+}
+
+// readNamedResourceRows reads the ID and name of a resource from each row.
+func readNamedResourceRows(rows pgx.Rows) ([]models.NamedResourceID, error) {
+	var resources []models.NamedResourceID
+	for rows.Next() {
+		var r models.NamedResourceID
+		if err := rows.Scan(&r.ID, &r.Name); err != nil {
+			return nil, err
+		}
+		resources = append(resources, r)
+	}
+	// Check for errors that occurred during the iteration
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// readPokemonMoveRows reads the moves of a pokemon from the rows of the last GetPokemon query.
+func readPokemonMoveRows(rows pgx.Rows) ([]models.PokemonMoveID, error) {
+	var moves []models.PokemonMoveID
+	for rows.Next() {
+		var m models.PokemonMoveID
+		if err := rows.Scan(&m.Move.ID, &m.Move.Name, &m.Method, &m.Cost, &m.Level); err != nil {
+			return nil, err
+		}
+		moves = append(moves, m)
+	}
+	// Check for errors that occurred during the iteration
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return moves, nil
+}
+
+// GetPokemonDefenses fetches a pokemon entry and calculates the combined damage multiplier it takes from
+// each attacking type by combining the interactions with all of its types.
+func GetPokemonDefenses(ctx context.Context, input SearchInput) (pokemon models.NamedResourceID, defenses []models.TypeDefenseID, err error) {
+	if dbquerier == nil {
+		return pokemon, nil, errors.New("database connection not initialized")
+	}
+	var rows pgx.Rows
+	// Use different query depending on search type
+	// Each row contains the interaction of one attacking type with one type of the pokemon
+	if useMaterializedViews {
+		// Read the precomputed interactions from the materialized view
+		queryString := `SELECT dex_number, pokemon_name, attacker_ID, attacker_name, interaction
+		FROM pokemon_defenses_view WHERE %v = $1 ORDER BY attacker_ID ASC;`
 		if input.SearchType == ID {
-			return pokemon, camp, nil, nil, nil, nil, &ResourceNotFoundError{ResourceType: "pokemon", SearchType: input.SearchType, ID: input.ID}
+			rows, err = query(ctx, fmt.Sprintf(queryString, "dex_number"), input.ID)
 		} else if input.SearchType == Name {
-			return pokemon, camp, nil, nil, nil, nil, &ResourceNotFoundError{ResourceType: "pokemon", SearchType: input.SearchType, Name: input.Name}
+			rows, err = query(ctx, fmt.Sprintf(queryString, "pokemon_name"), input.Name)
+		} else {
+			return pokemon, nil, fmt.Errorf("illegal search type %v", input.SearchType)
 		}
+	} else if input.SearchType == ID {
+		queryString := `SELECT P.dex_number, P.pokemon_name, AT.type_ID, AT.type_name, COALESCE(TT.interaction::text, '')
+		FROM (SELECT * FROM pokemon WHERE dex_number = $1) P
+		CROSS JOIN pokemon_type AT
+		LEFT JOIN pokemon_has_type PT ON P.dex_number = PT.dex_number
+		LEFT JOIN effectiveness TT ON AT.type_ID = TT.attacker AND PT.type_ID = TT.defender ORDER BY AT.type_ID ASC;`
+		rows, err = query(ctx, queryString, input.ID)
+	} else if input.SearchType == Name {
+		queryString := `SELECT P.dex_number, P.pokemon_name, AT.type_ID, AT.type_name, COALESCE(TT.interaction::text, '')
+		FROM (SELECT * FROM pokemon WHERE pokemon_name = $1) P
+		CROSS JOIN pokemon_type AT
+		LEFT JOIN pokemon_has_type PT ON P.dex_number = PT.dex_number
+		LEFT JOIN effectiveness TT ON AT.type_ID = TT.attacker AND PT.type_ID = TT.defender ORDER BY AT.type_ID ASC;`
+		rows, err = query(ctx, queryString, input.Name)
+	} else {
+		return pokemon, nil, fmt.Errorf("illegal search type %v", input.SearchType)
 	}
-	// Read query 2
-	for rows[1].Next() {
-		var t models.NamedResourceID
-		err = rows[1].Scan(&t.ID, &t.Name)
+	if err != nil {
+		return pokemon, nil, err
+	}
+	defer rows.Close()
+	// Combine the interactions of all rows with the same attacking type
+	for rows.Next() {
+		var attacker models.NamedResourceID
+		var interaction string
+		err = rows.Scan(&pokemon.ID, &pokemon.Name, &attacker.ID, &attacker.Name, &interaction)
 		if err != nil {
-			return pokemon, camp, nil, nil, nil, nil, err
+			return pokemon, nil, err
+		}
+		// Rows are ordered by the attacking type, so only the last entry needs to be checked
+		last := len(defenses) - 1
+		if last >= 0 && defenses[last].Attacker.ID == attacker.ID {
+			defenses[last].Multiplier *= models.InteractionMultiplier(interaction)
+		} else {
+			defenses = append(defenses, models.TypeDefenseID{Attacker: attacker, Multiplier: models.InteractionMultiplier(interaction)})
 		}
-		types = append(types, t)
 	}
-	// Read query 3
-	for rows[2].Next() {
-		var a models.NamedResourceID
-		err = rows[2].Scan(&a.ID, &a.Name)
-		if err != nil {
-			return pokemon, camp, nil, nil, nil, nil, err
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return pokemon, nil, err
+	}
+	// If the pokemon ID is zero, no entry was found
+	if pokemon.ID == 0 {
+		if input.SearchType == ID {
+			return pokemon, nil, &ResourceNotFoundError{ResourceType: "pokemon", SearchType: input.SearchType, ID: input.ID}
+		} else if input.SearchType == Name {
+			return pokemon, nil, &ResourceNotFoundError{ResourceType: "pokemon", SearchType: input.SearchType, Name: input.Name}
 		}
-		abilities = append(abilities, a)
 	}
-	// Read query 4
-	for rows[3].Next() {
-		var m models.PokemonMoveID
-		err = rows[3].Scan(&m.Move.ID, &m.Move.Name, &m.Method, &m.Cost, &m.Level)
+	return pokemon, defenses, nil
+}
+
+// GetPokemonGroupCounts fetches the number of pokemon for each group of the given dimension from the database.
+func GetPokemonGroupCounts(ctx context.Context, groupBy GroupBy) ([]models.GroupCount, error) {
+	if dbquerier == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+	var queryString string
+	// Use different query depending on the dimension
+	switch groupBy {
+	case GroupByType:
+		queryString = `SELECT T.type_name, COUNT(*) FROM pokemon_has_type PT
+		INNER JOIN pokemon_type T ON PT.type_ID = T.type_ID
+		GROUP BY T.type_ID, T.type_name ORDER BY T.type_ID ASC;`
+	case GroupByCamp:
+		queryString = `SELECT C.camp_name, COUNT(*) FROM pokemon P
+		INNER JOIN camp C ON P.camp_ID = C.camp_ID
+		GROUP BY C.camp_ID, C.camp_name ORDER BY C.camp_ID ASC;`
+	case GroupByEvolutionStage:
+		// Pokemon without an evolution stage are counted as "none" after all stages, like in the materialized view
+		queryString = `SELECT COALESCE(evolution_stage::text, 'none'), COUNT(*) FROM pokemon
+		GROUP BY evolution_stage ORDER BY evolution_stage ASC NULLS LAST;`
+	default:
+		return nil, fmt.Errorf("illegal group by dimension %v", groupBy)
+	}
+	var args []interface{}
+	if useMaterializedViews {
+		// Read the precomputed counts from the materialized view
+		queryString = `SELECT group_name, count FROM pokemon_group_counts_view
+		WHERE dimension = $1 ORDER BY group_order ASC NULLS LAST;`
+		args = append(args, groupBy)
+	}
+	rows, err := query(ctx, queryString, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	// Add all groups found to the slice
+	var groups []models.GroupCount
+	for rows.Next() {
+		var group models.GroupCount
+		err = rows.Scan(&group.Group, &group.Count)
 		if err != nil {
-			return pokemon, camp, nil, nil, nil, nil, err
+			return nil, err
 		}
-		moves = append(moves, m)
+		groups = append(groups, group)
 	}
-	return pokemon, camp, abilities, dungeons, moves, types, nil
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return groups, nil
 }
 
 // GetPokemonTypeList fetches a slice of all pokemon_type entries from the database.
-func GetPokemonTypeList(sort SortInput, pagination Pagination) (int, []models.NamedResourceID, error) {
-	if dbpool == nil {
+func GetPokemonTypeList(ctx context.Context, sort SortInput, pagination Pagination, filter ListFilter) (int, []models.NamedResourceID, error) {
+	if dbquerier == nil {
 		return 0, nil, errors.New("database connection not initialized")
 	}
 	var pokemonTypes []models.NamedResourceID
-	queryString := buildQuery("SELECT * FROM pokemon_type", sort, "type_ID", "type_name", pagination)
-	rows, err := dbpool.Query(context.Background(), queryString)
+	where := buildWhereClause(TypeTable, filter)
+	queryString := buildQuery("SELECT type_ID, type_name FROM pokemon_type", where, sort, TypeTable, "type_ID", "type_name", pagination)
+	rows, err := query(ctx, queryString, where.args...)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -651,33 +1592,305 @@ func GetPokemonTypeList(sort SortInput, pagination Pagination) (int, []models.Na
 		}
 		pokemonTypes = append(pokemonTypes, pokemonType)
 	}
-	// Get the total count
-	count, err := getCount("dungeon")
-	if err != nil {
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
 		return 0, nil, err
 	}
-	return count, pokemonTypes, nil
+	// Get the total count unless it is skipped
+	if pagination.SkipCount {
+		return -1, pokemonTypes, nil
+	}
+	return getCountOrUnknown(ctx, "pokemon_type", where), pokemonTypes, nil
+}
+
+// GetPokemonFullLearnset fetches the moves learned by a pokemon and all of its pre-evolutions from the database
+// by its ID or name. Each move is only returned once with all pokemon of the evolution line learning it.
+func GetPokemonFullLearnset(ctx context.Context, input SearchInput) (pokemon models.NamedResourceID, learnset []models.LearnsetMoveID, err error) {
+	if dbquerier == nil {
+		return pokemon, nil, errors.New("database connection not initialized")
+	}
+	// Find the pokemon first to distinguish missing pokemon from empty learnsets
+	if input.SearchType == ID {
+		err = queryRow(ctx, "SELECT dex_number, pokemon_name FROM pokemon WHERE dex_number = $1;", input.ID).Scan(&pokemon.ID, &pokemon.Name)
+	} else if input.SearchType == Name {
+		err = queryRow(ctx, "SELECT dex_number, pokemon_name FROM pokemon WHERE pokemon_name = $1;", input.Name).Scan(&pokemon.ID, &pokemon.Name)
+	} else {
+		return pokemon, nil, fmt.Errorf("illegal search type %v", input.SearchType)
+	}
+	if err == pgx.ErrNoRows {
+		return pokemon, nil, &ResourceNotFoundError{ResourceType: "pokemon", SearchType: input.SearchType, ID: input.ID, Name: input.Name}
+	} else if err != nil {
+		return pokemon, nil, err
+	}
+	// Walk the evolution line backwards and collect the moves of all pokemon in it
+	queryString := `WITH RECURSIVE evolution_line (dex_number) AS (
+		SELECT dex_number FROM pokemon WHERE dex_number = $1
+		UNION
+		SELECT E.dex_number FROM evolves_into E INNER JOIN evolution_line L ON E.evolved_dex_number = L.dex_number
+	)
+	SELECT M.move_ID, M.move_name, P.dex_number, P.pokemon_name, P.evolution_stage, PM.learn_type, PM.cost, PM.level
+	FROM evolution_line L INNER JOIN pokemon P ON L.dex_number = P.dex_number
+	INNER JOIN learns PM ON P.dex_number = PM.dex_number
+	INNER JOIN attack_move M ON PM.move_ID = M.move_ID ORDER BY M.move_ID ASC, P.evolution_stage ASC, P.dex_number ASC;`
+	rows, err := query(ctx, queryString, pokemon.ID)
+	if err != nil {
+		return pokemon, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var move models.NamedResourceID
+		var entry models.LearnsetEntryID
+		err = rows.Scan(&move.ID, &move.Name, &entry.Pokemon.ID, &entry.Pokemon.Name, &entry.EvolutionStage, &entry.Method, &entry.Cost, &entry.Level)
+		if err != nil {
+			return pokemon, nil, err
+		}
+		// Rows are ordered by the move, so only the last entry needs to be checked
+		last := len(learnset) - 1
+		if last >= 0 && learnset[last].Move.ID == move.ID {
+			learnset[last].LearnedBy = append(learnset[last].LearnedBy, entry)
+		} else {
+			learnset = append(learnset, models.LearnsetMoveID{Move: move, LearnedBy: []models.LearnsetEntryID{entry}})
+		}
+	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return pokemon, nil, err
+	}
+	return pokemon, learnset, nil
+}
+
+// GetEvolutionChain fetches the evolution chain of the pokemon from the database, starting with the base form
+// of its evolution line. Each pokemon contains all pokemon evolving from it, so branching evolutions form a tree.
+func GetEvolutionChain(ctx context.Context, input SearchInput) (chain models.EvolutionNodeID, err error) {
+	if dbquerier == nil {
+		return chain, errors.New("database connection not initialized")
+	}
+	// Find the pokemon first to distinguish missing pokemon from pokemon without evolutions
+	var dexNumber int
+	if input.SearchType == ID {
+		err = queryRow(ctx, "SELECT dex_number FROM pokemon WHERE dex_number = $1;", input.ID).Scan(&dexNumber)
+	} else if input.SearchType == Name {
+		err = queryRow(ctx, "SELECT dex_number FROM pokemon WHERE pokemon_name = $1;", input.Name).Scan(&dexNumber)
+	} else {
+		return chain, fmt.Errorf("illegal search type %v", input.SearchType)
+	}
+	if err == pgx.ErrNoRows {
+		return chain, &ResourceNotFoundError{ResourceType: "pokemon", SearchType: input.SearchType, ID: input.ID, Name: input.Name}
+	} else if err != nil {
+		return chain, err
+	}
+	// Walk the evolution line backwards to the base form and from there forwards to all final forms
+	queryString := `WITH RECURSIVE ancestors (dex_number) AS (
+		SELECT dex_number FROM pokemon WHERE dex_number = $1
+		UNION
+		SELECT E.dex_number FROM evolves_into E INNER JOIN ancestors A ON E.evolved_dex_number = A.dex_number
+	), chain (dex_number, parent) AS (
+		SELECT A.dex_number, NULL::smallint FROM ancestors A
+		WHERE NOT EXISTS (SELECT 1 FROM evolves_into E WHERE E.evolved_dex_number = A.dex_number)
+		UNION
+		SELECT E.evolved_dex_number, E.dex_number FROM evolves_into E INNER JOIN chain C ON E.dex_number = C.dex_number
+	)
+	SELECT C.parent, P.dex_number, P.pokemon_name, P.evolve_condition, P.evolve_level, P.evolve_crystals
+	FROM chain C INNER JOIN pokemon P ON C.dex_number = P.dex_number ORDER BY P.evolution_stage ASC, P.dex_number ASC;`
+	rows, err := query(ctx, queryString, dexNumber)
+	if err != nil {
+		return chain, err
+	}
+	defer rows.Close()
+	// Collect the evolutions of each pokemon, the base form has no parent
+	var root *models.EvolutionNodeID
+	evolutions := make(map[int][]models.EvolutionNodeID)
+	for rows.Next() {
+		var parent *int
+		var node models.EvolutionNodeID
+		err = rows.Scan(&parent, &node.Pokemon.ID, &node.Pokemon.Name, &node.EvolveCondition, &node.EvolveLevel, &node.EvolveCrystals)
+		if err != nil {
+			return chain, err
+		}
+		if parent == nil {
+			if root == nil {
+				root = &node
+			}
+		} else {
+			evolutions[*parent] = append(evolutions[*parent], node)
+		}
+	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return chain, err
+	}
+	if root == nil {
+		return chain, fmt.Errorf("no base form found in the evolution line of pokemon %v", dexNumber)
+	}
+	return buildEvolutionTree(*root, evolutions), nil
+}
+
+// buildEvolutionTree adds the evolutions of the node and recursively of all of its evolutions.
+func buildEvolutionTree(node models.EvolutionNodeID, evolutions map[int][]models.EvolutionNodeID) models.EvolutionNodeID {
+	node.EvolvesTo = []models.EvolutionNodeID{}
+	for _, evolution := range evolutions[node.Pokemon.ID] {
+		node.EvolvesTo = append(node.EvolvesTo, buildEvolutionTree(evolution, evolutions))
+	}
+	return node
+}
+
+// GetTypeMatrix fetches the matchups of all attacking types against all defending types from the database.
+// Each attacking type is passed to rowFunc as soon as its row is complete, so the matrix is never held in memory.
+// Returns the first error of rowFunc.
+func GetTypeMatrix(ctx context.Context, rowFunc func(row models.TypeMatrixRowID) error) error {
+	if dbquerier == nil {
+		return errors.New("database connection not initialized")
+	}
+	queryString := `SELECT AT.type_ID, AT.type_name, DT.type_ID, DT.type_name, COALESCE(TT.interaction::text, '')
+	FROM pokemon_type AT
+	CROSS JOIN pokemon_type DT
+	LEFT JOIN effectiveness TT ON AT.type_ID = TT.attacker AND DT.type_ID = TT.defender
+	ORDER BY AT.type_ID ASC, DT.type_ID ASC;`
+	rows, err := query(ctx, queryString)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var row models.TypeMatrixRowID
+	for rows.Next() {
+		var attacker models.NamedResourceID
+		var matchup models.TypeMatchupID
+		var interaction string
+		err = rows.Scan(&attacker.ID, &attacker.Name, &matchup.Defender.ID, &matchup.Defender.Name, &interaction)
+		if err != nil {
+			return err
+		}
+		// Rows are ordered by the attacking type, so a new attacker completes the previous row
+		if row.Attacker.ID != attacker.ID {
+			if len(row.Matchups) > 0 {
+				if err = rowFunc(row); err != nil {
+					return err
+				}
+			}
+			row = models.TypeMatrixRowID{Attacker: attacker}
+		}
+		matchup.Multiplier = models.InteractionMultiplier(interaction)
+		row.Matchups = append(row.Matchups, matchup)
+	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	// Pass the last row
+	if len(row.Matchups) > 0 {
+		return rowFunc(row)
+	}
+	return nil
+}
+
+// GetTypeCoverage fetches the best damage multiplier the attacking types with the provided names achieve
+// against each type, ordered by the ID of the defending type. Also returns the attacking types that were
+// found, names without a matching type are ignored.
+func GetTypeCoverage(ctx context.Context, attackerNames []string) (attackers []models.NamedResourceID, coverage []models.TypeCoverageID, err error) {
+	if dbquerier == nil {
+		return nil, nil, errors.New("database connection not initialized")
+	}
+	queryString := `SELECT DT.type_ID, DT.type_name, AT.type_ID, AT.type_name, COALESCE(TT.interaction::text, '')
+	FROM (SELECT * FROM pokemon_type WHERE type_name = ANY($1)) AT
+	CROSS JOIN pokemon_type DT
+	LEFT JOIN effectiveness TT ON AT.type_ID = TT.attacker AND DT.type_ID = TT.defender
+	ORDER BY DT.type_ID ASC, AT.type_ID ASC;`
+	rows, err := query(ctx, queryString, attackerNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var defender, attacker models.NamedResourceID
+		var interaction string
+		err = rows.Scan(&defender.ID, &defender.Name, &attacker.ID, &attacker.Name, &interaction)
+		if err != nil {
+			return nil, nil, err
+		}
+		multiplier := models.InteractionMultiplier(interaction)
+		// Rows are ordered by the defending type, so a new defender starts a new entry
+		last := len(coverage) - 1
+		if last < 0 || coverage[last].Defender.ID != defender.ID {
+			coverage = append(coverage, models.TypeCoverageID{Defender: defender, Multiplier: multiplier})
+			last++
+		}
+		// The attackers are the same for all defenders, so they are collected with the first one
+		if last == 0 {
+			attackers = append(attackers, attacker)
+		}
+		// Keep all attackers achieving the best multiplier
+		if multiplier > coverage[last].Multiplier {
+			coverage[last].Multiplier = multiplier
+			coverage[last].Attackers = nil
+		}
+		if multiplier == coverage[last].Multiplier {
+			coverage[last].Attackers = append(coverage[last].Attackers, attacker)
+		}
+	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return attackers, coverage, nil
+}
+
+// findType fetches the ID and name of the type with the ID or name of the SearchInput.
+func findType(ctx context.Context, input SearchInput) (pokemonType models.NamedResourceID, err error) {
+	if input.SearchType == ID {
+		err = queryRow(ctx, "SELECT type_ID, type_name FROM pokemon_type WHERE type_ID = $1;", input.ID).Scan(&pokemonType.ID, &pokemonType.Name)
+	} else if input.SearchType == Name {
+		err = queryRow(ctx, "SELECT type_ID, type_name FROM pokemon_type WHERE type_name = $1;", input.Name).Scan(&pokemonType.ID, &pokemonType.Name)
+	} else {
+		return pokemonType, fmt.Errorf("illegal search type %v", input.SearchType)
+	}
+	if err == pgx.ErrNoRows {
+		return pokemonType, &ResourceNotFoundError{ResourceType: "type", SearchType: input.SearchType, ID: input.ID, Name: input.Name}
+	}
+	return pokemonType, err
+}
+
+// GetTypeMatchup fetches the interaction of the attacking type with the defending type from the database.
+// Returns a ResourceNotFoundError if one of the types does not exist. Types without an explicit
+// interaction in the database interact normally, which is returned as the interaction "normal".
+func GetTypeMatchup(ctx context.Context, attackerInput SearchInput, defenderInput SearchInput) (attacker models.NamedResourceID, defender models.NamedResourceID, interaction string, err error) {
+	if dbquerier == nil {
+		return attacker, defender, "", errors.New("database connection not initialized")
+	}
+	// Find both types first to distinguish missing types from missing interactions
+	if attacker, err = findType(ctx, attackerInput); err != nil {
+		return attacker, defender, "", err
+	}
+	if defender, err = findType(ctx, defenderInput); err != nil {
+		return attacker, defender, "", err
+	}
+	err = queryRow(ctx, "SELECT interaction FROM effectiveness WHERE attacker = $1 AND defender = $2;", attacker.ID, defender.ID).Scan(&interaction)
+	if err == pgx.ErrNoRows {
+		return attacker, defender, "normal", nil
+	} else if err != nil {
+		return attacker, defender, "", err
+	}
+	return attacker, defender, interaction, nil
 }
 
 // GetPokemonType fetches a pokemonType entry and its type interactions from the database by its ID or name.
-func GetPokemonType(input SearchInput) (pokemonType models.PokemonType, interactions []models.TypeInteractionID, err error) {
-	if dbpool == nil {
+func GetPokemonType(ctx context.Context, input SearchInput) (pokemonType models.PokemonType, interactions []models.TypeInteractionID, err error) {
+	if dbquerier == nil {
 		return pokemonType, nil, errors.New("database connection not initialized")
 	}
 	var rows pgx.Rows
 	// Use different query depending on search type
 	if input.SearchType == ID {
-		queryString := `SELECT AT.*, TT.interaction, DT.*
+		queryString := `SELECT AT.type_ID, AT.type_name, TT.interaction, DT.type_ID, DT.type_name
 		FROM (SELECT * FROM pokemon_type WHERE type_ID = $1) AT
 		LEFT JOIN effectiveness TT ON AT.type_ID = TT.attacker
 		LEFT JOIN pokemon_type DT ON TT.defender = DT.type_ID ORDER BY DT.type_ID ASC;`
-		rows, err = dbpool.Query(context.Background(), queryString, input.ID)
+		rows, err = query(ctx, queryString, input.ID)
 	} else if input.SearchType == Name {
-		queryString := `SELECT AT.*, TT.interaction, DT.*
+		queryString := `SELECT AT.type_ID, AT.type_name, TT.interaction, DT.type_ID, DT.type_name
 		FROM (SELECT * FROM pokemon_type WHERE type_name = $1) AT
 		LEFT JOIN effectiveness TT ON AT.type_ID = TT.attacker
 		LEFT JOIN pokemon_type DT ON TT.defender = DT.type_ID ORDER BY DT.type_ID ASC;`
-		rows, err = dbpool.Query(context.Background(), queryString, input.Name)
+		rows, err = query(ctx, queryString, input.Name)
 	} else {
 		return pokemonType, nil, fmt.Errorf("illegal search type %v", input.SearchType)
 	}
@@ -705,6 +1918,10 @@ func GetPokemonType(input SearchInput) (pokemonType models.PokemonType, interact
 		// Checking for ID==0 is not necessary since all rows after the first will not have null values
 		interactions = append(interactions, i)
 	}
+	// Check for errors that occurred during the iteration
+	if err = rows.Err(); err != nil {
+		return pokemonType, nil, err
+	}
 	// If the TypeID is zero, no entry was found
 	if pokemonType.TypeID == 0 {
 		if input.SearchType == ID {
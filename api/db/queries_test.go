@@ -0,0 +1,455 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/janek64/pmd-dx-api/api/models"
+)
+
+// errConnectionLost is returned by the fakes to simulate a connection dropping during a query.
+var errConnectionLost = errors.New("connection lost")
+
+// fakeRows is a pgx.Rows returning fixed rows, followed by err after the last row.
+// Only the methods used by the package are implemented.
+type fakeRows struct {
+	pgx.Rows
+	rows    [][]interface{}
+	err     error
+	current int
+	closed  bool
+}
+
+func (r *fakeRows) Next() bool {
+	if r.closed || r.current >= len(r.rows) {
+		return false
+	}
+	r.current++
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	row := r.rows[r.current-1]
+	if len(dest) != len(row) {
+		return errors.New("number of destinations does not match the number of columns")
+	}
+	for i, value := range row {
+		if scanner, ok := dest[i].(sql.Scanner); ok {
+			if err := scanner.Scan(value); err != nil {
+				return err
+			}
+			continue
+		}
+		if value != nil {
+			reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(value))
+		}
+	}
+	return nil
+}
+
+func (r *fakeRows) Err() error {
+	if r.current < len(r.rows) {
+		return nil
+	}
+	return r.err
+}
+
+func (r *fakeRows) Close() {
+	r.closed = true
+}
+
+// fakeRow is the pgx.Row of the first row of a fakeRows.
+type fakeRow struct {
+	rows *fakeRows
+	err  error
+}
+
+func (r *fakeRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	return r.rows.Scan(dest...)
+}
+
+// fakeQuerier answers the queries with the rows returned by respond and records them.
+type fakeQuerier struct {
+	respond func(sql string, args []interface{}) (*fakeRows, error)
+	mu      sync.Mutex
+	queries []string
+	args    [][]interface{}
+}
+
+func (q *fakeQuerier) record(sql string, args []interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.queries = append(q.queries, sql)
+	q.args = append(q.args, args)
+}
+
+func (q *fakeQuerier) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	q.record(sql, args)
+	rows, err := q.respond(sql, args)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (q *fakeQuerier) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	q.record(sql, args)
+	rows, err := q.respond(sql, args)
+	return &fakeRow{rows: rows, err: err}
+}
+
+// useFakeQuerier replaces the querier of the package with a fakeQuerier answering with respond until the test ends.
+func useFakeQuerier(t *testing.T, respond func(sql string, args []interface{}) (*fakeRows, error)) *fakeQuerier {
+	t.Helper()
+	q := &fakeQuerier{respond: respond}
+	previous := dbquerier
+	dbquerier = q
+	t.Cleanup(func() { dbquerier = previous })
+	return q
+}
+
+func TestReadersReturnIterationErrors(t *testing.T) {
+	pokemonRow := []interface{}{1, "Bulbasaur", 1, "", nil, nil, "Seed Pokemon", 1, "Mystic Forest", 3, "Mt. Bristle", false}
+	tests := []struct {
+		name string
+		rows [][]interface{}
+		read func(rows pgx.Rows) (interface{}, error)
+	}{
+		{
+			name: "named resources",
+			rows: [][]interface{}{{1, "Overgrow"}, {2, "Chlorophyll"}},
+			read: func(rows pgx.Rows) (interface{}, error) { return readNamedResourceRows(rows) },
+		},
+		{
+			name: "pokemon moves",
+			rows: [][]interface{}{{1, "Tackle", "level", nil, int64(1)}},
+			read: func(rows pgx.Rows) (interface{}, error) { return readPokemonMoveRows(rows) },
+		},
+		{
+			name: "pokemon dungeons",
+			rows: [][]interface{}{pokemonRow, pokemonRow},
+			read: func(rows pgx.Rows) (interface{}, error) {
+				_, _, dungeons, err := readPokemonRows(rows)
+				return dungeons, err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.read(&fakeRows{rows: tt.rows, err: errConnectionLost})
+			if !errors.Is(err, errConnectionLost) {
+				t.Fatalf("err = %v, want %v", err, errConnectionLost)
+			}
+			if !reflect.ValueOf(result).IsNil() {
+				t.Errorf("returned partial result %v with the error", result)
+			}
+		})
+	}
+}
+
+func TestListReturnsIterationError(t *testing.T) {
+	q := useFakeQuerier(t, func(sql string, args []interface{}) (*fakeRows, error) {
+		return &fakeRows{rows: [][]interface{}{{1, "Overgrow"}}, err: errConnectionLost}, nil
+	})
+	count, abilities, err := GetAbilityList(context.Background(), SortInput{}, Pagination{PerPage: 50, Page: 1}, ListFilter{})
+	if !errors.Is(err, errConnectionLost) {
+		t.Fatalf("err = %v, want %v", err, errConnectionLost)
+	}
+	if abilities != nil || count != 0 {
+		t.Errorf("returned partial list %v with count %v", abilities, count)
+	}
+	for _, queryString := range q.queries {
+		if strings.Contains(queryString, "COUNT(*)") {
+			t.Errorf("counted the list after the iteration failed: %v", queryString)
+		}
+	}
+}
+
+func TestGetPokemonReturnsIterationError(t *testing.T) {
+	useFakeQuerier(t, func(sql string, args []interface{}) (*fakeRows, error) {
+		if strings.Contains(sql, "attack_move") {
+			return &fakeRows{rows: [][]interface{}{{1, "Tackle", "level", nil, int64(1)}}, err: errConnectionLost}, nil
+		}
+		if strings.Contains(sql, "encountered_in") {
+			return &fakeRows{rows: [][]interface{}{{1, "Bulbasaur", 1, "", nil, nil, "Seed Pokemon", 1, "Mystic Forest", 3, "Mt. Bristle", false}}}, nil
+		}
+		return &fakeRows{rows: [][]interface{}{{12, "Grass"}}}, nil
+	})
+	_, _, _, _, moves, _, err := GetPokemon(context.Background(), SearchInput{SearchType: ID, ID: 1})
+	if !errors.Is(err, errConnectionLost) {
+		t.Fatalf("err = %v, want %v", err, errConnectionLost)
+	}
+	if moves != nil {
+		t.Errorf("returned partial moves %v with the error", moves)
+	}
+}
+
+func TestListsWithoutCountIfCountFails(t *testing.T) {
+	lists := map[string]func(ctx context.Context, sort SortInput, pagination Pagination, filter ListFilter) (int, []models.NamedResourceID, error){
+		"abilities": GetAbilityList,
+		"camps":     GetCampList,
+		"dungeons":  GetDungeonList,
+		"moves":     GetMoveList,
+		"pokemon":   GetPokemonList,
+		"types":     GetPokemonTypeList,
+	}
+	for name, getList := range lists {
+		getList := getList
+		t.Run(name, func(t *testing.T) {
+			useFakeQuerier(t, func(sql string, args []interface{}) (*fakeRows, error) {
+				if strings.Contains(sql, "COUNT(*)") {
+					return nil, errConnectionLost
+				}
+				return &fakeRows{rows: [][]interface{}{{1, "First"}, {2, "Second"}}}, nil
+			})
+			count, resources, err := getList(context.Background(), SortInput{}, Pagination{PerPage: 50, Page: 1}, ListFilter{})
+			if err != nil {
+				t.Fatalf("the failing count failed the list: %v", err)
+			}
+			if count != -1 {
+				t.Errorf("count = %v, want -1", count)
+			}
+			want := []models.NamedResourceID{{ID: 1, Name: "First"}, {ID: 2, Name: "Second"}}
+			if !reflect.DeepEqual(resources, want) {
+				t.Errorf("resources = %v, want %v", resources, want)
+			}
+		})
+	}
+}
+
+// tableRows answers the queries of a list with the rows of the table after FROM
+// and the count queries with the number of rows of that table.
+func tableRows(tables map[string][][]interface{}) func(sql string, args []interface{}) (*fakeRows, error) {
+	return func(sql string, args []interface{}) (*fakeRows, error) {
+		fields := strings.Fields(sql[strings.Index(sql, "FROM")+len("FROM"):])
+		rows, ok := tables[strings.TrimSuffix(fields[0], ";")]
+		if !ok {
+			return nil, errors.New("unknown table")
+		}
+		if strings.Contains(sql, "COUNT(*)") {
+			return &fakeRows{rows: [][]interface{}{{len(rows)}}}, nil
+		}
+		return &fakeRows{rows: rows}, nil
+	}
+}
+
+func TestGetPokemonTypeListCountsTypes(t *testing.T) {
+	types := [][]interface{}{{1, "Normal"}, {2, "Fire"}, {3, "Water"}}
+	q := useFakeQuerier(t, tableRows(map[string][][]interface{}{
+		"pokemon_type": types,
+		"dungeon":      {{1, "Beach Cave"}, {2, "Drenched Bluff"}, {3, "Mt. Bristle"}, {4, "Waterfall Cave"}},
+	}))
+	count, pokemonTypes, err := GetPokemonTypeList(context.Background(), SortInput{}, Pagination{PerPage: 50, Page: 1}, ListFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != len(types) {
+		t.Errorf("count = %v, want the %v types", count, len(types))
+	}
+	if len(pokemonTypes) != len(types) {
+		t.Errorf("returned %v types, want %v", len(pokemonTypes), len(types))
+	}
+	// The count needs the same conditions as the list
+	if len(q.queries) != 2 || !reflect.DeepEqual(q.args[0], q.args[1]) {
+		t.Errorf("count and list were queried with different arguments: %v", q.args)
+	}
+}
+
+// BenchmarkGetPokemonQueryModes compares the latency and the connection usage of the pokemon query modes.
+// Besides the time per pokemon, it reports the connections acquired from the pool per pokemon and how many
+// of these acquisitions had to wait for a free connection. It needs the database configured like for InitDB
+// and is skipped otherwise.
+func BenchmarkGetPokemonQueryModes(b *testing.B) {
+	if _, ok := os.LookupEnv("DB_URL"); !ok {
+		b.Skip("DB_URL is not set, the benchmark needs a database")
+	}
+	if err := InitDB(); err != nil {
+		b.Fatal(err)
+	}
+	defer CloseDB()
+	defer func(mode PokemonQueryMode) { pokemonQueryMode = mode }(pokemonQueryMode)
+	input := SearchInput{SearchType: ID, ID: 1}
+	for _, mode := range []PokemonQueryMode{Concurrent, Sequential} {
+		pokemonQueryMode = mode
+		// run measures the pokemon queries with the body and reports the connection usage per pokemon
+		run := func(b *testing.B, body func(b *testing.B)) {
+			before := dbpool.Stat()
+			b.ResetTimer()
+			body(b)
+			b.StopTimer()
+			after := dbpool.Stat()
+			b.ReportMetric(float64(after.AcquireCount()-before.AcquireCount())/float64(b.N), "acquires/op")
+			b.ReportMetric(float64(after.EmptyAcquireCount()-before.EmptyAcquireCount())/float64(b.N), "waits/op")
+		}
+		// A single request at a time shows the latency of the mode
+		b.Run(string(mode), func(b *testing.B) {
+			run(b, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, _, _, _, _, _, err := GetPokemon(context.Background(), input); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+		// Parallel requests show how the mode behaves when the pool is under pressure
+		b.Run(string(mode)+"-parallel", func(b *testing.B) {
+			run(b, func(b *testing.B) {
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						if _, _, _, _, _, _, err := GetPokemon(context.Background(), input); err != nil {
+							b.Error(err)
+							return
+						}
+					}
+				})
+			})
+		})
+	}
+}
+
+func TestGetMoveListCategoryByPower(t *testing.T) {
+	q := useFakeQuerier(t, func(sql string, args []interface{}) (*fakeRows, error) {
+		if strings.Contains(sql, "COUNT(*)") {
+			return &fakeRows{rows: [][]interface{}{{3}}}, nil
+		}
+		return &fakeRows{rows: [][]interface{}{{42, "Mega Punch"}, {7, "Cut"}, {1, "Tackle"}}}, nil
+	})
+	sort := SortInput{SortTypes: []SortType{PowerDesc}}
+	filter := ListFilter{Category: Physical}
+	count, moves, err := GetMoveList(context.Background(), sort, Pagination{PerPage: 50, Page: 1}, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The filter and the sorting are applied by a single list query, counted with the same conditions
+	wantQueries := []string{
+		"SELECT move_ID, move_name FROM attack_move WHERE category = $1 ORDER BY initial_power DESC, move_ID ASC LIMIT 50 OFFSET 0;",
+		"SELECT COUNT(*) AS count FROM attack_move WHERE category = $1;",
+	}
+	if !reflect.DeepEqual(q.queries, wantQueries) {
+		t.Errorf("queries =\n%q\nwant\n%q", q.queries, wantQueries)
+	}
+	wantArgs := [][]interface{}{{"Physical"}, {"Physical"}}
+	if !reflect.DeepEqual(q.args, wantArgs) {
+		t.Errorf("arguments = %v, want %v", q.args, wantArgs)
+	}
+	// The order of the query is kept
+	wantMoves := []models.NamedResourceID{{ID: 42, Name: "Mega Punch"}, {ID: 7, Name: "Cut"}, {ID: 1, Name: "Tackle"}}
+	if count != 3 || !reflect.DeepEqual(moves, wantMoves) {
+		t.Errorf("GetMoveList() = %v, %v, want 3, %v", count, moves, wantMoves)
+	}
+}
+
+// TestGetMoveListUsesCategoryIndex checks that the query of the category filtered and power sorted move list
+// can use the index on category and power. It needs the database configured like for InitDB and is skipped otherwise.
+func TestGetMoveListUsesCategoryIndex(t *testing.T) {
+	if _, ok := os.LookupEnv("DB_URL"); !ok {
+		t.Skip("DB_URL is not set, the test needs a database")
+	}
+	if err := InitDB(); err != nil {
+		t.Fatal(err)
+	}
+	defer CloseDB()
+	ctx := context.Background()
+	conn, err := dbpool.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Release()
+	// The table is small enough for sequential scans, which would hide whether the index is usable
+	if _, err = conn.Exec(ctx, "SET enable_seqscan = off;"); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Exec(ctx, "RESET enable_seqscan;")
+	where := buildWhereClause(MoveTable, ListFilter{Category: Physical})
+	queryString := buildQuery("SELECT move_ID, move_name FROM attack_move", where, SortInput{SortTypes: []SortType{PowerDesc}}, MoveTable, "move_ID", "move_name", Pagination{PerPage: 50, Page: 1})
+	rows, err := conn.Query(ctx, "EXPLAIN "+queryString, where.args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var plan []string
+	for rows.Next() {
+		var line string
+		if err = rows.Scan(&line); err != nil {
+			t.Fatal(err)
+		}
+		plan = append(plan, line)
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(strings.Join(plan, "\n"), "move_category_power_idx") {
+		t.Errorf("the query does not use move_category_power_idx:\n%v", strings.Join(plan, "\n"))
+	}
+}
+
+func TestReadPokemonRowsListsEachDungeonOnce(t *testing.T) {
+	// pokemonRow returns a row of the first GetPokemon query for Bulbasaur with the dungeon
+	pokemonRow := func(dungeonID interface{}, dungeonName interface{}, isSuper interface{}) []interface{} {
+		return []interface{}{1, "Bulbasaur", 1, "", nil, nil, "Seed Pokemon", 1, "Mystic Forest", dungeonID, dungeonName, isSuper}
+	}
+	mtBristle := models.NamedResourceID{ID: 3, Name: "Mt. Bristle"}
+	drenchedBluff := models.NamedResourceID{ID: 4, Name: "Drenched Bluff"}
+	tests := []struct {
+		name string
+		rows [][]interface{}
+		want []models.PokemonDungeonID
+	}{
+		{
+			name: "no dungeons",
+			rows: [][]interface{}{pokemonRow(nil, nil, nil)},
+			want: nil,
+		},
+		{
+			name: "different dungeons",
+			rows: [][]interface{}{pokemonRow(3, "Mt. Bristle", false), pokemonRow(4, "Drenched Bluff", true)},
+			want: []models.PokemonDungeonID{{Dungeon: mtBristle}, {Dungeon: drenchedBluff, IsSuper: true}},
+		},
+		{
+			name: "normal and super enemy",
+			rows: [][]interface{}{pokemonRow(3, "Mt. Bristle", false), pokemonRow(3, "Mt. Bristle", true)},
+			want: []models.PokemonDungeonID{{Dungeon: mtBristle, IsSuper: true}},
+		},
+		{
+			name: "super and normal enemy",
+			rows: [][]interface{}{pokemonRow(3, "Mt. Bristle", true), pokemonRow(4, "Drenched Bluff", false), pokemonRow(3, "Mt. Bristle", false)},
+			want: []models.PokemonDungeonID{{Dungeon: mtBristle, IsSuper: true}, {Dungeon: drenchedBluff}},
+		},
+		{
+			name: "duplicate normal enemy",
+			rows: [][]interface{}{pokemonRow(3, "Mt. Bristle", false), pokemonRow(3, "Mt. Bristle", false)},
+			want: []models.PokemonDungeonID{{Dungeon: mtBristle}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pokemon, _, dungeons, err := readPokemonRows(&fakeRows{rows: tt.rows})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pokemon.DexNumber != 1 {
+				t.Errorf("DexNumber = %v, want 1", pokemon.DexNumber)
+			}
+			if !reflect.DeepEqual(dungeons, tt.want) {
+				t.Errorf("dungeons = %+v, want %+v", dungeons, tt.want)
+			}
+		})
+	}
+}
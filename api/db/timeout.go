@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// queryTimeout is the maximum duration of a single query, 0 disables the timeout.
+var queryTimeout = 10 * time.Second
+
+// withQueryTimeout returns a context for a single query that is canceled after queryTimeout,
+// in addition to the cancellation of the parent context (e.g. when the client disconnects).
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, queryTimeout)
+}
+
+// wrapContextError adds the error of the context to the error of a query that failed because its
+// context was canceled or timed out, so callers can check for context.Canceled and context.DeadlineExceeded.
+func wrapContextError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil || errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ctx.Err(), err)
+}
+
+// timeoutRows are pgx.Rows that release the context of their query once they are read completely or closed.
+type timeoutRows struct {
+	pgx.Rows
+	ctx    context.Context
+	cancel context.CancelFunc
+	err    error
+}
+
+// Next - implementation of pgx.Rows releasing the context after the last row.
+func (r *timeoutRows) Next() bool {
+	if !r.Rows.Next() {
+		// The error needs to be checked before the context is released
+		r.err = wrapContextError(r.ctx, r.Rows.Err())
+		r.cancel()
+		return false
+	}
+	return true
+}
+
+// Err - implementation of pgx.Rows including the error of the context.
+func (r *timeoutRows) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Rows.Err()
+}
+
+// Close - implementation of pgx.Rows releasing the context.
+func (r *timeoutRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+// timeoutRow is a pgx.Row that releases the context of its query once it is scanned.
+type timeoutRow struct {
+	row    pgx.Row
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Scan - implementation of pgx.Row releasing the context.
+func (r *timeoutRow) Scan(dest ...interface{}) error {
+	defer r.cancel()
+	return wrapContextError(r.ctx, r.row.Scan(dest...))
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/janek64/pmd-dx-api/api/cache"
+	"github.com/janek64/pmd-dx-api/api/logger"
+)
+
+// dataVersionRefreshInterval is the interval in which the data version is queried again.
+var dataVersionRefreshInterval = time.Minute
+
+// dataVersion is the time of the latest update of any resource, cached in memory so
+// responses can include it without querying the database.
+var dataVersion time.Time
+
+// dataVersionMu protects dataVersion.
+var dataVersionMu sync.RWMutex
+
+// stopDataVersionRefresh stops the goroutine refreshing the data version when closed.
+var stopDataVersionRefresh chan struct{}
+
+// DataVersion returns the time of the latest update of any resource as known from the last
+// refresh. ok is false if the data version could not be queried yet.
+func DataVersion() (version time.Time, ok bool) {
+	dataVersionMu.RLock()
+	defer dataVersionMu.RUnlock()
+	return dataVersion, !dataVersion.IsZero()
+}
+
+// refreshDataVersion queries the latest updated_at of all resource tables and stores it in dataVersion.
+// A changed version is published to the subscribers of the cache, e.g. the clients of '/v1/events'.
+// The first version queried is not published, since it is not known whether the data changed.
+func refreshDataVersion(ctx context.Context, q querier) error {
+	var version time.Time
+	err := q.QueryRow(ctx, `SELECT GREATEST(
+		(SELECT MAX(updated_at) FROM ability),
+		(SELECT MAX(updated_at) FROM camp),
+		(SELECT MAX(updated_at) FROM dungeon),
+		(SELECT MAX(updated_at) FROM attack_move),
+		(SELECT MAX(updated_at) FROM pokemon),
+		(SELECT MAX(updated_at) FROM pokemon_type))`).Scan(&version)
+	if err != nil {
+		return err
+	}
+	dataVersionMu.Lock()
+	previous := dataVersion
+	dataVersion = version
+	dataVersionMu.Unlock()
+	if !previous.IsZero() && !version.Equal(previous) {
+		// The data version is still updated if the subscribers can not be notified
+		if err := cache.PublishDataVersion(version.UTC().Format(time.RFC3339)); err != nil {
+			logDataVersionError(fmt.Errorf("publishing the data version failed: %w", err))
+		}
+	}
+	return nil
+}
+
+// startDataVersionRefresh queries the data version and keeps refreshing it in the
+// background with the pool until stopDataVersionRefresh is closed. Failed refreshes
+// are logged and the previous data version is kept.
+func startDataVersionRefresh(pool *pgxpool.Pool) {
+	stop := make(chan struct{})
+	stopDataVersionRefresh = stop
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), dataVersionRefreshInterval)
+		defer cancel()
+		if err := refreshDataVersion(ctx, pool); err != nil {
+			logDataVersionError(fmt.Errorf("refreshing the data version failed: %w", err))
+		}
+	}
+	refresh()
+	go func() {
+		ticker := time.NewTicker(dataVersionRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				refresh()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// logDataVersionError logs the error of a data version refresh to the error log.
+func logDataVersionError(err error) {
+	pc, file, line, ok := runtime.Caller(1)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "refreshDataVersion: failed to fetch caller information")
+		return
+	}
+	caller := logger.CallerInformation{Pc: pc, File: file, Line: line}
+	logger.LogError(err, caller)
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/janek64/pmd-dx-api/api/cache"
+)
+
+func TestRefreshDataVersionPublishesChanges(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	cache.SetClient(client)
+	previousVersion := dataVersion
+	t.Cleanup(func() {
+		cache.SetClient(nil)
+		client.Close()
+		dataVersion = previousVersion
+	})
+	dataVersion = time.Time{}
+	subscription, err := cache.SubscribeDataVersion(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer subscription.Close()
+
+	first := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	second := time.Date(2022, 3, 2, 8, 30, 0, 0, time.FixedZone("CET", 3600))
+	steps := []struct {
+		name    string
+		version time.Time
+		publish string
+	}{
+		{name: "initial version", version: first},
+		{name: "unchanged version", version: first},
+		{name: "changed version", version: second, publish: "2022-03-02T07:30:00Z"},
+	}
+	for _, step := range steps {
+		q := &fakeQuerier{respond: func(sql string, args []interface{}) (*fakeRows, error) {
+			return &fakeRows{rows: [][]interface{}{{step.version}}}, nil
+		}}
+		if err := refreshDataVersion(context.Background(), q); err != nil {
+			t.Fatalf("%v: %v", step.name, err)
+		}
+		if version, ok := DataVersion(); !ok || !version.Equal(step.version) {
+			t.Errorf("%v: DataVersion() = %v, want %v", step.name, version, step.version)
+		}
+		select {
+		case published := <-subscription.Versions():
+			if step.publish == "" {
+				t.Errorf("%v: published %q, want no notification", step.name, published)
+			} else if published != step.publish {
+				t.Errorf("%v: published %q, want %q", step.name, published, step.publish)
+			}
+		case <-time.After(100 * time.Millisecond):
+			if step.publish != "" {
+				t.Errorf("%v: %q was not published", step.name, step.publish)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// materializedViews contains the names of all materialized views created by scripts/create-views.sql.
+var materializedViews = []string{"pokemon_defenses_view", "pokemon_group_counts_view"}
+
+// MaterializedViewsEnabled returns true if derived data is read from the materialized views.
+func MaterializedViewsEnabled() bool {
+	return useMaterializedViews
+}
+
+// RefreshMaterializedViews refreshes the data of all materialized views. The views are refreshed
+// concurrently, so the queries reading them are not blocked during the refresh, which requires the
+// unique indices created by scripts/create-views.sql. The refresh is canceled with the context.
+func RefreshMaterializedViews(ctx context.Context) error {
+	if dbquerier == nil {
+		return errors.New("database connection not initialized")
+	}
+	return refreshMaterializedViews(ctx, dbquerier)
+}
+
+// refreshMaterializedViews refreshes the materialized views one after another with the querier.
+func refreshMaterializedViews(ctx context.Context, q querier) error {
+	for _, view := range materializedViews {
+		rows, err := q.Query(ctx, fmt.Sprintf("REFRESH MATERIALIZED VIEW CONCURRENTLY %v;", view))
+		if err != nil {
+			return wrapContextError(ctx, err)
+		}
+		// The statement returns no rows, closing them waits until it is completed
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return wrapContextError(ctx, err)
+		}
+	}
+	return nil
+}
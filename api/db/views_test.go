@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRefreshMaterializedViewsConcurrently(t *testing.T) {
+	q := &fakeQuerier{respond: func(sql string, args []interface{}) (*fakeRows, error) {
+		return &fakeRows{}, nil
+	}}
+	if err := refreshMaterializedViews(context.Background(), q); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"REFRESH MATERIALIZED VIEW CONCURRENTLY pokemon_defenses_view;",
+		"REFRESH MATERIALIZED VIEW CONCURRENTLY pokemon_group_counts_view;",
+	}
+	if !reflect.DeepEqual(q.queries, want) {
+		t.Errorf("queries = %q, want %q", q.queries, want)
+	}
+}
+
+func TestRefreshMaterializedViewsStopsAtDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	q := &fakeQuerier{respond: func(sql string, args []interface{}) (*fakeRows, error) {
+		return &fakeRows{err: errConnectionLost}, nil
+	}}
+	err := refreshMaterializedViews(ctx, q)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if len(q.queries) != 1 {
+		t.Errorf("%v views were refreshed after the deadline, want the refresh to stop", len(q.queries))
+	}
+}
+
+func TestPokemonGroupCountsMatchView(t *testing.T) {
+	if _, ok := os.LookupEnv("DB_URL"); !ok {
+		t.Skip("DB_URL is not set, the test needs a database")
+	}
+	if err := InitDB(); err != nil {
+		t.Fatal(err)
+	}
+	defer CloseDB()
+	previous := useMaterializedViews
+	defer func() { useMaterializedViews = previous }()
+	ctx := context.Background()
+	// The unique indices of the views allow refreshing them concurrently
+	if err := RefreshMaterializedViews(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, groupBy := range []GroupBy{GroupByType, GroupByCamp, GroupByEvolutionStage} {
+		useMaterializedViews = false
+		direct, err := GetPokemonGroupCounts(ctx, groupBy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		useMaterializedViews = true
+		view, err := GetPokemonGroupCounts(ctx, groupBy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(view, direct) {
+			t.Errorf("%v: the view returned %v, the query %v", groupBy, view, direct)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+// Package debug defines the debug features of the pmd-dx-api, which are only
+// included in builds with the build tag "debug" (go build -tags debug).
+// Release builds exclude them entirely to reduce the attack surface.
+package debug
@@ -0,0 +1,13 @@
+//go:build !debug
+// +build !debug
+
+package debug
+
+import "github.com/julienschmidt/httprouter"
+
+// Enabled is true if the debug features are included in the build.
+const Enabled = false
+
+// RegisterRoutes does nothing since the debug routes are not included in the build.
+func RegisterRoutes(router *httprouter.Router, middleware func(httprouter.Handle) httprouter.Handle) {
+}
@@ -0,0 +1,38 @@
+//go:build debug
+// +build debug
+
+package debug
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Enabled is true if the debug features are included in the build.
+const Enabled = true
+
+// RegisterRoutes registers all debug routes with the router. The middleware
+// is applied to each route and should restrict the access to trusted clients.
+func RegisterRoutes(router *httprouter.Router, middleware func(httprouter.Handle) httprouter.Handle) {
+	// Runtime profiles of net/http/pprof, the index lists all of them
+	router.GET("/debug/pprof/", middleware(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		pprof.Index(w, r)
+	}))
+	router.GET("/debug/pprof/:profile", middleware(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		// Profiles that are not part of the runtime have their own handlers
+		switch ps.ByName("profile") {
+		case "cmdline":
+			pprof.Cmdline(w, r)
+		case "profile":
+			pprof.Profile(w, r)
+		case "symbol":
+			pprof.Symbol(w, r)
+		case "trace":
+			pprof.Trace(w, r)
+		default:
+			pprof.Index(w, r)
+		}
+	}))
+}
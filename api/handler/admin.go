@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/janek64/pmd-dx-api/api/cache"
+	"github.com/julienschmidt/httprouter"
+)
+
+// CacheStatsHandler handles requests on '/admin/cache/stats' and answers with the number
+// of keys and cached responses and the memory usage of the redis cache.
+func CacheStatsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	stats, err := cache.Stats(r.Context())
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	responseJSON := orderedmap.New()
+	responseJSON.Set("keys", stats.Keys)
+	responseJSON.Set("responses", stats.Responses)
+	responseJSON.Set("usedMemory", stats.UsedMemory)
+	responseJSON.Set("maxMemory", stats.MaxMemory)
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	writeJSONOnly(w, json)
+}
+
+// CachePurgeHandler handles DELETE requests on '/admin/cache' and deletes all cached responses.
+// If the query parameter "url" is provided, only the cached responses of this URL are deleted in
+// all formats, e.g. "/v1/pokemon/25". Answers with the number of deleted entries.
+func CachePurgeHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var deleted int
+	var err error
+	if value := r.URL.Query().Get("url"); value != "" {
+		purgeURL, parseErr := url.Parse(value)
+		if parseErr != nil || !strings.HasPrefix(value, "/") {
+			AnswerWithValidationErrors(w, []ValidationError{{Parameter: "url", Reason: "expected a path starting with '/', e.g. /v1/pokemon/25"}})
+			return
+		}
+		deleted, err = purgeFormatVariants(purgeURL)
+	} else {
+		deleted, err = cache.PurgeAll()
+	}
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	responseJSON := orderedmap.New()
+	responseJSON.Set("deleted", deleted)
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	writeJSONOnly(w, json)
+}
+
+// purgeFormatVariants deletes the cached responses of the URL in all formats, since the responses in other
+// formats are cached with the format parameter, e.g. "format=csv", even if they were requested with the Accept
+// header. A URL with the format parameter only deletes the response in this format.
+func purgeFormatVariants(u *url.URL) (int, error) {
+	if u.Query().Get("format") != "" {
+		return cache.PurgeURL(u)
+	}
+	deleted, err := cache.PurgeURL(u)
+	if err != nil {
+		return deleted, err
+	}
+	for _, format := range formats {
+		queryParams := u.Query()
+		queryParams.Set("format", format)
+		variant := *u
+		variant.RawQuery = queryParams.Encode()
+		n, err := cache.PurgeURL(&variant)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
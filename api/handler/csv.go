@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/janek64/pmd-dx-api/api/models"
+)
+
+// CSVRequested checks if the response should be encoded as CSV, either requested with
+// the query parameter "format=csv" or by preferring "text/csv" in the Accept header.
+func CSVRequested(r *http.Request) bool {
+	return requestedFormat(r) == FormatCSV
+}
+
+// writeCSV writes the rows as a CSV response with code 200.
+func writeCSV(w http.ResponseWriter, rows [][]string) {
+	var buffer bytes.Buffer
+	csvWriter := csv.NewWriter(&buffer)
+	if err := csvWriter.WriteAll(rows); err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	setChecksumHeader(w, buffer.Bytes())
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buffer.Bytes())
+}
+
+// writeListCSV writes a resource list as CSV with the columns id, name and url.
+func writeListCSV(w http.ResponseWriter, resources []models.NamedResourceID, resourcesWithURL []models.NamedResourceURL) {
+	rows := [][]string{{"id", "name", "url"}}
+	for i, resource := range resources {
+		rows = append(rows, []string{strconv.Itoa(resource.ID), resourcesWithURL[i].Name, resourcesWithURL[i].URL})
+	}
+	writeCSV(w, rows)
+}
+
+// writeObjectCSV converts the JSON object of a response into a CSV header and a single row.
+// Nested resources are replaced with their names and arrays are joined with semicolons.
+func writeObjectCSV(w http.ResponseWriter, responseJSON []byte) {
+	object := orderedmap.New()
+	if err := json.Unmarshal(responseJSON, object); err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	var header, row []string
+	for _, key := range object.Keys() {
+		value, _ := object.Get(key)
+		cell, ok := csvCell(value)
+		if !ok {
+			continue
+		}
+		header = append(header, key)
+		row = append(row, cell)
+	}
+	writeCSV(w, [][]string{header, row})
+}
+
+// csvCell returns the representation of a JSON value in a CSV cell. Objects are represented
+// by their name and ok is false if the value can not be represented.
+func csvCell(value interface{}) (cell string, ok bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case orderedmap.OrderedMap:
+		name, ok := v.Get("name")
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprint(name), true
+	case []interface{}:
+		var cells []string
+		for _, element := range v {
+			if elementCell, ok := csvCell(element); ok {
+				cells = append(cells, elementCell)
+			}
+		}
+		return strings.Join(cells, ";"), true
+	}
+	return "", false
+}
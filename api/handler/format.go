@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// The formats of the responses, as selected with the query parameter "format".
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatJSONAPI  = "jsonapi"
+	FormatXML      = "xml"
+	FormatProtobuf = "protobuf"
+)
+
+// formats are all formats of the responses.
+var formats = []string{FormatJSON, FormatCSV, FormatJSONAPI, FormatXML, FormatProtobuf}
+
+// formatMediaTypes maps the media types of the Accept header to the formats they request.
+// The wildcards accept the default JSON representation.
+var formatMediaTypes = map[string]string{
+	"application/json":       FormatJSON,
+	"text/csv":               FormatCSV,
+	jsonAPIMediaType:         FormatJSONAPI,
+	"application/xml":        FormatXML,
+	"text/xml":               FormatXML,
+	"application/x-protobuf": FormatProtobuf,
+	"application/*":          FormatJSON,
+	"*/*":                    FormatJSON,
+}
+
+// NegotiateFormat returns the format of the response for the request. The query parameter "format" takes
+// precedence over the Accept header. Otherwise the media range of the Accept header with the highest quality
+// value ("q") that matches a format is chosen, preferring exact media types over wildcards and earlier ranges
+// over later ones if the quality is the same. Requests without a matching media range get JSON.
+func NegotiateFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+	format, bestQuality, bestExact := FormatJSON, 0.0, false
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		candidate, ok := formatMediaTypes[mediaType]
+		if !ok {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			// Ranges with invalid quality values are ignored
+			if quality, err = strconv.ParseFloat(q, 64); err != nil || quality < 0 || quality > 1 {
+				continue
+			}
+		}
+		exact := !strings.HasSuffix(mediaType, "/*")
+		// A quality of 0 marks the media type as not acceptable
+		if quality == 0 || quality < bestQuality || (quality == bestQuality && (bestExact || !exact)) {
+			continue
+		}
+		format, bestQuality, bestExact = candidate, quality, exact
+	}
+	return format
+}
+
+// requestedFormat returns the format of the response, negotiated once by the FormatParams middleware
+// if it was called, or negotiated for the request otherwise.
+func requestedFormat(r *http.Request) string {
+	if formatParams, ok := r.Context().Value(FormatParamsKey).(FormatParams); ok && formatParams.Format != "" {
+		return formatParams.Format
+	}
+	return NegotiateFormat(r)
+}
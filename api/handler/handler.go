@@ -3,21 +3,29 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
-	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/iancoleman/orderedmap"
+	"github.com/janek64/pmd-dx-api/api/cache"
 	"github.com/janek64/pmd-dx-api/api/db"
 	"github.com/janek64/pmd-dx-api/api/logger"
 	"github.com/janek64/pmd-dx-api/api/models"
 	"github.com/julienschmidt/httprouter"
+	"golang.org/x/sync/errgroup"
 )
 
 // ContextKey defines alls valid Context keys for requests to this API.
@@ -26,18 +34,170 @@ type ContextKey int
 const (
 	ResourceListParamsKey ContextKey = iota
 	FieldLimitingParamsKey
+	FormatParamsKey
+	NestedListParamsKey
 )
 
 // ResourceListParams contains the parsed parameter values for requests to resource lists.
 type ResourceListParams struct {
 	Sort       db.SortInput
 	Pagination db.Pagination
+	Filter     db.ListFilter
+	// OffsetPagination is true if the client used offset and limit instead of page and per_page
+	OffsetPagination bool
+	// CountOnly is true if only the number of matching resources is requested
+	CountOnly bool
+	// Errors contains all invalid parameter values of the request
+	Errors []ValidationError
+}
+
+// NestedListParams contains the parsed parameter values for a list nested in a single resource.
+type NestedListParams struct {
+	// Sort is the requested sorting or empty for the default order
+	Sort string
+	// Paginated is true if the client requested a page of the nested list instead of the full list
+	Paginated  bool
+	Pagination db.Pagination
+	// Errors contains all invalid parameter values of the request
+	Errors []ValidationError
+}
+
+// Sort values of nested lists, each list supports a subset of them
+const (
+	DefenderAsc  = "defender_asc"
+	DefenderDesc = "defender_desc"
+	StrengthAsc  = "strength_asc"
+	StrengthDesc = "strength_desc"
+)
+
+// nestedListParams extracts the NestedListParams of the nested list with the name from the request context.
+func nestedListParams(r *http.Request, listName string) (NestedListParams, bool) {
+	params, ok := r.Context().Value(NestedListParamsKey).(map[string]NestedListParams)
+	if !ok {
+		return NestedListParams{}, false
+	}
+	listParams, ok := params[listName]
+	return listParams, ok
+}
+
+// paginateNested returns the bounds of the requested page for a nested list with the given length.
+func paginateNested(params NestedListParams, length int) (start int, end int) {
+	if !params.Paginated {
+		return 0, length
+	}
+	start = (params.Pagination.Page - 1) * params.Pagination.PerPage
+	if start > length {
+		start = length
+	}
+	end = start + params.Pagination.PerPage
+	if end > length {
+		end = length
+	}
+	return start, end
+}
+
+// ValidationError describes an invalid value of a query parameter.
+type ValidationError struct {
+	Parameter string `json:"parameter"`
+	Reason    string `json:"reason"`
 }
 
 // FieldLimitingParams contains the parsed parameter values for requests to resource lists.
 type FieldLimitingParams struct {
 	FieldLimitingEnabled bool
-	Fields               []string
+	Fields               []FieldSelection
+	// ExcludeFields are removed from the response if no Fields are selected
+	ExcludeFields []FieldSelection
+	// StrictFields is true if selecting fields that do not exist in the response should be rejected
+	StrictFields bool
+}
+
+// FieldSelection is a field that should be included in the response. If Children is not empty,
+// only these fields of the nested objects of the field are included, e.g. for "moves(name,level)".
+type FieldSelection struct {
+	Name     string
+	Children []FieldSelection
+}
+
+// FormatParams contains the parsed parameter values for the representation of responses.
+type FormatParams struct {
+	Flat bool
+	// SortKeys is true if the keys of all JSON objects should be sorted alphabetically
+	SortKeys bool
+	// Template is the name of the registered response template the response is reshaped with, if not empty
+	Template string
+	// Raw is true if an authorized client requested the raw database representation
+	Raw bool
+	// Format is the format of the response negotiated with NegotiateFormat, e.g. "csv"
+	Format string
+}
+
+// publicBaseURL overrides the base URL of all generated resource URLs if it is not empty.
+var publicBaseURL string
+
+// spriteBaseURL is the base URL of the pokemon sprites, which are omitted if it is empty.
+var spriteBaseURL string
+
+// trustProxyHeaders is true if the X-Forwarded-Host and X-Forwarded-Proto headers of a reverse proxy are used for the resource URLs.
+var trustProxyHeaders bool
+
+// defaultScheme is the scheme of the resource URLs of requests without TLS if no scheme is forwarded by a proxy.
+var defaultScheme = "http"
+
+// InitHandler reads the configuration of the handlers from the environment.
+// The optional PUBLIC_BASE_URL is used for all generated resource URLs instead of the request host.
+// The optional SPRITE_BASE_URL enables the sprite URLs of pokemon.
+// The optional TRUST_PROXY_HEADERS uses the host and scheme forwarded by a reverse proxy for the resource URLs,
+// invalid values are ignored. The optional DEFAULT_SCHEME ("http" or "https") is used for requests without TLS
+// if no scheme is forwarded.
+func InitHandler() {
+	trustProxyHeaders, _ = strconv.ParseBool(os.Getenv("TRUST_PROXY_HEADERS"))
+	if value := strings.ToLower(os.Getenv("DEFAULT_SCHEME")); value == "http" || value == "https" {
+		defaultScheme = value
+	}
+	if value, ok := os.LookupEnv("PUBLIC_BASE_URL"); ok {
+		publicBaseURL = strings.TrimSuffix(value, "/")
+	}
+	if value, ok := os.LookupEnv("SPRITE_BASE_URL"); ok {
+		spriteBaseURL = strings.TrimSuffix(value, "/")
+	}
+}
+
+// BaseURL returns the absolute base URL for the resource URLs generated for the request.
+// Uses PUBLIC_BASE_URL if it is set and the host of the request otherwise. Behind a trusted
+// proxy, the forwarded host and scheme replace the host and scheme of the request.
+func BaseURL(r *http.Request) string {
+	if strings.Contains(publicBaseURL, "://") {
+		return publicBaseURL
+	}
+	scheme := requestScheme(r)
+	if publicBaseURL != "" {
+		return scheme + "://" + publicBaseURL
+	}
+	host := r.Host
+	if forwardedHost := firstHeaderValue(r, "X-Forwarded-Host"); trustProxyHeaders && forwardedHost != "" {
+		host = forwardedHost
+	}
+	return scheme + "://" + host
+}
+
+// requestScheme returns the scheme the client used for the request: the scheme forwarded by a trusted
+// proxy, "https" for requests with TLS and the DEFAULT_SCHEME otherwise.
+func requestScheme(r *http.Request) string {
+	if trustProxyHeaders {
+		if forwardedProto := strings.ToLower(firstHeaderValue(r, "X-Forwarded-Proto")); forwardedProto == "http" || forwardedProto == "https" {
+			return forwardedProto
+		}
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return defaultScheme
+}
+
+// firstHeaderValue returns the first value of a comma-separated header, which proxies append their values to.
+func firstHeaderValue(r *http.Request, name string) string {
+	return strings.TrimSpace(strings.Split(r.Header.Get(name), ",")[0])
 }
 
 // Default404Handler handles requests on all undefined routes. It sets the status to 404
@@ -49,15 +209,130 @@ func Default404Handler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	w.Header().Set("X-Content-Type-Options", "nosniff")
 	responseRecorder.Write([]byte("404 page not found"))
-	logger.LogRequest(r, responseRecorder)
+	if !logger.AccessLogExcluded(r.URL.Path) {
+		logger.LogRequest(r, responseRecorder)
+	}
+}
+
+// writeJSON writes the JSON as a successful response with an explicit UTF-8 charset,
+// since the names of resources can contain non-ASCII characters. Requests for CSV are
+// answered with the JSON object converted to a single CSV row instead, requests for
+// JSON:API with the JSON object wrapped in a JSON:API document, requests for XML
+// with the JSON object converted to XML elements and requests for Protocol Buffers
+// with the JSON object converted to the message of the endpoint.
+func writeJSON(w http.ResponseWriter, r *http.Request, json []byte) {
+	formatParams, ok := r.Context().Value(FormatParamsKey).(FormatParams)
+	if ok && formatParams.Template != "" {
+		reshaped, err := applyTemplate(formatParams.Template, json)
+		if err != nil {
+			ErrorAndLog500(w, err)
+			return
+		}
+		json = reshaped
+	}
+	if CSVRequested(r) {
+		writeObjectCSV(w, json)
+		return
+	}
+	if JSONAPIRequested(r) {
+		writeJSONAPI(w, r, json, ok && formatParams.SortKeys)
+		return
+	}
+	if XMLRequested(r) {
+		writeXML(w, json)
+		return
+	}
+	if ProtobufRequested(r) {
+		writeProtobuf(w, r, json)
+		return
+	}
+	if ok && formatParams.SortKeys {
+		sorted, err := sortJSONKeys(json)
+		if err != nil {
+			ErrorAndLog500(w, err)
+			return
+		}
+		json = sorted
+	}
+	writeJSONOnly(w, json)
+}
+
+// sortJSONKeys encodes the JSON again with the keys of all objects in alphabetical order instead of
+// the insertion order of the orderedmaps. Numbers are kept as they are instead of being converted to floats.
+func sortJSONKeys(body []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	// Maps are always encoded with sorted keys
+	return json.Marshal(value)
+}
+
+// writeJSONOnly writes the JSON as a successful response like writeJSON, but for responses
+// that are not available as CSV.
+func writeJSONOnly(w http.ResponseWriter, json []byte) {
+	setChecksumHeader(w, json)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(json)
+}
+
+// ChecksumHeader is the header containing the hex encoded SHA-256 checksum of the response body.
+const ChecksumHeader = "X-Content-SHA256"
+
+// setChecksumHeader sets the SHA-256 checksum of the body, so clients can verify the integrity of the response.
+func setChecksumHeader(w http.ResponseWriter, body []byte) {
+	w.Header().Set(ChecksumHeader, fmt.Sprintf("%x", sha256.Sum256(body)))
+}
+
+// AnswerWithValidationErrors answers the request with status 400 (Bad Request)
+// and a JSON listing all invalid parameters.
+func AnswerWithValidationErrors(w http.ResponseWriter, errs []ValidationError) {
+	AnswerWithErrors(w, http.StatusBadRequest, errs)
+}
+
+// AnswerWithErrors answers the request with the status code and a JSON listing the errors
+// like AnswerWithValidationErrors, e.g. for a missing authorization.
+func AnswerWithErrors(w http.ResponseWriter, status int, errs []ValidationError) {
+	responseJSON := orderedmap.New()
+	responseJSON.Set("errors", errs)
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(json)
 }
 
+// statusClientClosedRequest is the non-standard status code for requests canceled by the client.
+const statusClientClosedRequest = 499
+
 // ErrorAndLog500 is a wrapper around http.Error() that
 // writes the error message to the error log instead of returning
 // it to the client. Should only be used for internal server errors.
+// Errors of canceled queries are answered with code 499 (client closed
+// the request) without logging them, and errors of timed out queries with
+// code 503 (Service Unavailable). The request ID of the response is included in the
+// message and the log entry, so reported failures can be found in the error log.
 func ErrorAndLog500(w http.ResponseWriter, err error) {
-	// Use http.Error() with default message
-	http.Error(w, "Something went wrong on our side. Please contact the administrator.", http.StatusInternalServerError)
+	if errors.Is(err, context.Canceled) {
+		// The client is gone, so the status code is only visible in the access log
+		w.WriteHeader(statusClientClosedRequest)
+		return
+	}
+	requestID := w.Header().Get(logger.RequestIDHeader)
+	if errors.Is(err, context.DeadlineExceeded) {
+		http.Error(w, "The request could not be completed in time. Please try again later.", http.StatusServiceUnavailable)
+	} else if requestID != "" {
+		http.Error(w, fmt.Sprintf("Something went wrong on our side. Please contact the administrator with the request ID %v.", requestID), http.StatusInternalServerError)
+	} else {
+		// Use http.Error() with default message
+		http.Error(w, "Something went wrong on our side. Please contact the administrator.", http.StatusInternalServerError)
+	}
 	// Gather caller information to pass it to the logger
 	pc, file, line, ok := runtime.Caller(1)
 	if !ok {
@@ -65,6 +340,9 @@ func ErrorAndLog500(w http.ResponseWriter, err error) {
 		return
 	}
 	caller := logger.CallerInformation{Pc: pc, File: file, Line: line}
+	if requestID != "" {
+		err = fmt.Errorf("request %v: %w", requestID, err)
+	}
 	// Write to the error logger
 	logErr := logger.LogError(err, caller)
 	if logErr != nil {
@@ -74,16 +352,67 @@ func ErrorAndLog500(w http.ResponseWriter, err error) {
 
 // answerWithListJSON transforms the provided resources to a list with URLs, packages
 // them in a JSON and sends it as a response with the provided ResponseWriter.
-func answerWithListJSON(count int, resources []models.NamedResourceID, resourceTypeName string, pagination db.Pagination, w http.ResponseWriter, r *http.Request) {
+func answerWithListJSON(count int, resources []models.NamedResourceID, resourceTypeName string, params ResourceListParams, w http.ResponseWriter, r *http.Request) {
+	pagination := params.Pagination
+	// Answer with the raw database representation if requested
+	if rawRequested(r) {
+		w.Header().Set("Link", buildLinkHeader(BaseURL(r), r.URL, count, pagination, params.OffsetPagination))
+		answerWithRawJSON(map[string]interface{}{"count": count, "results": resources}, w)
+		return
+	}
+	// Fetch the types of all pokemon of the page at once if they should be included
+	var pokemonTypes map[int][]models.NamedResourceID
+	if resourceTypeName == "pokemon" && includeRequested(r, "types") && len(resources) > 0 {
+		dexNumbers := make([]int, 0, len(resources))
+		for _, resource := range resources {
+			dexNumbers = append(dexNumbers, resource.ID)
+		}
+		var err error
+		if pokemonTypes, err = db.GetTypesOfPokemon(r.Context(), dexNumbers); err != nil {
+			ErrorAndLog500(w, err)
+			return
+		}
+	}
 	// Build representation with URL instead of ID
 	var resourcesWithURL []models.NamedResourceURL
 	for _, resource := range resources {
-		resourcesWithURL = append(resourcesWithURL, resource.ToNamedResourceURL(r.Host, resourceTypeName))
+		resourceWithURL := resource.ToNamedResourceURL(BaseURL(r), resourceTypeName)
+		// Add the sprite and the included types to pokemon
+		if resourceTypeName == "pokemon" {
+			resourceWithURL.Sprite = models.SpriteURL(spriteBaseURL, resource.ID)
+			if pokemonTypes != nil {
+				resourceWithURL.Types = transformToURLResources(pokemonTypes[resource.ID], BaseURL(r), "types")
+			}
+		}
+		resourcesWithURL = append(resourcesWithURL, resourceWithURL)
+	}
+	// The count is unknown if it was skipped or could not be queried
+	countKnown := count >= 0
+	if !countKnown {
+		// Only a page that is not full reveals the total count, which is needed for the Link header
+		if len(resources) < pagination.PerPage && (len(resources) > 0 || pagination.Page == 1) {
+			count = (pagination.Page-1)*pagination.PerPage + len(resources)
+		}
+	}
+	// Generate the Link header for pagination
+	w.Header().Set("Link", buildLinkHeader(BaseURL(r), r.URL, count, pagination, params.OffsetPagination))
+	// Answer with the columns id, name and url if CSV is requested
+	if CSVRequested(r) {
+		writeListCSV(w, resources, resourcesWithURL)
+		return
 	}
 	// Build the response JSON as a map
 	responseJSON := orderedmap.New()
-	responseJSON.Set("count", count)
-	responseJSON.Set("results", resourcesWithURL)
+	if countKnown {
+		responseJSON.Set("count", count)
+	}
+	// The effective page size, which may be smaller than the requested one
+	responseJSON.Set("perPage", pagination.PerPage)
+	if r.URL.Query().Get("as") == "map" {
+		responseJSON.Set("results", mapResourcesByName(resources, resourcesWithURL))
+	} else {
+		responseJSON.Set("results", resourcesWithURL)
+	}
 	// Extract the FieldLimitingParams from the context with a type assertion
 	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
 	if !ok {
@@ -91,51 +420,179 @@ func answerWithListJSON(count int, resources []models.NamedResourceID, resourceT
 		return
 	}
 	// Perform field limiting if necessary
-	limitResultFields(responseJSON, fieldLimitParams)
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
 	// Transform the map to JSON
 	json, err := json.Marshal(responseJSON)
 	if err != nil {
 		ErrorAndLog500(w, err)
 		return
 	}
-	// Generate the headers for pagination
-	// Calculate the page numbers
-	lastPage := count/pagination.PerPage + 1
-	if count%pagination.PerPage == 0 {
-		lastPage -= 1
+	// Write the response
+	writeJSON(w, r, json)
+}
+
+// mapResourcesByName builds the map representation of a resource list, using the names as keys
+// in the order of the list. Since names can not be used twice, a resource whose name is already
+// taken by a previous resource of the list is keyed by its ID instead.
+func mapResourcesByName(resources []models.NamedResourceID, resourcesWithURL []models.NamedResourceURL) *orderedmap.OrderedMap {
+	resourceMap := orderedmap.New()
+	for i, resource := range resourcesWithURL {
+		entry := orderedmap.New()
+		entry.Set("id", resources[i].ID)
+		entry.Set("url", resource.URL)
+		if resource.Sprite != "" {
+			entry.Set("sprite", resource.Sprite)
+		}
+		if resource.Types != nil {
+			entry.Set("types", resource.Types)
+		}
+		key := resource.Name
+		if _, taken := resourceMap.Get(key); taken {
+			key = strconv.Itoa(resources[i].ID)
+		}
+		resourceMap.Set(key, entry)
 	}
-	nextPage := pagination.Page + 1
-	previousPage := pagination.Page - 1
-	// Generate the URLs
-	requestURL := r.Host + r.URL.String()
-	// If no page URL parameter was provided, add it
-	match, err := regexp.Match(`.+[?&]page=\d*(&.+)?`, []byte(requestURL))
+	return resourceMap
+}
+
+// fetchWithCounts calls fetch to query a single resource of the table and, if requested with "include=counts",
+// queries the counts of its reverse relationships concurrently. The counts are nil if they were not requested.
+func fetchWithCounts(r *http.Request, table db.ListTable, searchInput db.SearchInput, fetch func(ctx context.Context) error) ([]models.RelationshipCount, error) {
+	if !includeRequested(r, "counts") {
+		return nil, fetch(r.Context())
+	}
+	var counts []models.RelationshipCount
+	g, ctx := errgroup.WithContext(r.Context())
+	g.Go(func() error {
+		return fetch(ctx)
+	})
+	g.Go(func() (err error) {
+		counts, err = db.GetReverseCounts(ctx, table, searchInput)
+		return err
+	})
+	return counts, g.Wait()
+}
+
+// setRelationshipCounts adds the counts of the reverse relationships to the responseJSON.
+func setRelationshipCounts(responseJSON *orderedmap.OrderedMap, counts []models.RelationshipCount) {
+	for _, count := range counts {
+		responseJSON.Set(count.Name, count.Count)
+	}
+}
+
+// includeRequested checks if the related resource is part of the comma-separated "include" argument.
+func includeRequested(r *http.Request, related string) bool {
+	for _, include := range strings.Split(r.URL.Query().Get("include"), ",") {
+		if strings.TrimSpace(include) == related {
+			return true
+		}
+	}
+	return false
+}
+
+// answerWithCountJSON sends the number of entries of the resource list table
+// matching the ListFilter as a response with the provided ResponseWriter.
+func answerWithCountJSON(table db.ListTable, filter db.ListFilter, w http.ResponseWriter, r *http.Request) {
+	count, err := db.GetListCount(r.Context(), table, filter)
 	if err != nil {
 		ErrorAndLog500(w, err)
 		return
 	}
-	if !match {
-		// Check if there is already a question mark followed by characters
-		match, err = regexp.Match(`.+\?.+`, []byte(requestURL))
-		if err != nil {
-			ErrorAndLog500(w, err)
+	// Build the response JSON as a map
+	responseJSON := orderedmap.New()
+	responseJSON.Set("count", count)
+	// Transform the map to JSON
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Write the response
+	writeJSON(w, r, json)
+}
+
+// CountHandler returns the handler for requests on '/v1/<resource>/count', which returns only the number of
+// resources of the table like "count_only=true" on the list, applying the same filters without fetching any rows.
+func CountHandler(table db.ListTable) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		// Extract the ResourceListParams from the context with a type assertion
+		params, ok := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
+		if !ok {
+			ErrorAndLog500(w, errors.New("missing ResourceListParams"))
 			return
 		}
-		if match {
-			requestURL = fmt.Sprintf("%v&page=%v", requestURL, pagination.Page)
-		} else {
-			requestURL = fmt.Sprintf("%v?page=%v", requestURL, pagination.Page)
+		// Answer with all invalid parameters at once
+		if len(params.Errors) > 0 {
+			AnswerWithValidationErrors(w, params.Errors)
+			return
 		}
-
+		answerWithCountJSON(table, params.Filter, w, r)
 	}
-	re, err := regexp.Compile(`([?&])page=\d*`)
+}
+
+// rawRequested checks if the FormatParams of the request context request the raw database representation.
+func rawRequested(r *http.Request) bool {
+	formatParams, ok := r.Context().Value(FormatParamsKey).(FormatParams)
+	return ok && formatParams.Raw
+}
+
+// answerWithRawJSON sends the database entries as JSON without any transformation. The response
+// is marked as private since it is only available for authorized clients and is not stable.
+func answerWithRawJSON(entries map[string]interface{}, w http.ResponseWriter) {
+	json, err := json.Marshal(entries)
 	if err != nil {
 		ErrorAndLog500(w, err)
 		return
 	}
-	nextURL := re.ReplaceAllString(requestURL, fmt.Sprintf("${1}page=%v", nextPage))
-	previousURL := re.ReplaceAllString(requestURL, fmt.Sprintf("${1}page=%v", previousPage))
-	lastURL := re.ReplaceAllString(requestURL, fmt.Sprintf("${1}page=%v", lastPage))
+	// Write the response
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeJSONOnly(w, json)
+}
+
+// buildLinkHeader generates the Link header with the next, previous and last page for the
+// requestURL of a resource list with count resources and the provided Pagination. The URLs use
+// offset instead of page if offsetPagination is true. Relations without a page are set to null.
+// If the count is unknown (negative), the last relation is omitted and a next page is assumed.
+func buildLinkHeader(baseURL string, requestURL *url.URL, count int, pagination db.Pagination, offsetPagination bool) string {
+	// Calculate the page numbers
+	lastPage := count/pagination.PerPage + 1
+	if count%pagination.PerPage == 0 {
+		lastPage -= 1
+	}
+	// An empty list still has a first page
+	if lastPage < 1 {
+		lastPage = 1
+	}
+	if count < 0 {
+		lastPage = pagination.Page + 1
+	}
+	nextPage := pagination.Page + 1
+	previousPage := pagination.Page - 1
+	// Use the pagination style of the request for the URLs
+	pageKey := "page"
+	pageValue := func(page int) int { return page }
+	if offsetPagination {
+		pageKey = "offset"
+		pageValue = func(page int) int { return (page - 1) * pagination.PerPage }
+	}
+	// Generate the URLs by replacing the page in the parsed query, which leaves all other parameters untouched
+	// except for the page size, which is replaced with the effective one if it was requested
+	queryParams := requestURL.Query()
+	for _, perPageKey := range []string{"per_page", "limit"} {
+		if queryParams.Has(perPageKey) {
+			queryParams.Set(perPageKey, strconv.Itoa(pagination.PerPage))
+		}
+	}
+	pageURL := func(page int) string {
+		queryParams.Set(pageKey, strconv.Itoa(pageValue(page)))
+		return fmt.Sprintf("%v%v?%v", baseURL, requestURL.Path, queryParams.Encode())
+	}
+	nextURL := pageURL(nextPage)
+	previousURL := pageURL(previousPage)
+	lastURL := pageURL(lastPage)
 	// Set null values when links should not be provided
 	if pagination.Page == 1 {
 		previousURL = "null"
@@ -146,17 +603,14 @@ func answerWithListJSON(count int, resources []models.NamedResourceID, resourceT
 		nextURL = "null"
 		previousURL = "null"
 	}
-	// Set the Link header
-	linkHeader := fmt.Sprintf("<%v>; rel=\"next\", <%v>; rel=\"previous\", <%v>; rel=\"last\"", nextURL, previousURL, lastURL)
-	w.Header().Set("Link", linkHeader)
-	// Write the response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(json)
+	if count < 0 {
+		return fmt.Sprintf("<%v>; rel=\"next\", <%v>; rel=\"previous\"", nextURL, previousURL)
+	}
+	return fmt.Sprintf("<%v>; rel=\"next\", <%v>; rel=\"previous\", <%v>; rel=\"last\"", nextURL, previousURL, lastURL)
 }
 
-// generateSearchInput decides if a db search argument is an ID or a name and generates the corresponding db.SearchInput.
-func generateSearchInput(arg string) db.SearchInput {
+// GenerateSearchInput decides if a db search argument is an ID or a name and generates the corresponding db.SearchInput.
+func GenerateSearchInput(arg string) db.SearchInput {
 	var searchInput db.SearchInput
 	// Check if the search argument provided is an ID or a name
 	// strconv.Atoi will return an error for non-numeric strings (name)
@@ -172,6 +626,72 @@ func generateSearchInput(arg string) db.SearchInput {
 	return searchInput
 }
 
+// generatePokemonSearchInput generates the SearchInput for a pokemon like GenerateSearchInput. Forms are stored
+// with the form in parentheses after the name of the species, so the "form" argument is added to a name,
+// e.g. "deoxys" with the form "attack" is searched as "Deoxys (Attack)". IDs already identify a single form.
+func generatePokemonSearchInput(r *http.Request, arg string) db.SearchInput {
+	searchInput := GenerateSearchInput(arg)
+	if form := r.URL.Query().Get("form"); form != "" && searchInput.SearchType == db.Name {
+		searchInput = GenerateSearchInput(fmt.Sprintf("%v (%v)", arg, form))
+	}
+	return searchInput
+}
+
+// answerNotFound answers a request for a single resource that was not found with code 404 (Not Found).
+// If a pokemon species with forms is requested by its name, the forms are returned with code 300 (Multiple Choices).
+// If the "match" argument requests a prefix or fuzzy search for a name, the candidates with similar names
+// are returned with code 300 instead, if there are any.
+func answerNotFound(w http.ResponseWriter, r *http.Request, err error, table db.ListTable, resourceTypeName string, searchInput db.SearchInput) {
+	// The name of a species does not match its forms exactly, so they need to be chosen explicitly
+	if table == db.PokemonTable && searchInput.SearchType == db.Name && !strings.Contains(searchInput.Name, "(") {
+		forms, formErr := db.GetPokemonForms(r.Context(), searchInput.Name)
+		if formErr != nil {
+			ErrorAndLog500(w, formErr)
+			return
+		}
+		if len(forms) > 0 {
+			message := fmt.Sprintf("pokemon '%v' has multiple forms, select one with the parameter 'form' or by its ID", searchInput.Name)
+			answerWithCandidates(w, r, message, forms, resourceTypeName)
+			return
+		}
+	}
+	match := r.URL.Query().Get("match")
+	// Invalid values are ignored and only exact matches are returned
+	if searchInput.SearchType != db.Name || match == "" || !MatchParameter.Allows(match) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	searchInput.SearchType = db.SearchType(match)
+	candidates, candidateErr := db.GetCandidates(r.Context(), table, searchInput)
+	if candidateErr != nil {
+		ErrorAndLog500(w, candidateErr)
+		return
+	}
+	if len(candidates) == 0 {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	answerWithCandidates(w, r, err.Error(), candidates, resourceTypeName)
+}
+
+// answerWithCandidates answers with code 300 (Multiple Choices) and the resources the client can choose from.
+func answerWithCandidates(w http.ResponseWriter, r *http.Request, message string, candidates []models.NamedResourceID, resourceTypeName string) {
+	// Build the response JSON with a map
+	responseJSON := orderedmap.New()
+	responseJSON.Set("message", message)
+	responseJSON.Set("candidates", transformToURLResources(candidates, BaseURL(r), resourceTypeName))
+	// Transform the map to JSON
+	json, jsonErr := json.Marshal(responseJSON)
+	if jsonErr != nil {
+		ErrorAndLog500(w, jsonErr)
+		return
+	}
+	// Write the response
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusMultipleChoices)
+	w.Write(json)
+}
+
 // transformToURLResources transforms a slice of NamedResources with IDs to NamedResources with URLs and returns it.
 func transformToURLResources(resources []models.NamedResourceID, instanceURL string, resourceTypeName string) []models.NamedResourceURL {
 	var resourcesWithURL []models.NamedResourceURL
@@ -183,53 +703,1153 @@ func transformToURLResources(resources []models.NamedResourceID, instanceURL str
 
 // limitResultFields checks if field limiting is necessary and removes all fields
 // from the responseJSON that should not be displayed if this is the case.
-func limitResultFields(responseJSON *orderedmap.OrderedMap, params FieldLimitingParams) {
+// If strict fields are requested, selected fields that do not exist in the responseJSON
+// are returned as validation errors and the responseJSON is left unchanged.
+func limitResultFields(responseJSON *orderedmap.OrderedMap, params FieldLimitingParams) []ValidationError {
 	// Check if field limiting is not enabled
 	if !params.FieldLimitingEnabled {
+		// Only the excluded fields are removed if no fields are selected
+		if len(params.ExcludeFields) > 0 {
+			excludeObjectFields(responseJSON, params.ExcludeFields)
+		}
+		return nil
+	}
+	if params.StrictFields {
+		unknown := unknownFields([]*orderedmap.OrderedMap{responseJSON}, params.Fields, "")
+		if len(unknown) > 0 {
+			return []ValidationError{{Parameter: "fields", Reason: fmt.Sprintf("unknown fields: %v", strings.Join(unknown, ", "))}}
+		}
+	}
+	limitObjectFields(responseJSON, params.Fields)
+	return nil
+}
+
+// unknownFields returns the dotted paths of the selected fields that exist in none of the objects.
+// Nested selections are checked against the nested objects of all objects, fields of empty arrays
+// can not be checked and are never unknown.
+func unknownFields(objects []*orderedmap.OrderedMap, fields []FieldSelection, prefix string) []string {
+	if len(objects) == 0 {
+		return nil
+	}
+	var unknown []string
+	selected := selectedFields(fields)
+	// Check the fields in the order of the selection for a stable error message
+	for _, f := range fields {
+		children, ok := selected[f.Name]
+		if !ok {
+			// Already checked
+			continue
+		}
+		delete(selected, f.Name)
+		var nested []*orderedmap.OrderedMap
+		found := false
+		for _, object := range objects {
+			value, exists := object.Get(f.Name)
+			if !exists {
+				continue
+			}
+			found = true
+			if children != nil {
+				transformValueObjects(value, func(n *orderedmap.OrderedMap) {
+					nested = append(nested, n)
+				})
+			}
+		}
+		if !found {
+			unknown = append(unknown, prefix+f.Name)
+		} else if children != nil {
+			unknown = append(unknown, unknownFields(nested, children, prefix+f.Name+".")...)
+		}
+	}
+	return unknown
+}
+
+// excludeObjectFields removes the excluded fields from the object. Fields with a nested selection
+// are kept and only the selected fields of their nested objects are removed.
+func excludeObjectFields(object *orderedmap.OrderedMap, fields []FieldSelection) {
+	for k, children := range selectedFields(fields) {
+		value, ok := object.Get(k)
+		if !ok {
+			continue
+		}
+		if children == nil {
+			object.Delete(k)
+		} else {
+			object.Set(k, transformValueObjects(value, func(nested *orderedmap.OrderedMap) {
+				excludeObjectFields(nested, children)
+			}))
+		}
+	}
+}
+
+// limitObjectFields removes all keys of the object that are not selected and limits the
+// nested values of the fields with a nested selection.
+func limitObjectFields(object *orderedmap.OrderedMap, fields []FieldSelection) {
+	selected := selectedFields(fields)
+	// Collect the keys first since deleting while looping over the keys
+	// caused keys to be skipped and others to be used multiple times
+	for _, k := range append([]string{}, object.Keys()...) {
+		children, ok := selected[k]
+		if !ok {
+			object.Delete(k)
+		} else if children != nil {
+			value, _ := object.Get(k)
+			object.Set(k, limitValueFields(value, children))
+		}
+	}
+}
+
+// selectedFields maps the names of the selected fields to their nested selection, which is
+// nil if the whole field is selected. Nested selections of the same field are merged.
+func selectedFields(fields []FieldSelection) map[string][]FieldSelection {
+	selected := make(map[string][]FieldSelection)
+	for _, f := range fields {
+		children, ok := selected[f.Name]
+		switch {
+		case ok && children == nil:
+			// The whole field is already selected
+		case len(f.Children) == 0:
+			selected[f.Name] = nil
+		default:
+			selected[f.Name] = append(children, f.Children...)
+		}
+	}
+	return selected
+}
+
+// limitValueFields applies the nested selection to a value of the response. Objects are limited to
+// the selected fields and arrays are limited element by element, other values are returned unchanged.
+func limitValueFields(value interface{}, fields []FieldSelection) interface{} {
+	return transformValueObjects(value, func(object *orderedmap.OrderedMap) {
+		limitObjectFields(object, fields)
+	})
+}
+
+// transformValueObjects applies the transformation to a value of the response if it is an object or to
+// each element if it is an array, other values are returned unchanged. The value is converted to its
+// JSON representation first, since it is usually one of the models.
+func transformValueObjects(value interface{}, transform func(object *orderedmap.OrderedMap)) interface{} {
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	valueJSON = bytes.TrimSpace(valueJSON)
+	if len(valueJSON) == 0 {
+		return value
+	}
+	switch valueJSON[0] {
+	case '{':
+		object := orderedmap.New()
+		if err := json.Unmarshal(valueJSON, object); err != nil {
+			return value
+		}
+		transform(object)
+		return object
+	case '[':
+		var elements []json.RawMessage
+		if err := json.Unmarshal(valueJSON, &elements); err != nil {
+			return value
+		}
+		transformed := make([]interface{}, len(elements))
+		for i, element := range elements {
+			transformed[i] = transformValueObjects(element, transform)
+		}
+		return transformed
+	}
+	return value
+}
+
+// flattenResultFields replaces all nested resources and arrays of nested resources in the
+// responseJSON with their names, joining multiple names with commas.
+func flattenResultFields(responseJSON *orderedmap.OrderedMap) {
+	for _, k := range responseJSON.Keys() {
+		value, _ := responseJSON.Get(k)
+		var names []string
+		switch v := value.(type) {
+		case models.NamedResourceURL:
+			names = append(names, v.Name)
+		case []models.NamedResourceURL:
+			for _, r := range v {
+				names = append(names, r.Name)
+			}
+		case []models.PokemonDungeonURL:
+			for _, d := range v {
+				names = append(names, d.Dungeon.Name)
+			}
+		case []models.PokemonMoveURL:
+			for _, m := range v {
+				names = append(names, m.Move.Name)
+			}
+		default:
+			// Keep all scalar values
+			continue
+		}
+		responseJSON.Set(k, strings.Join(names, ","))
+	}
+}
+
+// DispatchStaticRoutes returns a handler that passes requests with a searcharg matching one of the static routes
+// to the corresponding handler and all other requests to the search handler. This is necessary since httprouter
+// does not allow static routes like '/v1/pokemon/stats' next to the wildcard route '/v1/pokemon/:searcharg'.
+func DispatchStaticRoutes(search httprouter.Handle, routes map[string]httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if h, ok := routes[ps.ByName("searcharg")]; ok {
+			h(w, r, ps)
+			return
+		}
+		search(w, r, ps)
+	}
+}
+
+// SearchAllHandler handles requests on '/v1/search' and returns the resources of all types
+// whose name contains the search term of the "q" argument, grouped by their type.
+func SearchAllHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Extract the ResourceListParams from the context with a type assertion
+	params, ok := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing ResourceListParams"))
+		return
+	}
+	// An empty term would match all resources
+	if strings.TrimSpace(r.URL.Query().Get("q")) == "" {
+		params.Errors = append(params.Errors, ValidationError{Parameter: "q", Reason: "a search term is required"})
+	}
+	// Answer with all invalid parameters at once
+	if len(params.Errors) > 0 {
+		AnswerWithValidationErrors(w, params.Errors)
+		return
+	}
+	// Search all resources in the database
+	results, err := db.SearchAll(r.Context(), strings.TrimSpace(r.URL.Query().Get("q")), params.Pagination)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Build the response JSON with a map, using the resource types as keys
+	responseJSON := orderedmap.New()
+	for _, result := range results {
+		responseJSON.Set(result.ResourceType, transformToURLResources(result.Resources, BaseURL(r), result.ResourceType))
+	}
+	// Extract the FieldLimitingParams from the context with a type assertion
+	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
+		return
+	}
+	// Perform field limiting if necessary
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
+	// Transform the map to JSON
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Write the response
+	writeJSON(w, r, json)
+}
+
+// eventHeartbeatInterval is the interval of the comments keeping idle event streams alive.
+const eventHeartbeatInterval = 15 * time.Second
+
+// eventStreamsStopped is closed by StopEventStreams to end all event streams.
+var eventStreamsStopped = make(chan struct{})
+
+// stopEventStreamsOnce makes sure eventStreamsStopped is only closed once.
+var stopEventStreamsOnce sync.Once
+
+// StopEventStreams ends all open event streams and the ones requested afterwards, so they do not keep
+// the server from shutting down. The clients can reconnect to another instance.
+func StopEventStreams() {
+	stopEventStreamsOnce.Do(func() {
+		close(eventStreamsStopped)
+	})
+}
+
+// EventsHandler handles requests on '/v1/events' and keeps the connection open to send
+// server-sent events. An event "data-version" with the new version as data is sent whenever
+// the data changes, so clients can sync again. A comment is sent as a heartbeat in between.
+func EventsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		ErrorAndLog500(w, errors.New("streaming is not supported by the ResponseWriter"))
+		return
+	}
+	// Subscribe before the response starts, so failures can still be answered with an error
+	subscription, err := cache.SubscribeDataVersion(r.Context())
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	defer subscription.Close()
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		// Stop when the client disconnects or the server shuts down
+		case <-r.Context().Done():
+			return
+		case <-eventStreamsStopped:
+			return
+		case version, ok := <-subscription.Versions():
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "event: data-version\ndata: %v\n\n", version)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+// AbilityListHandler handles requests on '/v1/abilities' and returns a list of all ability resources.
+func AbilityListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Extract the ResourceListParams from the context with a type assertion
+	params, ok := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing ResourceListParams"))
+		return
+	}
+	// Answer with all invalid parameters at once
+	if len(params.Errors) > 0 {
+		AnswerWithValidationErrors(w, params.Errors)
+		return
+	}
+	// Answer with the count only if requested
+	if params.CountOnly {
+		answerWithCountJSON(db.AbilityTable, params.Filter, w, r)
+		return
+	}
+	// Fetch the ability list from the database
+	count, abilities, err := db.GetAbilityList(r.Context(), params.Sort, params.Pagination, params.Filter)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Build response JSON with URLs instead of IDs and send it to the client
+	answerWithListJSON(count, abilities, "abilities", params, w, r)
+}
+
+// AbilitySearchHandler handles requests on '/v1/abilities/:searcharg' and returns information about the desired ability.
+func AbilitySearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Extract the FieldLimitingParams from the context with a type assertion
+	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
+		return
+	}
+	// Generate the input for the db search
+	searchInput := GenerateSearchInput(ps.ByName("searcharg"))
+	// Get the ability from the database
+	var ability models.Ability
+	var pokemon []models.NamedResourceID
+	counts, err := fetchWithCounts(r, db.AbilityTable, searchInput, func(ctx context.Context) (err error) {
+		ability, pokemon, err = db.GetAbility(ctx, searchInput)
+		return err
+	})
+	if err != nil {
+		// If the error is a db.ResourceNotFoundError, return code 404 (not found) or the candidates for the name
+		if _, ok := err.(*db.ResourceNotFoundError); ok {
+			answerNotFound(w, r, err, db.AbilityTable, "abilities", searchInput)
+		} else {
+			ErrorAndLog500(w, err)
+		}
+		return
+	}
+	// Answer with the raw database representation if requested
+	if rawRequested(r) {
+		answerWithRawJSON(map[string]interface{}{"ability": ability, "pokemon": pokemon}, w)
+		return
+	}
+	// Build representation of the pokemon with URL instead of ID
+	pokemonWithURL := transformToURLResources(pokemon, BaseURL(r), "pokemon")
+	// Build the response JSON with a map
+	responseJSON := orderedmap.New()
+	responseJSON.Set("id", ability.AbilityID)
+	responseJSON.Set("name", ability.AbilityName)
+	responseJSON.Set("description", ability.Description)
+	responseJSON.Set("pokemon", pokemonWithURL)
+	setRelationshipCounts(responseJSON, counts)
+	// Perform field limiting if necessary
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
+	// Transform the map to JSON
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Write the response
+	writeJSON(w, r, json)
+}
+
+// CampListHandler handles requests on '/v1/camps' and returns a list of all camp resources.
+func CampListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Extract the ResourceListParams from the context with a type assertion
+	params, ok := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing ResourceListParams"))
+		return
+	}
+	// Answer with all invalid parameters at once
+	if len(params.Errors) > 0 {
+		AnswerWithValidationErrors(w, params.Errors)
+		return
+	}
+	// Answer with the count only if requested
+	if params.CountOnly {
+		answerWithCountJSON(db.CampTable, params.Filter, w, r)
+		return
+	}
+	// Fetch the ability list from the database
+	count, camps, err := db.GetCampList(r.Context(), params.Sort, params.Pagination, params.Filter)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Build response JSON with URLs instead of IDs and send it to the client
+	answerWithListJSON(count, camps, "camps", params, w, r)
+}
+
+// CampSearchHandler handles requests on '/v1/camps/:searcharg' and returns information about the desired camp.
+func CampSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Extract the FieldLimitingParams from the context with a type assertion
+	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
+		return
+	}
+	// Generate the input for the db search
+	searchInput := GenerateSearchInput(ps.ByName("searcharg"))
+	// Get the ability from the database
+	var camp models.Camp
+	var pokemon []models.NamedResourceID
+	counts, err := fetchWithCounts(r, db.CampTable, searchInput, func(ctx context.Context) (err error) {
+		camp, pokemon, err = db.GetCamp(ctx, searchInput)
+		return err
+	})
+	if err != nil {
+		// If the error is a db.ResourceNotFoundError, return code 404 (not found) or the candidates for the name
+		if _, ok := err.(*db.ResourceNotFoundError); ok {
+			answerNotFound(w, r, err, db.CampTable, "camps", searchInput)
+		} else {
+			ErrorAndLog500(w, err)
+		}
+		return
+	}
+	// Answer with the raw database representation if requested
+	if rawRequested(r) {
+		answerWithRawJSON(map[string]interface{}{"camp": camp, "pokemon": pokemon}, w)
+		return
+	}
+	// Build representation of the pokemon with URL instead of ID
+	pokemonWithURL := transformToURLResources(pokemon, BaseURL(r), "pokemon")
+	// Build the response JSON with a map
+	responseJSON := orderedmap.New()
+	responseJSON.Set("id", camp.CampID)
+	responseJSON.Set("name", camp.CampName)
+	responseJSON.Set("description", camp.Description)
+	responseJSON.Set("unlockType", camp.UnlockType)
+	responseJSON.Set("cost", camp.Cost)
+	responseJSON.Set("pokemon", pokemonWithURL)
+	setRelationshipCounts(responseJSON, counts)
+	// Perform field limiting if necessary
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
+	// Transform the map to JSON
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Write the response
+	writeJSON(w, r, json)
+}
+
+// DungeonListHandler handles requests on '/v1/dungeons' and returns a list of all dungeon resources.
+func DungeonListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Extract the ResourceListParams from the context with a type assertion
+	params, ok := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing ResourceListParams"))
+		return
+	}
+	// Answer with all invalid parameters at once
+	if len(params.Errors) > 0 {
+		AnswerWithValidationErrors(w, params.Errors)
+		return
+	}
+	// Answer with the count only if requested
+	if params.CountOnly {
+		answerWithCountJSON(db.DungeonTable, params.Filter, w, r)
+		return
+	}
+	// Fetch the ability list from the database
+	count, dungeons, err := db.GetDungeonList(r.Context(), params.Sort, params.Pagination, params.Filter)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Build response JSON with URLs instead of IDs and send it to the client
+	answerWithListJSON(count, dungeons, "dungeons", params, w, r)
+}
+
+// DungeonSearchHandler handles requests on '/v1/dungeons/:searcharg' and returns information about the desired dungeon.
+func DungeonSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Extract the FieldLimitingParams from the context with a type assertion
+	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
+		return
+	}
+	// Generate the input for the db search
+	searchInput := GenerateSearchInput(ps.ByName("searcharg"))
+	// Get the ability from the database
+	var dungeon models.Dungeon
+	var pokemon []models.DungeonPokemonID
+	counts, err := fetchWithCounts(r, db.DungeonTable, searchInput, func(ctx context.Context) (err error) {
+		dungeon, pokemon, err = db.GetDungeon(ctx, searchInput)
+		return err
+	})
+	if err != nil {
+		// If the error is a db.ResourceNotFoundError, return code 404 (not found) or the candidates for the name
+		if _, ok := err.(*db.ResourceNotFoundError); ok {
+			answerNotFound(w, r, err, db.DungeonTable, "dungeons", searchInput)
+		} else {
+			ErrorAndLog500(w, err)
+		}
+		return
+	}
+	// Answer with the raw database representation if requested
+	if rawRequested(r) {
+		answerWithRawJSON(map[string]interface{}{"dungeon": dungeon, "pokemon": pokemon}, w)
+		return
+	}
+	// Build representation of the pokemon with URL instead of ID
+	var pokemonWithURL []models.DungeonPokemonURL
+	for _, p := range pokemon {
+		pokemonWithURL = append(pokemonWithURL, p.ToDungeonPokemonURL(BaseURL(r)))
+	}
+	// Build the response JSON with a map
+	responseJSON := orderedmap.New()
+	responseJSON.Set("id", dungeon.DungeonID)
+	responseJSON.Set("name", dungeon.DungeonName)
+	responseJSON.Set("levels", dungeon.Levels)
+	responseJSON.Set("startLevel", dungeon.StartLevel)
+	responseJSON.Set("teamSize", dungeon.TeamSize)
+	responseJSON.Set("itemsAllowed", dungeon.ItemsAllowed)
+	responseJSON.Set("pokemonJoining", dungeon.PokemonJoining)
+	responseJSON.Set("mapVisible", dungeon.MapVisible)
+	responseJSON.Set("pokemon", pokemonWithURL)
+	setRelationshipCounts(responseJSON, counts)
+	// Perform field limiting if necessary
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
+	// Transform the map to JSON
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Write the response
+	writeJSON(w, r, json)
+}
+
+// MoveListHandler handles requests on '/v1/moves' and returns a list of all move resources.
+func MoveListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Extract the ResourceListParams from the context with a type assertion
+	params, ok := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing ResourceListParams"))
+		return
+	}
+	// Answer with all invalid parameters at once
+	if len(params.Errors) > 0 {
+		AnswerWithValidationErrors(w, params.Errors)
+		return
+	}
+	// Answer with the count only if requested
+	if params.CountOnly {
+		answerWithCountJSON(db.MoveTable, params.Filter, w, r)
+		return
+	}
+	// Fetch the ability list from the database
+	count, moves, err := db.GetMoveList(r.Context(), params.Sort, params.Pagination, params.Filter)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Build response JSON with URLs instead of IDs and send it to the client
+	answerWithListJSON(count, moves, "moves", params, w, r)
+}
+
+// MovesByTypeHandler handles requests on '/v1/moves/by-type' and returns all moves grouped by their type.
+func MovesByTypeHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Extract the FieldLimitingParams from the context with a type assertion
+	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
+		return
+	}
+	// Get the moves of all types from the database
+	types, err := db.GetMovesByType(r.Context())
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Build the response JSON with a map, using the type names as keys
+	responseJSON := orderedmap.New()
+	for _, t := range types {
+		typeJSON := orderedmap.New()
+		typeJSON.Set("count", len(t.Moves))
+		typeJSON.Set("moves", transformToURLResources(t.Moves, BaseURL(r), "moves"))
+		responseJSON.Set(t.Type.Name, typeJSON)
+	}
+	// Perform field limiting if necessary
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
+	// Transform the map to JSON
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Write the response
+	writeJSON(w, r, json)
+}
+
+// MoveSearchHandler handles requests on '/v1/moves/:searcharg' and returns information about the desired move.
+func MoveSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Extract the FieldLimitingParams from the context with a type assertion
+	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
+		return
+	}
+	// Validate the level the stats should be scaled to
+	atLevel := 0
+	if value := r.URL.Query().Get("at_level"); value != "" {
+		level, err := strconv.Atoi(value)
+		if err != nil || level < minLevel || level > maxLevel {
+			AnswerWithValidationErrors(w, []ValidationError{{Parameter: "at_level", Reason: fmt.Sprintf("invalid value '%v', expected an integer from %v to %v", value, minLevel, maxLevel)}})
+			return
+		}
+		atLevel = level
+	}
+	// Generate the input for the db search
+	searchInput := GenerateSearchInput(ps.ByName("searcharg"))
+	// Get the ability from the database
+	var move models.AttackMove
+	var moveType models.NamedResourceID
+	var pokemon []models.MovePokemonID
+	counts, err := fetchWithCounts(r, db.MoveTable, searchInput, func(ctx context.Context) (err error) {
+		move, moveType, pokemon, err = db.GetMove(ctx, searchInput)
+		return err
+	})
+	if err != nil {
+		// If the error is a db.ResourceNotFoundError, return code 404 (not found) or the candidates for the name
+		if _, ok := err.(*db.ResourceNotFoundError); ok {
+			answerNotFound(w, r, err, db.MoveTable, "moves", searchInput)
+		} else {
+			ErrorAndLog500(w, err)
+		}
+		return
+	}
+	// Answer with the raw database representation if requested
+	if rawRequested(r) {
+		answerWithRawJSON(map[string]interface{}{"move": move, "type": moveType, "pokemon": pokemon}, w)
+		return
+	}
+	responseJSON := moveJSON(r, move, moveType, pokemon, atLevel)
+	setRelationshipCounts(responseJSON, counts)
+	// Perform field limiting if necessary
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
+	// Transform the map to JSON
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Write the response
+	writeJSON(w, r, json)
+}
+
+// moveJSON builds the response JSON of a move with URLs instead of IDs.
+// The stats at the level are only included if atLevel is above 0.
+func moveJSON(r *http.Request, move models.AttackMove, moveType models.NamedResourceID, pokemon []models.MovePokemonID, atLevel int) *orderedmap.OrderedMap {
+	// Build representation of the pokemon with URL instead of ID
+	var pokemonWithURL []models.MovePokemonURL
+	for _, p := range pokemon {
+		pokemonWithURL = append(pokemonWithURL, p.ToMovePokemonURL(BaseURL(r)))
+	}
+	// Build the response JSON with a map
+	responseJSON := orderedmap.New()
+	responseJSON.Set("id", move.MoveID)
+	responseJSON.Set("name", move.MoveName)
+	responseJSON.Set("category", move.Category)
+	responseJSON.Set("range", move.Range)
+	responseJSON.Set("target", move.Target)
+	responseJSON.Set("initialPP", move.InitialPP)
+	responseJSON.Set("initialPower", move.InitialPower)
+	responseJSON.Set("accuracy", move.Accuracy)
+	responseJSON.Set("description", move.Description)
+	responseJSON.Set("type", moveType.ToNamedResourceURL(BaseURL(r), "types"))
+	// Add the stats at the requested level
+	if atLevel > 0 {
+		responseJSON.Set("atLevel", movePPAndPowerAtLevel(move, atLevel))
+	}
+	responseJSON.Set("pokemon", pokemonWithURL)
+	return responseJSON
+}
+
+// minLevel and maxLevel are the bounds of the level of a pokemon.
+const (
+	minLevel = 1
+	maxLevel = 100
+)
+
+// movePPAndPowerAtLevel returns the PP and power of the move at the level of a pokemon.
+// The data contains no scaling of moves by level, so the base values are returned with a note.
+func movePPAndPowerAtLevel(move models.AttackMove, level int) *orderedmap.OrderedMap {
+	stats := orderedmap.New()
+	stats.Set("level", level)
+	stats.Set("pp", move.InitialPP)
+	stats.Set("power", move.InitialPower)
+	stats.Set("note", "no level scaling data is available for moves, the base values are returned")
+	return stats
+}
+
+// PokemonListHandler handles requests on '/v1/pokemon' and returns a list of all pokemon resources.
+func PokemonListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Extract the ResourceListParams from the context with a type assertion
+	params, ok := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing ResourceListParams"))
+		return
+	}
+	// Answer with all invalid parameters at once
+	if len(params.Errors) > 0 {
+		AnswerWithValidationErrors(w, params.Errors)
+		return
+	}
+	// Answer with the count only if requested
+	if params.CountOnly {
+		answerWithCountJSON(db.PokemonTable, params.Filter, w, r)
+		return
+	}
+	// Answer with a batch lookup if names are provided
+	if names := r.URL.Query().Get("names"); names != "" {
+		pokemonBatchByNames(names, w, r)
+		return
+	}
+	// Answer with the full pokemon if IDs are provided
+	if ids := r.URL.Query().Get("ids"); ids != "" {
+		pokemonBatchByIDs(ids, w, r)
+		return
+	}
+	// Fetch the ability list from the database
+	count, pokemon, err := db.GetPokemonList(r.Context(), params.Sort, params.Pagination, params.Filter)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Build response JSON with URLs instead of IDs and send it to the client
+	answerWithListJSON(count, pokemon, "pokemon", params, w, r)
+}
+
+// maxBatchNames is the maximum number of names accepted by a batch lookup.
+const maxBatchNames = 50
+
+// pokemonBatchByNames answers a request to '/v1/pokemon?names=<name>,<name>' with the
+// pokemon matching the comma-separated names and a list of names that were not found.
+func pokemonBatchByNames(names string, w http.ResponseWriter, r *http.Request) {
+	// Normalize the names like for single searches and remove duplicates
+	var searchNames []string
+	requestedNames := make(map[string]string)
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		searchName := strings.Title(strings.ToLower(name))
+		if _, ok := requestedNames[searchName]; !ok {
+			requestedNames[searchName] = name
+			searchNames = append(searchNames, searchName)
+		}
+	}
+	if len(searchNames) > maxBatchNames {
+		AnswerWithValidationErrors(w, []ValidationError{{Parameter: "names", Reason: fmt.Sprintf("too many values, at most %v are allowed", maxBatchNames)}})
+		return
+	}
+	// Fetch the pokemon from the database
+	pokemon, err := db.GetPokemonByNames(r.Context(), searchNames)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Collect the names without a result
+	for _, p := range pokemon {
+		delete(requestedNames, p.Name)
+	}
+	notFound := make([]string, 0, len(requestedNames))
+	for _, searchName := range searchNames {
+		if name, ok := requestedNames[searchName]; ok {
+			notFound = append(notFound, name)
+		}
+	}
+	// Build representation of the pokemon with URL instead of ID
+	pokemonWithURL := transformToURLResources(pokemon, BaseURL(r), "pokemon")
+	for i := range pokemonWithURL {
+		pokemonWithURL[i].Sprite = models.SpriteURL(spriteBaseURL, pokemon[i].ID)
+	}
+	// Build the response JSON as a map
+	responseJSON := orderedmap.New()
+	responseJSON.Set("count", len(pokemon))
+	responseJSON.Set("results", pokemonWithURL)
+	responseJSON.Set("notFound", notFound)
+	// Extract the FieldLimitingParams from the context with a type assertion
+	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
+		return
+	}
+	// Perform field limiting if necessary
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
+	// Transform the map to JSON
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Write the response
+	writeJSON(w, r, json)
+}
+
+// maxBatchIDs is the maximum number of IDs accepted by a batch lookup.
+const maxBatchIDs = 100
+
+// BatchError describes a requested resource of a batch lookup that could not be returned.
+type BatchError struct {
+	ID     int    `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// pokemonBatchByIDs answers a request to '/v1/pokemon?ids=<id>,<id>' with the full representation
+// of the pokemon with the comma-separated IDs and an error for each ID that was not found.
+func pokemonBatchByIDs(ids string, w http.ResponseWriter, r *http.Request) {
+	// Parse the IDs and remove duplicates
+	var dexNumbers []int
+	requestedIDs := make(map[int]bool)
+	for _, value := range strings.Split(ids, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			AnswerWithValidationErrors(w, []ValidationError{{Parameter: "ids", Reason: fmt.Sprintf("invalid value '%v', expected a positive integer", value)}})
+			return
+		}
+		if !requestedIDs[id] {
+			requestedIDs[id] = true
+			dexNumbers = append(dexNumbers, id)
+		}
+	}
+	if len(dexNumbers) > maxBatchIDs {
+		AnswerWithValidationErrors(w, []ValidationError{{Parameter: "ids", Reason: fmt.Sprintf("too many values, at most %v are allowed", maxBatchIDs)}})
+		return
+	}
+	// Fetch the pokemon from the database
+	entries, err := db.GetPokemonByIDs(r.Context(), dexNumbers)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Build the representation of each pokemon, the nested lists are not paginated
+	results := make([]*orderedmap.OrderedMap, 0, len(entries))
+	for _, entry := range entries {
+		results = append(results, pokemonJSON(r, entry, nil))
+		delete(requestedIDs, entry.Pokemon.DexNumber)
+	}
+	// Report the IDs without a result in the order of the request
+	batchErrors := make([]BatchError, 0, len(requestedIDs))
+	for _, id := range dexNumbers {
+		if requestedIDs[id] {
+			notFound := &db.ResourceNotFoundError{ResourceType: "pokemon", SearchType: db.ID, ID: id}
+			batchErrors = append(batchErrors, BatchError{ID: id, Reason: notFound.Error()})
+		}
+	}
+	// Build the response JSON as a map
+	responseJSON := orderedmap.New()
+	responseJSON.Set("count", len(results))
+	responseJSON.Set("results", results)
+	responseJSON.Set("errors", batchErrors)
+	// Extract the FieldLimitingParams from the context with a type assertion
+	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
+		return
+	}
+	// Perform field limiting if necessary
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
+	// Transform the map to JSON
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Write the response
+	writeJSON(w, r, json)
+}
+
+// paginatedPokemonLists are the lists nested in a single pokemon that can be paginated.
+var paginatedPokemonLists = []string{"abilities", "dungeons", "moves"}
+
+// PokemonSearchHandler handles requests on '/v1/pokemon/:searcharg' and returns information about the desired pokemon.
+func PokemonSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Extract the NestedListParams of the lists that can be paginated from the context
+	nestedParams, ok := pokemonNestedListParams(w, r)
+	if !ok {
+		return
+	}
+	// Generate the input for the db search
+	searchInput := generatePokemonSearchInput(r, ps.ByName("searcharg"))
+	// Get the pokemon from the database
+	pokemon, camp, abilities, dungeons, moves, pokemonTypes, err := db.GetPokemon(r.Context(), searchInput)
+	if err != nil {
+		// If the error is a db.ResourceNotFoundError, return code 404 (not found) or the candidates for the name
+		if _, ok := err.(*db.ResourceNotFoundError); ok {
+			answerNotFound(w, r, err, db.PokemonTable, "pokemon", searchInput)
+		} else {
+			ErrorAndLog500(w, err)
+		}
+		return
+	}
+	answerWithPokemon(w, r, nestedParams, models.PokemonEntryID{Pokemon: pokemon, Camp: camp, Abilities: abilities, Dungeons: dungeons, Moves: moves, Types: pokemonTypes})
+}
+
+// RandomPokemonHandler handles requests on '/v1/pokemon/random' and returns information about a random pokemon.
+// The optional parameter "type" only chooses pokemon of the type with the ID or name.
+func RandomPokemonHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Extract the NestedListParams of the lists that can be paginated from the context
+	nestedParams, ok := pokemonNestedListParams(w, r)
+	if !ok {
+		return
+	}
+	var filter db.ListFilter
+	if pokemonType := r.URL.Query().Get("type"); pokemonType != "" {
+		typeInput := GenerateSearchInput(pokemonType)
+		filter.Type = &typeInput
+	}
+	// Get a random pokemon from the database
+	pokemon, camp, abilities, dungeons, moves, pokemonTypes, err := db.GetRandomPokemon(r.Context(), filter)
+	if err != nil {
+		// If the error is a db.ResourceNotFoundError, no pokemon has the type
+		if _, ok := err.(*db.ResourceNotFoundError); ok {
+			http.Error(w, fmt.Sprintf("no pokemon of type '%v' found", r.URL.Query().Get("type")), http.StatusNotFound)
+		} else {
+			ErrorAndLog500(w, err)
+		}
+		return
+	}
+	// Each request returns a different pokemon, so the response must not be reused
+	w.Header().Set("Cache-Control", "no-store")
+	answerWithPokemon(w, r, nestedParams, models.PokemonEntryID{Pokemon: pokemon, Camp: camp, Abilities: abilities, Dungeons: dungeons, Moves: moves, Types: pokemonTypes})
+}
+
+// pokemonNestedListParams extracts the NestedListParams of the lists of a pokemon that can be paginated from the context.
+// Answers the request and returns false if they are missing or invalid.
+func pokemonNestedListParams(w http.ResponseWriter, r *http.Request) (map[string]NestedListParams, bool) {
+	nestedParams := make(map[string]NestedListParams)
+	var nestedErrors []ValidationError
+	for _, listName := range paginatedPokemonLists {
+		params, ok := nestedListParams(r, listName)
+		if !ok {
+			ErrorAndLog500(w, errors.New("missing NestedListParams"))
+			return nil, false
+		}
+		nestedParams[listName] = params
+		nestedErrors = append(nestedErrors, params.Errors...)
+	}
+	// Answer with all invalid parameters at once
+	if len(nestedErrors) > 0 {
+		AnswerWithValidationErrors(w, nestedErrors)
+		return nil, false
+	}
+	return nestedParams, true
+}
+
+// answerWithPokemon answers the request with the representation of the pokemon, its camp and all its abilities,
+// dungeons, moves and types. Only the requested pages of the lists that can be paginated are included.
+func answerWithPokemon(w http.ResponseWriter, r *http.Request, nestedParams map[string]NestedListParams, entry models.PokemonEntryID) {
+	// Extract the FieldLimitingParams from the context with a type assertion
+	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
+		return
+	}
+	// Extract the FormatParams from the context with a type assertion
+	formatParams, ok := r.Context().Value(FormatParamsKey).(FormatParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing FormatParams"))
+		return
+	}
+	// Answer with the raw database representation if requested
+	if rawRequested(r) {
+		answerWithRawJSON(map[string]interface{}{"pokemon": entry.Pokemon, "camp": entry.Camp, "abilities": entry.Abilities, "dungeons": entry.Dungeons, "moves": entry.Moves, "types": entry.Types}, w)
+		return
+	}
+	responseJSON := pokemonJSON(r, entry, nestedParams)
+	// Flatten the nested resources if requested, before field limiting converts them into generic objects
+	// the flattening does not recognize. Nested selections of flattened fields keep their names.
+	if formatParams.Flat {
+		flattenResultFields(responseJSON)
+	}
+	// Perform field limiting if necessary
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
+	// Wrap the pages of paginated lists with the total number of entries, the full lists stay arrays
+	totals := map[string]int{"abilities": len(entry.Abilities), "dungeons": len(entry.Dungeons), "moves": len(entry.Moves)}
+	for _, listName := range paginatedPokemonLists {
+		if nestedParams[listName].Paginated {
+			wrapNestedPage(responseJSON, listName, totals[listName])
+		}
+	}
+	// Transform the map to JSON
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Write the response
+	writeJSON(w, r, json)
+}
+
+// wrapNestedPage replaces the page of the nested list with an object containing the total number of
+// entries as "count" and the page as "results", like the resource lists. Lists removed by field limiting are skipped.
+func wrapNestedPage(responseJSON *orderedmap.OrderedMap, listName string, total int) {
+	results, ok := responseJSON.Get(listName)
+	if !ok {
+		return
+	}
+	page := orderedmap.New()
+	page.Set("count", total)
+	page.Set("results", results)
+	responseJSON.Set(listName, page)
+}
+
+// pokemonJSON builds the representation of the pokemon entry with URLs instead of IDs.
+// Only the requested pages of the lists that can be paginated are included.
+func pokemonJSON(r *http.Request, entry models.PokemonEntryID, nestedParams map[string]NestedListParams) *orderedmap.OrderedMap {
+	pokemon := entry.Pokemon
+	// Build representation of the abilities with URL instead of ID
+	abilitiesWithURL := transformToURLResources(entry.Abilities, BaseURL(r), "abilities")
+	// Build representation of the dungeons with URL instead of ID
+	var dungeonsWithURL []models.PokemonDungeonURL
+	for _, d := range entry.Dungeons {
+		dungeonsWithURL = append(dungeonsWithURL, d.ToPokemonDungeonURL(BaseURL(r)))
+	}
+	// Build representation of the moves with URL instead of ID
+	var movesWithURL []models.PokemonMoveURL
+	for _, m := range entry.Moves {
+		movesWithURL = append(movesWithURL, m.ToPokemonMoveURL(BaseURL(r)))
+	}
+	// Build representation of the types with URL instead of ID
+	pokemonTypesWithURL := transformToURLResources(entry.Types, BaseURL(r), "types")
+	// Build the response JSON with a map
+	responseJSON := orderedmap.New()
+	responseJSON.Set("id", pokemon.DexNumber)
+	responseJSON.Set("name", pokemon.PokemonName)
+	if sprite := models.SpriteURL(spriteBaseURL, pokemon.DexNumber); sprite != "" {
+		responseJSON.Set("sprite", sprite)
+	}
+	responseJSON.Set("classification", pokemon.Classification)
+	responseJSON.Set("evolutionStage", pokemon.EvolutionStage)
+	responseJSON.Set("evolveCondition", pokemon.EvolveCondition)
+	responseJSON.Set("evolveLevel", pokemon.EvolveLevel)
+	responseJSON.Set("evolveCrystals", pokemon.EvolveCrystals)
+	responseJSON.Set("camp", entry.Camp.ToNamedResourceURL(BaseURL(r), "camps"))
+	// Only include the requested pages of the lists that can be paginated
+	start, end := paginateNested(nestedParams["abilities"], len(abilitiesWithURL))
+	responseJSON.Set("abilities", abilitiesWithURL[start:end])
+	start, end = paginateNested(nestedParams["dungeons"], len(dungeonsWithURL))
+	responseJSON.Set("dungeons", dungeonsWithURL[start:end])
+	start, end = paginateNested(nestedParams["moves"], len(movesWithURL))
+	responseJSON.Set("moves", movesWithURL[start:end])
+	responseJSON.Set("types", pokemonTypesWithURL)
+	return responseJSON
+}
+
+// PokemonStatsHandler handles requests on '/v1/pokemon/stats' and returns the number of pokemon for each group of the
+// dimension provided by the 'group_by' parameter.
+func PokemonStatsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Extract the FieldLimitingParams from the context with a type assertion
+	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
 		return
 	}
-	// Loop through the JSON and check which parameters need to be removed
-	deleteKeys := make(map[string]bool)
-	keys := responseJSON.Keys()
-	for _, k := range keys {
-		deleteKeys[k] = true
-		for _, v := range params.Fields {
-			if k == v {
-				deleteKeys[k] = false
-				break
-			}
-		}
+	// Check if the dimension is supported
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy == "" || !GroupByParameter.Allows(groupBy) {
+		AnswerWithValidationErrors(w, []ValidationError{{Parameter: "group_by", Reason: fmt.Sprintf("invalid value '%v', expected one of %v", groupBy, strings.Join(GroupByParameter.AllowedValues, ", "))}})
+		return
 	}
-	// Delete all keys marked for deletion
-	// Needs to be done separately since deleting while looping over the keys
-	// caused keys to be skipped and others to be used multiple times
-	for k, v := range deleteKeys {
-		if v {
-			responseJSON.Delete(k)
-		}
+	// Get the counts from the database
+	groups, err := db.GetPokemonGroupCounts(r.Context(), db.GroupBy(groupBy))
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
 	}
-}
-
-// AbilityListHandler handles requests on '/v1/abilities' and returns a list of all ability resources.
-func AbilityListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	// Extract the ResourceListParams from the context with a type assertion
-	params, ok := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
-	if !ok {
-		ErrorAndLog500(w, errors.New("missing ResourceListParams"))
+	// Build the response JSON with a map
+	responseJSON := orderedmap.New()
+	for _, g := range groups {
+		responseJSON.Set(g.Group, g.Count)
+	}
+	// Perform field limiting if necessary
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
 		return
 	}
-	// Fetch the ability list from the database
-	count, abilities, err := db.GetAbilityList(params.Sort, params.Pagination)
+	// Transform the map to JSON
+	json, err := json.Marshal(responseJSON)
 	if err != nil {
 		ErrorAndLog500(w, err)
 		return
 	}
-	// Build response JSON with URLs instead of IDs and send it to the client
-	answerWithListJSON(count, abilities, "abilities", params.Pagination, w, r)
+	// Write the response
+	writeJSON(w, r, json)
 }
 
-// AbilitySearchHandler handles requests on '/v1/abilities/:searcharg' and returns information about the desired ability.
-func AbilitySearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+// PokemonDefensesHandler handles requests on '/v1/pokemon/:searcharg/defenses' and returns the damage
+// multipliers the desired pokemon takes from each attacking type.
+func PokemonDefensesHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	// Extract the FieldLimitingParams from the context with a type assertion
 	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
 	if !ok {
@@ -237,28 +1857,32 @@ func AbilitySearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.
 		return
 	}
 	// Generate the input for the db search
-	searchInput := generateSearchInput(ps.ByName("searcharg"))
-	// Get the ability from the database
-	ability, pokemon, err := db.GetAbility(searchInput)
+	searchInput := generatePokemonSearchInput(r, ps.ByName("searcharg"))
+	// Get the defensive profile from the database
+	pokemon, defenses, err := db.GetPokemonDefenses(r.Context(), searchInput)
 	if err != nil {
-		// If the error is a db.ResourceNotFoundError, return code 404 (not found)
+		// If the error is a db.ResourceNotFoundError, return code 404 (not found) or the forms or candidates for the name
 		if _, ok := err.(*db.ResourceNotFoundError); ok {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			answerNotFound(w, r, err, db.PokemonTable, "pokemon", searchInput)
 		} else {
 			ErrorAndLog500(w, err)
 		}
 		return
 	}
-	// Build representation of the pokemon with URL instead of ID
-	pokemonWithURL := transformToURLResources(pokemon, r.Host, "pokemon")
+	// Build representation of the defenses with URL instead of ID
+	var defensesWithURL []models.TypeDefenseURL
+	for _, d := range defenses {
+		defensesWithURL = append(defensesWithURL, d.ToTypeDefenseURL(BaseURL(r)))
+	}
 	// Build the response JSON with a map
 	responseJSON := orderedmap.New()
-	responseJSON.Set("id", ability.AbilityID)
-	responseJSON.Set("name", ability.AbilityName)
-	responseJSON.Set("description", ability.Description)
-	responseJSON.Set("pokemon", pokemonWithURL)
+	responseJSON.Set("pokemon", pokemon.ToNamedResourceURL(BaseURL(r), "pokemon"))
+	responseJSON.Set("defenses", defensesWithURL)
 	// Perform field limiting if necessary
-	limitResultFields(responseJSON, fieldLimitParams)
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
 	// Transform the map to JSON
 	json, err := json.Marshal(responseJSON)
 	if err != nil {
@@ -266,31 +1890,12 @@ func AbilitySearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.
 		return
 	}
 	// Write the response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(json)
-}
-
-// CampListHandler handles requests on '/v1/camps' and returns a list of all camp resources.
-func CampListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	// Extract the ResourceListParams from the context with a type assertion
-	params, ok := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
-	if !ok {
-		ErrorAndLog500(w, errors.New("missing ResourceListParams"))
-		return
-	}
-	// Fetch the ability list from the database
-	count, camps, err := db.GetCampList(params.Sort, params.Pagination)
-	if err != nil {
-		ErrorAndLog500(w, err)
-		return
-	}
-	// Build response JSON with URLs instead of IDs and send it to the client
-	answerWithListJSON(count, camps, "camps", params.Pagination, w, r)
+	writeJSON(w, r, json)
 }
 
-// CampSearchHandler handles requests on '/v1/camps/:searcharg' and returns information about the desired camp.
-func CampSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+// PokemonFullLearnsetHandler handles requests on '/v1/pokemon/:searcharg/full-learnset' and returns the moves
+// learned by the desired pokemon and all of its pre-evolutions.
+func PokemonFullLearnsetHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	// Extract the FieldLimitingParams from the context with a type assertion
 	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
 	if !ok {
@@ -298,30 +1903,32 @@ func CampSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 		return
 	}
 	// Generate the input for the db search
-	searchInput := generateSearchInput(ps.ByName("searcharg"))
-	// Get the ability from the database
-	camp, pokemon, err := db.GetCamp(searchInput)
+	searchInput := generatePokemonSearchInput(r, ps.ByName("searcharg"))
+	// Get the learnset from the database
+	pokemon, learnset, err := db.GetPokemonFullLearnset(r.Context(), searchInput)
 	if err != nil {
-		// If the error is a db.ResourceNotFoundError, return code 404 (not found)
+		// If the error is a db.ResourceNotFoundError, return code 404 (not found) or the forms or candidates for the name
 		if _, ok := err.(*db.ResourceNotFoundError); ok {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			answerNotFound(w, r, err, db.PokemonTable, "pokemon", searchInput)
 		} else {
 			ErrorAndLog500(w, err)
 		}
 		return
 	}
-	// Build representation of the pokemon with URL instead of ID
-	pokemonWithURL := transformToURLResources(pokemon, r.Host, "pokemon")
+	// Build representation of the learnset with URLs instead of IDs
+	var learnsetWithURL []models.LearnsetMoveURL
+	for _, l := range learnset {
+		learnsetWithURL = append(learnsetWithURL, l.ToLearnsetMoveURL(BaseURL(r)))
+	}
 	// Build the response JSON with a map
 	responseJSON := orderedmap.New()
-	responseJSON.Set("id", camp.CampID)
-	responseJSON.Set("name", camp.CampName)
-	responseJSON.Set("description", camp.Description)
-	responseJSON.Set("unlockType", camp.UnlockType)
-	responseJSON.Set("cost", camp.Cost)
-	responseJSON.Set("pokemon", pokemonWithURL)
+	responseJSON.Set("pokemon", pokemon.ToNamedResourceURL(BaseURL(r), "pokemon"))
+	responseJSON.Set("moves", learnsetWithURL)
 	// Perform field limiting if necessary
-	limitResultFields(responseJSON, fieldLimitParams)
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
 	// Transform the map to JSON
 	json, err := json.Marshal(responseJSON)
 	if err != nil {
@@ -329,31 +1936,12 @@ func CampSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 		return
 	}
 	// Write the response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(json)
-}
-
-// DungeonListHandler handles requests on '/v1/dungeons' and returns a list of all dungeon resources.
-func DungeonListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	// Extract the ResourceListParams from the context with a type assertion
-	params, ok := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
-	if !ok {
-		ErrorAndLog500(w, errors.New("missing ResourceListParams"))
-		return
-	}
-	// Fetch the ability list from the database
-	count, dungeons, err := db.GetDungeonList(params.Sort, params.Pagination)
-	if err != nil {
-		ErrorAndLog500(w, err)
-		return
-	}
-	// Build response JSON with URLs instead of IDs and send it to the client
-	answerWithListJSON(count, dungeons, "dungeons", params.Pagination, w, r)
+	writeJSON(w, r, json)
 }
 
-// DungeonSearchHandler handles requests on '/v1/dungeons/:searcharg' and returns information about the desired dungeon.
-func DungeonSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+// PokemonEvolutionHandler handles requests on '/v1/pokemon/:searcharg/evolution' and returns
+// the evolution chain of the pokemon, starting with the base form of its evolution line.
+func PokemonEvolutionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	// Extract the FieldLimitingParams from the context with a type assertion
 	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
 	if !ok {
@@ -361,36 +1949,26 @@ func DungeonSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.
 		return
 	}
 	// Generate the input for the db search
-	searchInput := generateSearchInput(ps.ByName("searcharg"))
-	// Get the ability from the database
-	dungeon, pokemon, err := db.GetDungeon(searchInput)
+	searchInput := generatePokemonSearchInput(r, ps.ByName("searcharg"))
+	// Get the evolution chain from the database
+	chain, err := db.GetEvolutionChain(r.Context(), searchInput)
 	if err != nil {
-		// If the error is a db.ResourceNotFoundError, return code 404 (not found)
+		// If the error is a db.ResourceNotFoundError, return code 404 (not found) or the forms or candidates for the name
 		if _, ok := err.(*db.ResourceNotFoundError); ok {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			answerNotFound(w, r, err, db.PokemonTable, "pokemon", searchInput)
 		} else {
 			ErrorAndLog500(w, err)
 		}
 		return
 	}
-	// Build representation of the pokemon with URL instead of ID
-	var pokemonWithURL []models.DungeonPokemonURL
-	for _, p := range pokemon {
-		pokemonWithURL = append(pokemonWithURL, p.ToDungeonPokemonURL(r.Host))
-	}
 	// Build the response JSON with a map
 	responseJSON := orderedmap.New()
-	responseJSON.Set("id", dungeon.DungeonID)
-	responseJSON.Set("name", dungeon.DungeonName)
-	responseJSON.Set("levels", dungeon.Levels)
-	responseJSON.Set("startLevel", dungeon.StartLevel)
-	responseJSON.Set("teamSize", dungeon.TeamSize)
-	responseJSON.Set("itemsAllowed", dungeon.ItemsAllowed)
-	responseJSON.Set("pokemonJoining", dungeon.PokemonJoining)
-	responseJSON.Set("mapVisible", dungeon.MapVisible)
-	responseJSON.Set("pokemon", pokemonWithURL)
+	responseJSON.Set("chain", chain.ToEvolutionNodeURL(BaseURL(r)))
 	// Perform field limiting if necessary
-	limitResultFields(responseJSON, fieldLimitParams)
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
 	// Transform the map to JSON
 	json, err := json.Marshal(responseJSON)
 	if err != nil {
@@ -398,70 +1976,61 @@ func DungeonSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.
 		return
 	}
 	// Write the response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(json)
+	writeJSON(w, r, json)
 }
 
-// MoveListHandler handles requests on '/v1/moves' and returns a list of all move resources.
-func MoveListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+// PokemonTypeListHandler handles requests on '/v1/types' and returns a list of all pokemon type resources.
+func PokemonTypeListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	// Extract the ResourceListParams from the context with a type assertion
 	params, ok := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
 	if !ok {
 		ErrorAndLog500(w, errors.New("missing ResourceListParams"))
 		return
 	}
+	// Answer with all invalid parameters at once
+	if len(params.Errors) > 0 {
+		AnswerWithValidationErrors(w, params.Errors)
+		return
+	}
+	// Answer with the count only if requested
+	if params.CountOnly {
+		answerWithCountJSON(db.TypeTable, params.Filter, w, r)
+		return
+	}
 	// Fetch the ability list from the database
-	count, moves, err := db.GetMoveList(params.Sort, params.Pagination)
+	count, pokemonTypes, err := db.GetPokemonTypeList(r.Context(), params.Sort, params.Pagination, params.Filter)
 	if err != nil {
 		ErrorAndLog500(w, err)
 		return
 	}
 	// Build response JSON with URLs instead of IDs and send it to the client
-	answerWithListJSON(count, moves, "moves", params.Pagination, w, r)
+	answerWithListJSON(count, pokemonTypes, "types", params, w, r)
 }
 
-// MoveSearchHandler handles requests on '/v1/moves/:searcharg' and returns information about the desired move.
-func MoveSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	// Extract the FieldLimitingParams from the context with a type assertion
-	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
-	if !ok {
-		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
+// PokemonTypeMatrixHandler handles requests on '/v1/types/matrix' and returns the damage multipliers
+// of all attacking types against all defending types. If the client requests a stream with "stream=true"
+// or the Accept header "application/x-ndjson", each attacking type is written as a separate line of JSON
+// as soon as it is fetched from the database.
+func PokemonTypeMatrixHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	stream, _ := strconv.ParseBool(r.URL.Query().Get("stream"))
+	if stream || strings.Contains(r.Header.Get("Accept"), "application/x-ndjson") {
+		streamTypeMatrix(w, r)
 		return
 	}
-	// Generate the input for the db search
-	searchInput := generateSearchInput(ps.ByName("searcharg"))
-	// Get the ability from the database
-	move, moveType, pokemon, err := db.GetMove(searchInput)
+	// Collect all rows of the matrix
+	var rows []models.TypeMatrixRowURL
+	err := db.GetTypeMatrix(r.Context(), func(row models.TypeMatrixRowID) error {
+		rows = append(rows, row.ToTypeMatrixRowURL(BaseURL(r)))
+		return nil
+	})
 	if err != nil {
-		// If the error is a db.ResourceNotFoundError, return code 404 (not found)
-		if _, ok := err.(*db.ResourceNotFoundError); ok {
-			http.Error(w, err.Error(), http.StatusNotFound)
-		} else {
-			ErrorAndLog500(w, err)
-		}
+		ErrorAndLog500(w, err)
 		return
 	}
-	// Build representation of the pokemon with URL instead of ID
-	var pokemonWithURL []models.MovePokemonURL
-	for _, p := range pokemon {
-		pokemonWithURL = append(pokemonWithURL, p.ToMovePokemonURL(r.Host))
-	}
-	// Build the response JSON with a map
+	// Build the response JSON as a map
 	responseJSON := orderedmap.New()
-	responseJSON.Set("id", move.MoveID)
-	responseJSON.Set("name", move.MoveName)
-	responseJSON.Set("category", move.Category)
-	responseJSON.Set("range", move.Range)
-	responseJSON.Set("target", move.Target)
-	responseJSON.Set("initialPP", move.InitialPP)
-	responseJSON.Set("initialPower", move.InitialPower)
-	responseJSON.Set("accuracy", move.Accuracy)
-	responseJSON.Set("description", move.Description)
-	responseJSON.Set("type", moveType.ToNamedResourceURL(r.Host, "moves"))
-	responseJSON.Set("pokemon", pokemonWithURL)
-	// Perform field limiting if necessary
-	limitResultFields(responseJSON, fieldLimitParams)
+	responseJSON.Set("count", len(rows))
+	responseJSON.Set("results", rows)
 	// Transform the map to JSON
 	json, err := json.Marshal(responseJSON)
 	if err != nil {
@@ -469,41 +2038,131 @@ func MoveSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 		return
 	}
 	// Write the response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(json)
+	writeJSON(w, r, json)
 }
 
-// PokemonListHandler handles requests on '/v1/pokemon' and returns a list of all pokemon resources.
-func PokemonListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	// Extract the ResourceListParams from the context with a type assertion
-	params, ok := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
+// streamTypeMatrix writes the type matrix as newline-delimited JSON with one attacking type
+// per line. The response is flushed after each line if the ResponseWriter supports it.
+func streamTypeMatrix(w http.ResponseWriter, r *http.Request) {
+	flusher, _ := w.(http.Flusher)
+	started := false
+	err := db.GetTypeMatrix(r.Context(), func(row models.TypeMatrixRowID) error {
+		line, err := json.Marshal(row.ToTypeMatrixRowURL(BaseURL(r)))
+		if err != nil {
+			return err
+		}
+		// Send the headers with the first line, errors can not change the status code afterwards
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		if _, err = w.Write(append(line, '\n')); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if started {
+			// Log the error, the client notices the incomplete stream by the missing rows
+			pc, file, line, ok := runtime.Caller(0)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "streamTypeMatrix: failed to fetch caller information")
+				return
+			}
+			caller := logger.CallerInformation{Pc: pc, File: file, Line: line}
+			logger.LogError(err, caller)
+			return
+		}
+		ErrorAndLog500(w, err)
+	}
+}
+
+// PokemonTypeCoverageHandler handles requests on '/v1/types/coverage' and returns the best damage
+// multiplier the attacking types of the "types" argument achieve against each type.
+func PokemonTypeCoverageHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Normalize the names like GenerateSearchInput and remove duplicates
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(r.URL.Query().Get("types"), ",") {
+		name = strings.Title(strings.ToLower(strings.TrimSpace(name)))
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		AnswerWithValidationErrors(w, []ValidationError{{Parameter: "types", Reason: "at least one type name is required"}})
+		return
+	}
+	// Get the coverage from the database
+	attackers, coverage, err := db.GetTypeCoverage(r.Context(), names)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	// Unknown types would silently reduce the coverage, so they are answered with an error
+	found := make(map[string]bool)
+	for _, a := range attackers {
+		found[a.Name] = true
+	}
+	var errs []ValidationError
+	for _, name := range names {
+		if !found[name] {
+			errs = append(errs, ValidationError{Parameter: "types", Reason: fmt.Sprintf("unknown type '%v'", name)})
+		}
+	}
+	if len(errs) > 0 {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
+	// Build representation of the coverage with URL instead of ID
+	coverageWithURL := make([]models.TypeCoverageURL, 0, len(coverage))
+	for _, c := range coverage {
+		coverageWithURL = append(coverageWithURL, c.ToTypeCoverageURL(BaseURL(r)))
+	}
+	// Build the response JSON with a map
+	responseJSON := orderedmap.New()
+	responseJSON.Set("attackers", transformToURLResources(attackers, BaseURL(r), "types"))
+	responseJSON.Set("coverage", coverageWithURL)
+	// Extract the FieldLimitingParams from the context with a type assertion
+	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
 	if !ok {
-		ErrorAndLog500(w, errors.New("missing ResourceListParams"))
+		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
 		return
 	}
-	// Fetch the ability list from the database
-	count, pokemon, err := db.GetPokemonList(params.Sort, params.Pagination)
+	// Perform field limiting if necessary
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
+	// Transform the map to JSON
+	json, err := json.Marshal(responseJSON)
 	if err != nil {
 		ErrorAndLog500(w, err)
 		return
 	}
-	// Build response JSON with URLs instead of IDs and send it to the client
-	answerWithListJSON(count, pokemon, "pokemon", params.Pagination, w, r)
+	// Write the response
+	writeJSON(w, r, json)
 }
 
-// PokemonSearchHandler handles requests on '/v1/pokemon/:searcharg' and returns information about the desired pokemon.
-func PokemonSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+// PokemonTypeEffectivenessHandler handles requests on '/v1/types/:searcharg/effectiveness/:defender'
+// and returns the interaction of the attacking type with the defending type.
+func PokemonTypeEffectivenessHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	// Extract the FieldLimitingParams from the context with a type assertion
 	fieldLimitParams, ok := r.Context().Value(FieldLimitingParamsKey).(FieldLimitingParams)
 	if !ok {
 		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
 		return
 	}
-	// Generate the input for the db search
-	searchInput := generateSearchInput(ps.ByName("searcharg"))
-	// Get the ability from the database
-	pokemon, camp, abilities, dungeons, moves, pokemonTypes, err := db.GetPokemon(searchInput)
+	// Generate the inputs for the db search
+	attackerInput := GenerateSearchInput(ps.ByName("searcharg"))
+	defenderInput := GenerateSearchInput(ps.ByName("defender"))
+	// Get the matchup from the database
+	attacker, defender, interaction, err := db.GetTypeMatchup(r.Context(), attackerInput, defenderInput)
 	if err != nil {
 		// If the error is a db.ResourceNotFoundError, return code 404 (not found)
 		if _, ok := err.(*db.ResourceNotFoundError); ok {
@@ -513,36 +2172,17 @@ func PokemonSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.
 		}
 		return
 	}
-	// Build representation of the abilities with URL instead of ID
-	abilitiesWithURL := transformToURLResources(abilities, r.Host, "abilities")
-	// Build representation of the dungeons with URL instead of ID
-	var dungeonsWithURL []models.PokemonDungeonURL
-	for _, d := range dungeons {
-		dungeonsWithURL = append(dungeonsWithURL, d.ToPokemonDungeonURL(r.Host))
-	}
-	// Build representation of the moves with URL instead of ID
-	var movesWithURL []models.PokemonMoveURL
-	for _, m := range moves {
-		movesWithURL = append(movesWithURL, m.ToPokemonMoveURL(r.Host))
-	}
-	// Build representation of the types with URL instead of ID
-	pokemonTypesWithURL := transformToURLResources(pokemonTypes, r.Host, "types")
 	// Build the response JSON with a map
 	responseJSON := orderedmap.New()
-	responseJSON.Set("id", pokemon.DexNumber)
-	responseJSON.Set("name", pokemon.PokemonName)
-	responseJSON.Set("classification", pokemon.Classification)
-	responseJSON.Set("evolutionStage", pokemon.EvolutionStage)
-	responseJSON.Set("evolveCondition", pokemon.EvolveCondition)
-	responseJSON.Set("evolveLevel", pokemon.EvolveLevel)
-	responseJSON.Set("evolveCrystals", pokemon.EvolveCrystals)
-	responseJSON.Set("camp", camp.ToNamedResourceURL(r.Host, "camps"))
-	responseJSON.Set("abilities", abilitiesWithURL)
-	responseJSON.Set("dungeons", dungeonsWithURL)
-	responseJSON.Set("moves", movesWithURL)
-	responseJSON.Set("types", pokemonTypesWithURL)
+	responseJSON.Set("attacker", attacker.ToNamedResourceURL(BaseURL(r), "types"))
+	responseJSON.Set("defender", defender.ToNamedResourceURL(BaseURL(r), "types"))
+	responseJSON.Set("interaction", interaction)
+	responseJSON.Set("multiplier", models.InteractionMultiplier(interaction))
 	// Perform field limiting if necessary
-	limitResultFields(responseJSON, fieldLimitParams)
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
 	// Transform the map to JSON
 	json, err := json.Marshal(responseJSON)
 	if err != nil {
@@ -550,27 +2190,7 @@ func PokemonSearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.
 		return
 	}
 	// Write the response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(json)
-}
-
-// PokemonTypeListHandler handles requests on '/v1/types' and returns a list of all pokemon type resources.
-func PokemonTypeListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	// Extract the ResourceListParams from the context with a type assertion
-	params, ok := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
-	if !ok {
-		ErrorAndLog500(w, errors.New("missing ResourceListParams"))
-		return
-	}
-	// Fetch the ability list from the database
-	count, pokemonTypes, err := db.GetPokemonTypeList(params.Sort, params.Pagination)
-	if err != nil {
-		ErrorAndLog500(w, err)
-		return
-	}
-	// Build response JSON with URLs instead of IDs and send it to the client
-	answerWithListJSON(count, pokemonTypes, "types", params.Pagination, w, r)
+	writeJSON(w, r, json)
 }
 
 // PokemonTypeSearchHandler handles requests on '/v1/types/:searcharg' and returns information about the desired pokemonType.
@@ -581,31 +2201,79 @@ func PokemonTypeSearchHandler(w http.ResponseWriter, r *http.Request, ps httprou
 		ErrorAndLog500(w, errors.New("missing FieldLimitingParams"))
 		return
 	}
+	// Extract the NestedListParams of the interactions from the context
+	interactionParams, ok := nestedListParams(r, "interactions")
+	if !ok {
+		ErrorAndLog500(w, errors.New("missing NestedListParams"))
+		return
+	}
+	// Answer with all invalid parameters at once
+	if len(interactionParams.Errors) > 0 {
+		AnswerWithValidationErrors(w, interactionParams.Errors)
+		return
+	}
 	// Generate the input for the db search
-	searchInput := generateSearchInput(ps.ByName("searcharg"))
+	searchInput := GenerateSearchInput(ps.ByName("searcharg"))
 	// Get the ability from the database
-	pokemonType, interactions, err := db.GetPokemonType(searchInput)
+	var pokemonType models.PokemonType
+	var interactions []models.TypeInteractionID
+	counts, err := fetchWithCounts(r, db.TypeTable, searchInput, func(ctx context.Context) (err error) {
+		pokemonType, interactions, err = db.GetPokemonType(ctx, searchInput)
+		return err
+	})
 	if err != nil {
-		// If the error is a db.ResourceNotFoundError, return code 404 (not found)
+		// If the error is a db.ResourceNotFoundError, return code 404 (not found) or the candidates for the name
 		if _, ok := err.(*db.ResourceNotFoundError); ok {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			answerNotFound(w, r, err, db.TypeTable, "types", searchInput)
 		} else {
 			ErrorAndLog500(w, err)
 		}
 		return
 	}
-	// Build representation of the interactions with URL instead of ID
-	var interactionsWithURL []models.TypeInteractionURL
-	for _, i := range interactions {
-		interactionsWithURL = append(interactionsWithURL, i.ToTypeInteractionURL(r.Host))
+	// Answer with the raw database representation if requested
+	if rawRequested(r) {
+		answerWithRawJSON(map[string]interface{}{"type": pokemonType, "interactions": interactions}, w)
+		return
+	}
+	// Sort the interactions, which are ordered by the defender ID by default
+	switch interactionParams.Sort {
+	case DefenderAsc, DefenderDesc:
+		sort.SliceStable(interactions, func(i, j int) bool {
+			if interactionParams.Sort == DefenderDesc {
+				return interactions[i].Defender.Name > interactions[j].Defender.Name
+			}
+			return interactions[i].Defender.Name < interactions[j].Defender.Name
+		})
+	case StrengthAsc, StrengthDesc:
+		sort.SliceStable(interactions, func(i, j int) bool {
+			mi, mj := models.InteractionMultiplier(interactions[i].Interaction), models.InteractionMultiplier(interactions[j].Interaction)
+			if interactionParams.Sort == StrengthDesc {
+				return mi > mj
+			}
+			return mi < mj
+		})
+	}
+	// Build representation of the requested interactions with URL instead of ID
+	start, end := paginateNested(interactionParams, len(interactions))
+	interactionsWithURL := []models.TypeInteractionURL{}
+	for _, i := range interactions[start:end] {
+		interactionsWithURL = append(interactionsWithURL, i.ToTypeInteractionURL(BaseURL(r)))
 	}
 	// Build the response JSON with a map
 	responseJSON := orderedmap.New()
 	responseJSON.Set("id", pokemonType.TypeID)
 	responseJSON.Set("name", pokemonType.TypeName)
 	responseJSON.Set("interactions", interactionsWithURL)
+	setRelationshipCounts(responseJSON, counts)
 	// Perform field limiting if necessary
-	limitResultFields(responseJSON, fieldLimitParams)
+	if errs := limitResultFields(responseJSON, fieldLimitParams); errs != nil {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
+	// Wrap the page of paginated interactions with their total number, all interactions stay an array
+	if interactionParams.Paginated {
+		wrapNestedPage(responseJSON, "interactions", len(interactions))
+	}
 	// Transform the map to JSON
 	json, err := json.Marshal(responseJSON)
 	if err != nil {
@@ -613,7 +2281,5 @@ func PokemonTypeSearchHandler(w http.ResponseWriter, r *http.Request, ps httprou
 		return
 	}
 	// Write the response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(json)
+	writeJSON(w, r, json)
 }
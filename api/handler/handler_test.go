@@ -0,0 +1,958 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/iancoleman/orderedmap"
+	"github.com/janek64/pmd-dx-api/api/cache"
+	"github.com/janek64/pmd-dx-api/api/db"
+	"github.com/janek64/pmd-dx-api/api/models"
+	"github.com/janek64/pmd-dx-api/api/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBaseURL(t *testing.T) {
+	defer func(base string, trust bool, scheme string) {
+		publicBaseURL, trustProxyHeaders, defaultScheme = base, trust, scheme
+	}(publicBaseURL, trustProxyHeaders, defaultScheme)
+	tests := []struct {
+		name          string
+		publicBaseURL string
+		trustProxy    bool
+		defaultScheme string
+		tls           bool
+		headers       map[string]string
+		want          string
+	}{
+		{name: "request host", defaultScheme: "http", want: "http://api.test"},
+		{name: "default scheme", defaultScheme: "https", want: "https://api.test"},
+		{name: "tls", defaultScheme: "http", tls: true, want: "https://api.test"},
+		{name: "public base url with scheme", publicBaseURL: "https://public.test", defaultScheme: "http", want: "https://public.test"},
+		{name: "public base url with scheme over tls", publicBaseURL: "http://public.test", defaultScheme: "http", tls: true, want: "http://public.test"},
+		{name: "public base url without scheme", publicBaseURL: "public.test", defaultScheme: "http", want: "http://public.test"},
+		{name: "public base url without scheme over tls", publicBaseURL: "public.test", defaultScheme: "http", tls: true, want: "https://public.test"},
+		{
+			name:          "forwarded proto and host",
+			trustProxy:    true,
+			defaultScheme: "http",
+			headers:       map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "proxy.test, internal.test"},
+			want:          "https://proxy.test",
+		},
+		{
+			name:          "forwarded proto overrides tls",
+			trustProxy:    true,
+			defaultScheme: "http",
+			tls:           true,
+			headers:       map[string]string{"X-Forwarded-Proto": "http"},
+			want:          "http://api.test",
+		},
+		{
+			name:          "invalid forwarded proto",
+			trustProxy:    true,
+			defaultScheme: "http",
+			headers:       map[string]string{"X-Forwarded-Proto": "ftp"},
+			want:          "http://api.test",
+		},
+		{
+			name:          "untrusted forwarded headers",
+			defaultScheme: "http",
+			headers:       map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "proxy.test"},
+			want:          "http://api.test",
+		},
+		{
+			name:          "forwarded proto with public base url without scheme",
+			publicBaseURL: "public.test",
+			trustProxy:    true,
+			defaultScheme: "http",
+			headers:       map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "proxy.test"},
+			want:          "https://public.test",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publicBaseURL, trustProxyHeaders, defaultScheme = tt.publicBaseURL, tt.trustProxy, tt.defaultScheme
+			r := httptest.NewRequest("GET", "/v1/pokemon/25", nil)
+			r.Host = "api.test"
+			if tt.tls {
+				r.TLS = &tls.ConnectionState{}
+			} else {
+				r.TLS = nil
+			}
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			if got := BaseURL(r); got != tt.want {
+				t.Errorf("BaseURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildLinkHeader(t *testing.T) {
+	tests := []struct {
+		name       string
+		requestURL string
+		count      int
+		pagination db.Pagination
+		want       string
+	}{
+		{
+			name:       "first page",
+			requestURL: "/v1/pokemon?page=1",
+			count:      120,
+			pagination: db.Pagination{PerPage: 50, Page: 1},
+			want:       `<http://api.test/v1/pokemon?page=2>; rel="next", <null>; rel="previous", <http://api.test/v1/pokemon?page=3>; rel="last"`,
+		},
+		{
+			name:       "middle page",
+			requestURL: "/v1/pokemon?page=2",
+			count:      120,
+			pagination: db.Pagination{PerPage: 50, Page: 2},
+			want:       `<http://api.test/v1/pokemon?page=3>; rel="next", <http://api.test/v1/pokemon?page=1>; rel="previous", <http://api.test/v1/pokemon?page=3>; rel="last"`,
+		},
+		{
+			name:       "last page",
+			requestURL: "/v1/pokemon?page=3",
+			count:      120,
+			pagination: db.Pagination{PerPage: 50, Page: 3},
+			want:       `<null>; rel="next", <http://api.test/v1/pokemon?page=2>; rel="previous", <http://api.test/v1/pokemon?page=3>; rel="last"`,
+		},
+		{
+			name:       "full last page",
+			requestURL: "/v1/pokemon?page=2",
+			count:      100,
+			pagination: db.Pagination{PerPage: 50, Page: 2},
+			want:       `<null>; rel="next", <http://api.test/v1/pokemon?page=1>; rel="previous", <http://api.test/v1/pokemon?page=2>; rel="last"`,
+		},
+		{
+			name:       "single page",
+			requestURL: "/v1/pokemon",
+			count:      20,
+			pagination: db.Pagination{PerPage: 50, Page: 1},
+			want:       `<null>; rel="next", <null>; rel="previous", <http://api.test/v1/pokemon?page=1>; rel="last"`,
+		},
+		{
+			name:       "page after the last page",
+			requestURL: "/v1/pokemon?page=5",
+			count:      120,
+			pagination: db.Pagination{PerPage: 50, Page: 5},
+			want:       `<null>; rel="next", <null>; rel="previous", <http://api.test/v1/pokemon?page=3>; rel="last"`,
+		},
+		{
+			name:       "empty list",
+			requestURL: "/v1/pokemon?type=none",
+			count:      0,
+			pagination: db.Pagination{PerPage: 50, Page: 1},
+			want:       `<null>; rel="next", <null>; rel="previous", <http://api.test/v1/pokemon?page=1&type=none>; rel="last"`,
+		},
+		{
+			name:       "unknown count",
+			requestURL: "/v1/pokemon?page=2&no_count=true",
+			count:      -1,
+			pagination: db.Pagination{PerPage: 50, Page: 2},
+			want:       `<http://api.test/v1/pokemon?no_count=true&page=3>; rel="next", <http://api.test/v1/pokemon?no_count=true&page=1>; rel="previous"`,
+		},
+		{
+			name:       "existing query parameters",
+			requestURL: "/v1/pokemon?type=fire&sort=name&page=1&fields=name",
+			count:      60,
+			pagination: db.Pagination{PerPage: 50, Page: 1},
+			want:       `<http://api.test/v1/pokemon?fields=name&page=2&sort=name&type=fire>; rel="next", <null>; rel="previous", <http://api.test/v1/pokemon?fields=name&page=2&sort=name&type=fire>; rel="last"`,
+		},
+		{
+			name:       "effective page size",
+			requestURL: "/v1/pokemon?per_page=1000",
+			count:      450,
+			pagination: db.Pagination{PerPage: 200, Page: 1},
+			want:       `<http://api.test/v1/pokemon?page=2&per_page=200>; rel="next", <null>; rel="previous", <http://api.test/v1/pokemon?page=3&per_page=200>; rel="last"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestURL, err := url.Parse(tt.requestURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buildLinkHeader("http://api.test", requestURL, tt.count, tt.pagination, false); got != tt.want {
+				t.Errorf("buildLinkHeader() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildLinkHeaderKeepsTrickyParameters(t *testing.T) {
+	tests := []struct {
+		name       string
+		requestURL string
+		want       string
+	}{
+		{
+			name:       "value containing page",
+			requestURL: "/v1/pokemon?name=page%3D2&page=2",
+			want:       `<http://api.test/v1/pokemon?name=page%3D2&page=3>; rel="next", <http://api.test/v1/pokemon?name=page%3D2&page=1>; rel="previous", <http://api.test/v1/pokemon?name=page%3D2&page=3>; rel="last"`,
+		},
+		{
+			name:       "value containing per_page",
+			requestURL: "/v1/pokemon?q=per_page%3D3&page=2&per_page=10",
+			want:       `<http://api.test/v1/pokemon?page=3&per_page=10&q=per_page%3D3>; rel="next", <http://api.test/v1/pokemon?page=1&per_page=10&q=per_page%3D3>; rel="previous", <http://api.test/v1/pokemon?page=3&per_page=10&q=per_page%3D3>; rel="last"`,
+		},
+		{
+			name:       "parameter ending in page",
+			requestURL: "/v1/pokemon?homepage=4&page=2",
+			want:       `<http://api.test/v1/pokemon?homepage=4&page=3>; rel="next", <http://api.test/v1/pokemon?homepage=4&page=1>; rel="previous", <http://api.test/v1/pokemon?homepage=4&page=3>; rel="last"`,
+		},
+		{
+			name:       "encoded ampersand before page",
+			requestURL: "/v1/pokemon?name=a%26page%3D9&page=2",
+			want:       `<http://api.test/v1/pokemon?name=a%26page%3D9&page=3>; rel="next", <http://api.test/v1/pokemon?name=a%26page%3D9&page=1>; rel="previous", <http://api.test/v1/pokemon?name=a%26page%3D9&page=3>; rel="last"`,
+		},
+		{
+			name:       "page missing from the request",
+			requestURL: "/v1/pokemon?name=page",
+			want:       `<http://api.test/v1/pokemon?name=page&page=3>; rel="next", <http://api.test/v1/pokemon?name=page&page=1>; rel="previous", <http://api.test/v1/pokemon?name=page&page=3>; rel="last"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestURL, err := url.Parse(tt.requestURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pagination := db.Pagination{PerPage: 10, Page: 2}
+			if got := buildLinkHeader("http://api.test", requestURL, 25, pagination, false); got != tt.want {
+				t.Errorf("buildLinkHeader() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListWithUnknownCount(t *testing.T) {
+	resources := []models.NamedResourceID{{ID: 1, Name: "Stench"}, {ID: 2, Name: "Drizzle"}}
+	params := ResourceListParams{Pagination: db.Pagination{PerPage: 2, Page: 1}}
+	r := httptest.NewRequest("GET", "http://api.test/v1/abilities?per_page=2", nil)
+	r = r.WithContext(context.WithValue(r.Context(), FieldLimitingParamsKey, FieldLimitingParams{}))
+	w := httptest.NewRecorder()
+	answerWithListJSON(-1, resources, "abilities", params, w, r)
+	if w.Code != 200 {
+		t.Fatalf("status = %v, want 200: %v", w.Code, w.Body)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := body["count"]; ok {
+		t.Errorf("response contains a count: %v", w.Body)
+	}
+	if results, _ := body["results"].([]interface{}); len(results) != len(resources) {
+		t.Errorf("response does not contain the list: %v", w.Body)
+	}
+	wantLink := `<http://api.test/v1/abilities?page=2&per_page=2>; rel="next", <null>; rel="previous"`
+	if link := w.Header().Get("Link"); link != wantLink {
+		t.Errorf("Link = %v, want %v", link, wantLink)
+	}
+}
+
+// testPokemonJSON is a response of a pokemon with nested objects and arrays.
+const testPokemonJSON = `{"id":1,"name":"Bulbasaur","camp":{"id":3,"name":"Mystic Forest"},"moves":[{"move":{"id":1,"name":"Tackle"},"method":"level","level":1},{"move":{"id":2,"name":"Vine Whip"},"method":"level","level":7}]}`
+
+// orderedJSON decodes the JSON object into an orderedmap.
+func orderedJSON(t *testing.T, s string) *orderedmap.OrderedMap {
+	t.Helper()
+	object := orderedmap.New()
+	if err := json.Unmarshal([]byte(s), object); err != nil {
+		t.Fatal(err)
+	}
+	return object
+}
+
+func TestLimitResultFieldsNestedSelections(t *testing.T) {
+	field := func(name string, children ...FieldSelection) FieldSelection {
+		return FieldSelection{Name: name, Children: children}
+	}
+	tests := []struct {
+		name   string
+		fields []FieldSelection
+		want   string
+	}{
+		{
+			name:   "top-level fields",
+			fields: []FieldSelection{field("name"), field("id")},
+			want:   `{"id":1,"name":"Bulbasaur"}`,
+		},
+		{
+			name:   "dotted path",
+			fields: []FieldSelection{field("moves", field("move", field("name")))},
+			want:   `{"moves":[{"move":{"name":"Tackle"}},{"move":{"name":"Vine Whip"}}]}`,
+		},
+		{
+			name:   "merged paths of the same field",
+			fields: []FieldSelection{field("name"), field("moves", field("move", field("name"))), field("moves", field("level"))},
+			want:   `{"name":"Bulbasaur","moves":[{"move":{"name":"Tackle"},"level":1},{"move":{"name":"Vine Whip"},"level":7}]}`,
+		},
+		{
+			name:   "whole field and path",
+			fields: []FieldSelection{field("camp", field("name")), field("camp")},
+			want:   `{"camp":{"id":3,"name":"Mystic Forest"}}`,
+		},
+		{
+			name:   "path of a field without nested objects",
+			fields: []FieldSelection{field("name", field("first"))},
+			want:   `{"name":"Bulbasaur"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responseJSON := orderedJSON(t, testPokemonJSON)
+			if errs := limitResultFields(responseJSON, FieldLimitingParams{FieldLimitingEnabled: true, Fields: tt.fields}); errs != nil {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			got, err := json.Marshal(responseJSON)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("limited JSON =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitResultFieldsExclude(t *testing.T) {
+	field := func(name string, children ...FieldSelection) FieldSelection {
+		return FieldSelection{Name: name, Children: children}
+	}
+	tests := []struct {
+		name    string
+		exclude []FieldSelection
+		want    string
+	}{
+		{
+			name:    "top-level fields",
+			exclude: []FieldSelection{field("moves"), field("camp")},
+			want:    `{"id":1,"name":"Bulbasaur"}`,
+		},
+		{
+			name:    "nested field",
+			exclude: []FieldSelection{field("camp", field("id"))},
+			want:    `{"id":1,"name":"Bulbasaur","camp":{"name":"Mystic Forest"},"moves":[{"move":{"id":1,"name":"Tackle"},"method":"level","level":1},{"move":{"id":2,"name":"Vine Whip"},"method":"level","level":7}]}`,
+		},
+		{
+			name:    "fields of array elements",
+			exclude: []FieldSelection{field("moves", field("move", field("id")), field("method"))},
+			want:    `{"id":1,"name":"Bulbasaur","camp":{"id":3,"name":"Mystic Forest"},"moves":[{"move":{"name":"Tackle"},"level":1},{"move":{"name":"Vine Whip"},"level":7}]}`,
+		},
+		{
+			name:    "unknown fields",
+			exclude: []FieldSelection{field("types"), field("camp", field("region"))},
+			want:    testPokemonJSON,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responseJSON := orderedJSON(t, testPokemonJSON)
+			if errs := limitResultFields(responseJSON, FieldLimitingParams{ExcludeFields: tt.exclude}); errs != nil {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			got, err := json.Marshal(responseJSON)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("JSON without the excluded fields =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSONSortKeys(t *testing.T) {
+	body := `{"name":"Bulbasaur","id":1,"stats":{"speed":45,"attack":49},"moves":[{"name":"Tackle","accuracy":100,"power":1.50}],"big":12345678901234567890}`
+	tests := []struct {
+		name     string
+		sortKeys bool
+		want     string
+	}{
+		{name: "insertion order by default", sortKeys: false, want: body},
+		{
+			name:     "sorted keys",
+			sortKeys: true,
+			want:     `{"big":12345678901234567890,"id":1,"moves":[{"accuracy":100,"name":"Tackle","power":1.50}],"name":"Bulbasaur","stats":{"attack":49,"speed":45}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/v1/pokemon/1", nil)
+			r = r.WithContext(context.WithValue(r.Context(), FormatParamsKey, FormatParams{SortKeys: tt.sortKeys}))
+			w := httptest.NewRecorder()
+			writeJSON(w, r, []byte(body))
+			if w.Code != 200 {
+				t.Fatalf("status = %v, want 200: %v", w.Code, w.Body)
+			}
+			// The keys are only reordered, the compact layout and the numbers are kept as they are
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+// reference is a nested resource of a response with its ID and URL.
+type reference struct {
+	ID  int    `json:"id"`
+	URL string `json:"url"`
+}
+
+// decodeJSON encodes the value as JSON and decodes it into the target.
+func decodeJSON(t *testing.T, value interface{}, target interface{}) {
+	t.Helper()
+	body, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPokemonIDsAcrossRepresentations(t *testing.T) {
+	// Pikachu has a dex number that differs from the IDs of its move and type
+	pikachu := models.NamedResourceID{ID: 25, Name: "Pikachu"}
+	thunderbolt := models.NamedResourceID{ID: 85, Name: "Thunderbolt"}
+	electric := models.NamedResourceID{ID: 4, Name: "Electric"}
+	want := reference{ID: 25, URL: "http://api.test/v1/pokemon/25"}
+	r := httptest.NewRequest("GET", "http://api.test/v1/pokemon/25", nil)
+	r = r.WithContext(context.WithValue(r.Context(), FieldLimitingParamsKey, FieldLimitingParams{}))
+
+	// The details of the pokemon
+	var detail struct {
+		reference
+		Moves []struct {
+			Move reference `json:"move"`
+		} `json:"moves"`
+		Types []reference `json:"types"`
+	}
+	entry := models.PokemonEntryID{
+		Pokemon: models.Pokemon{DexNumber: pikachu.ID, PokemonName: pikachu.Name},
+		Moves:   []models.PokemonMoveID{{Move: thunderbolt}},
+		Types:   []models.NamedResourceID{electric},
+	}
+	decodeJSON(t, pokemonJSON(r, entry, nil), &detail)
+	if detail.ID != want.ID {
+		t.Errorf("pokemon details: id = %v, want %v", detail.ID, want.ID)
+	}
+	if len(detail.Moves) != 1 || detail.Moves[0].Move != (reference{ID: 85, URL: "http://api.test/v1/moves/85"}) {
+		t.Errorf("pokemon details: moves = %+v, want a reference of move 85", detail.Moves)
+	}
+	if len(detail.Types) != 1 || detail.Types[0] != (reference{ID: 4, URL: "http://api.test/v1/types/4"}) {
+		t.Errorf("pokemon details: types = %+v, want a reference of type 4", detail.Types)
+	}
+
+	// The pokemon list
+	w := httptest.NewRecorder()
+	answerWithListJSON(1, []models.NamedResourceID{pikachu}, "pokemon", ResourceListParams{Pagination: db.Pagination{PerPage: 50, Page: 1}}, w, r)
+	var list struct {
+		Results []reference `json:"results"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("invalid list response %v: %v", w.Body, err)
+	}
+	if len(list.Results) != 1 || list.Results[0] != want {
+		t.Errorf("pokemon list: results = %+v, want %+v", list.Results, want)
+	}
+
+	// The details of a move learned by the pokemon
+	var move struct {
+		reference
+		Type    reference `json:"type"`
+		Pokemon []struct {
+			Pokemon reference `json:"pokemon"`
+		} `json:"pokemon"`
+	}
+	decodeJSON(t, moveJSON(r, models.AttackMove{MoveID: thunderbolt.ID, MoveName: thunderbolt.Name}, electric, []models.MovePokemonID{{Pokemon: pikachu}}, 0), &move)
+	if move.ID != thunderbolt.ID {
+		t.Errorf("move details: id = %v, want %v", move.ID, thunderbolt.ID)
+	}
+	if want := (reference{ID: 4, URL: "http://api.test/v1/types/4"}); move.Type != want {
+		t.Errorf("move details: type = %+v, want %+v", move.Type, want)
+	}
+	if len(move.Pokemon) != 1 || move.Pokemon[0].Pokemon != want {
+		t.Errorf("move details: pokemon = %+v, want %+v", move.Pokemon, want)
+	}
+}
+
+func TestBuildLinkHeaderOffsetPagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		requestURL string
+		count      int
+		pagination db.Pagination
+		want       string
+	}{
+		{
+			name:       "first page",
+			requestURL: "/v1/pokemon?offset=0&limit=20",
+			count:      70,
+			pagination: db.Pagination{PerPage: 20, Page: 1},
+			want:       `<http://api.test/v1/pokemon?limit=20&offset=20>; rel="next", <null>; rel="previous", <http://api.test/v1/pokemon?limit=20&offset=60>; rel="last"`,
+		},
+		{
+			name:       "offset inside a page",
+			requestURL: "/v1/pokemon?offset=45&limit=20&type=fire",
+			count:      70,
+			pagination: db.Pagination{PerPage: 20, Page: 3},
+			want:       `<http://api.test/v1/pokemon?limit=20&offset=60&type=fire>; rel="next", <http://api.test/v1/pokemon?limit=20&offset=20&type=fire>; rel="previous", <http://api.test/v1/pokemon?limit=20&offset=60&type=fire>; rel="last"`,
+		},
+		{
+			name:       "effective limit",
+			requestURL: "/v1/pokemon?offset=200&limit=1000",
+			count:      450,
+			pagination: db.Pagination{PerPage: 200, Page: 2},
+			want:       `<http://api.test/v1/pokemon?limit=200&offset=400>; rel="next", <http://api.test/v1/pokemon?limit=200&offset=0>; rel="previous", <http://api.test/v1/pokemon?limit=200&offset=400>; rel="last"`,
+		},
+		{
+			name:       "offset without limit",
+			requestURL: "/v1/pokemon?offset=50",
+			count:      -1,
+			pagination: db.Pagination{PerPage: 50, Page: 2},
+			want:       `<http://api.test/v1/pokemon?offset=100>; rel="next", <http://api.test/v1/pokemon?offset=0>; rel="previous"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestURL, err := url.Parse(tt.requestURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buildLinkHeader("http://api.test", requestURL, tt.count, tt.pagination, true); got != tt.want {
+				t.Errorf("buildLinkHeader() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSONProtobuf(t *testing.T) {
+	pikachu := &pb.NamedResource{Id: 25, Name: "Pikachu", Url: "http://api.test/v1/pokemon/25"}
+	thunderbolt := &pb.NamedResource{Id: 85, Name: "Thunderbolt", Url: "http://api.test/v1/moves/85"}
+	electric := &pb.NamedResource{Id: 4, Name: "Electric", Url: "http://api.test/v1/types/4"}
+	tests := []struct {
+		name        string
+		target      string
+		accept      string
+		body        string
+		messageType string
+		want        proto.Message
+	}{
+		{
+			name:        "pokemon with paginated moves",
+			target:      "/v1/pokemon/25?format=protobuf&moves_per_page=1",
+			body:        `{"id":25,"name":"Pikachu","classification":"Mouse Pokemon","evolutionStage":2,"evolveCondition":"","evolveLevel":null,"evolveCrystals":null,"camp":null,"abilities":[],"dungeons":[],"moves":{"count":12,"results":[{"move":{"id":85,"name":"Thunderbolt","url":"http://api.test/v1/moves/85"},"method":"level","level":24,"cost":null}]},"types":[{"id":4,"name":"Electric","url":"http://api.test/v1/types/4"}]}`,
+			messageType: "pmd.v1.Pokemon",
+			want: &pb.Pokemon{
+				Id: 25, Name: "Pikachu", Classification: "Mouse Pokemon", EvolutionStage: 2,
+				Moves:      []*pb.PokemonMove{{Move: thunderbolt, Method: "level", Level: proto.Int32(24)}},
+				MovesCount: proto.Int32(12),
+				Types:      []*pb.NamedResource{electric},
+			},
+		},
+		{
+			name:        "Accept header",
+			target:      "/v1/moves/85",
+			accept:      "application/json;q=0.5, application/x-protobuf",
+			body:        `{"id":85,"name":"Thunderbolt","category":"Special","initialPP":15,"initialPower":9,"accuracy":100,"type":{"id":4,"name":"Electric","url":"http://api.test/v1/types/4"},"pokemon":[],"pokemonCount":3}`,
+			messageType: "pmd.v1.Move",
+			want:        &pb.Move{Id: 85, Name: "Thunderbolt", Category: "Special", InitialPp: 15, InitialPower: 9, Accuracy: 100, Type: electric, PokemonCount: proto.Int32(3)},
+		},
+		{
+			name:        "list",
+			target:      "/v1/pokemon?format=protobuf&include=types",
+			body:        `{"count":1025,"perPage":1,"results":[{"id":25,"name":"Pikachu","url":"http://api.test/v1/pokemon/25","types":[{"id":4,"name":"Electric","url":"http://api.test/v1/types/4"}]}]}`,
+			messageType: "pmd.v1.ResourceList",
+			want: &pb.ResourceList{Count: proto.Int32(1025), PerPage: 1, Results: []*pb.NamedResource{
+				{Id: 25, Name: "Pikachu", Url: "http://api.test/v1/pokemon/25", Types: []*pb.NamedResource{electric}},
+			}},
+		},
+		{
+			name:        "responses keyed by names",
+			target:      "/v1/moves/by-type?format=protobuf",
+			body:        `{"Electric":{"count":1,"moves":[{"id":85,"name":"Thunderbolt","url":"http://api.test/v1/moves/85"}]},"Ghost":{"count":0,"moves":[]}}`,
+			messageType: "pmd.v1.MovesByType",
+			want: &pb.MovesByType{Types: map[string]*pb.TypeMoves{
+				"Electric": {Count: 1, Moves: []*pb.NamedResource{thunderbolt}},
+				"Ghost":    {},
+			}},
+		},
+		{
+			name:        "paginated interactions",
+			target:      "/v1/types/4?format=protobuf&interactions_per_page=1",
+			body:        `{"id":4,"name":"Electric","interactions":{"count":3,"results":[{"defender":{"id":25,"name":"Pikachu","url":"http://api.test/v1/pokemon/25"},"interaction":"not very effective","multiplier":0.5}]}}`,
+			messageType: "pmd.v1.Type",
+			want: &pb.Type{Id: 4, Name: "Electric", InteractionsCount: proto.Int32(3), Interactions: []*pb.TypeInteraction{
+				{Defender: pikachu, Interaction: "not very effective", Multiplier: 0.5},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.target, nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			writeJSON(w, r, []byte(tt.body))
+			if w.Code != 200 {
+				t.Fatalf("status = %v, want 200: %v", w.Code, w.Body)
+			}
+			if got, want := w.Header().Get("Content-Type"), "application/x-protobuf; messageType="+tt.messageType; got != want {
+				t.Errorf("Content-Type = %q, want %q", got, want)
+			}
+			if got, want := w.Header().Get(ChecksumHeader), fmt.Sprintf("%x", sha256.Sum256(w.Body.Bytes())); got != want {
+				t.Errorf("%v = %v, want the checksum of the protobuf body %v", ChecksumHeader, got, want)
+			}
+			// The typed message needs less space than the JSON, which repeats the field names
+			if w.Body.Len() >= len(tt.body) {
+				t.Errorf("the body has %v bytes, want less than the %v bytes of the JSON", w.Body.Len(), len(tt.body))
+			}
+			got := tt.want.ProtoReflect().New().Interface()
+			if err := proto.Unmarshal(w.Body.Bytes(), got); err != nil {
+				t.Fatalf("the body is no %v: %v", tt.messageType, err)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("decoded message = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSONProtobufUnsupportedRepresentations(t *testing.T) {
+	body := `{"count":1,"perPage":50,"results":{"Pikachu":{"id":25,"url":"http://api.test/v1/pokemon/25"}}}`
+	tests := []struct {
+		name          string
+		target        string
+		formatParams  FormatParams
+		wantParameter string
+	}{
+		{name: "map representation", target: "/v1/pokemon?format=protobuf&as=map", wantParameter: "as"},
+		{name: "flattened pokemon", target: "/v1/pokemon/25?format=protobuf&flat=true", formatParams: FormatParams{Flat: true}, wantParameter: "flat"},
+		{name: "template", target: "/v1/pokemon/25?format=protobuf", formatParams: FormatParams{Template: "chatbot"}, wantParameter: "template"},
+		{name: "endpoint without message", target: "/v1/unknown/1/2/3?format=protobuf", wantParameter: "format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.target, nil)
+			r = r.WithContext(context.WithValue(r.Context(), FormatParamsKey, tt.formatParams))
+			w := httptest.NewRecorder()
+			// The template is validated by the middleware, so the representation is rejected before it is applied
+			writeProtobuf(w, r, []byte(body))
+			if w.Code != 400 {
+				t.Fatalf("status = %v, want 400: %v", w.Code, w.Body)
+			}
+			var response struct{ Errors []ValidationError }
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if len(response.Errors) != 1 || response.Errors[0].Parameter != tt.wantParameter {
+				t.Errorf("errors = %+v, want an error for %v", response.Errors, tt.wantParameter)
+			}
+		})
+	}
+}
+
+func TestProtobufRequested(t *testing.T) {
+	tests := []struct {
+		target string
+		accept string
+		want   bool
+	}{
+		{target: "/v1/pokemon/25", want: false},
+		{target: "/v1/pokemon/25?format=protobuf", want: true},
+		{target: "/v1/pokemon/25", accept: "application/x-protobuf", want: true},
+		{target: "/v1/pokemon/25", accept: "application/json", want: false},
+		// The format parameter takes precedence over the Accept header
+		{target: "/v1/pokemon/25?format=json", accept: "application/x-protobuf", want: false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.target, nil)
+		r.Header.Set("Accept", tt.accept)
+		if got := ProtobufRequested(r); got != tt.want {
+			t.Errorf("ProtobufRequested(%v with Accept %q) = %v, want %v", tt.target, tt.accept, got, tt.want)
+		}
+	}
+}
+
+// openEventStream starts an in-memory redis instance and requests an event stream from EventsHandler.
+// The stream is closed after the test.
+func openEventStream(t *testing.T) *http.Response {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	cache.SetClient(client)
+	events := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		EventsHandler(w, r, nil)
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(func() {
+		cancel()
+		events.Close()
+		cache.SetClient(nil)
+		client.Close()
+	})
+	request, err := http.NewRequestWithContext(ctx, "GET", events.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { response.Body.Close() })
+	if got := response.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/event-stream") {
+		t.Fatalf("Content-Type = %q, want text/event-stream", got)
+	}
+	return response
+}
+
+func TestEventsHandlerSendsPublishedDataVersions(t *testing.T) {
+	response := openEventStream(t)
+	// The subscription is confirmed before the response starts, so no version published now is missed
+	if err := cache.PublishDataVersion("2022-03-02T07:30:00Z"); err != nil {
+		t.Fatal(err)
+	}
+	reader := bufio.NewReader(response.Body)
+	var event []string
+	for len(event) < 2 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading the event stream failed after %q: %v", event, err)
+		}
+		if line = strings.TrimSuffix(line, "\n"); line != "" {
+			event = append(event, line)
+		}
+	}
+	want := []string{"event: data-version", "data: 2022-03-02T07:30:00Z"}
+	if !reflect.DeepEqual(event, want) {
+		t.Errorf("event = %q, want %q", event, want)
+	}
+}
+
+func TestWrapNestedPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		listName string
+		total    int
+		want     string
+	}{
+		{
+			name:     "interactions of a type",
+			response: `{"id":10,"name":"Fire","interactions":[{"defender":{"id":11,"name":"Water"},"multiplier":0.5}],"pokemonCount":3}`,
+			listName: "interactions",
+			total:    17,
+			want:     `{"id":10,"name":"Fire","interactions":{"count":17,"results":[{"defender":{"id":11,"name":"Water"},"multiplier":0.5}]},"pokemonCount":3}`,
+		},
+		{
+			name:     "empty page",
+			response: `{"id":1,"moves":[]}`,
+			listName: "moves",
+			total:    4,
+			want:     `{"id":1,"moves":{"count":4,"results":[]}}`,
+		},
+		{
+			name:     "list removed by field limiting",
+			response: `{"id":10,"name":"Fire"}`,
+			listName: "interactions",
+			total:    17,
+			want:     `{"id":10,"name":"Fire"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responseJSON := orderedJSON(t, tt.response)
+			wrapNestedPage(responseJSON, tt.listName, tt.total)
+			got, err := json.Marshal(responseJSON)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("response =\n%s\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStopEventStreams(t *testing.T) {
+	t.Cleanup(func() {
+		eventStreamsStopped = make(chan struct{})
+		stopEventStreamsOnce = sync.Once{}
+	})
+	response := openEventStream(t)
+	ended := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(io.Discard, response.Body)
+		ended <- err
+	}()
+	StopEventStreams()
+	// Stopping again, e.g. on a second shutdown, must not panic
+	StopEventStreams()
+	select {
+	case err := <-ended:
+		if err != nil {
+			t.Errorf("the stream ended with %v, want a complete response", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the event stream was not ended")
+	}
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		target string
+		accept string
+		want   string
+	}{
+		{target: "/v1/pokemon", want: FormatJSON},
+		{target: "/v1/pokemon", accept: "text/csv", want: FormatCSV},
+		{target: "/v1/pokemon", accept: "text/html", want: FormatJSON},
+		// The quality values decide instead of a fixed precedence of the formats
+		{target: "/v1/pokemon", accept: "text/csv;q=0.1, application/json", want: FormatJSON},
+		{target: "/v1/pokemon", accept: "application/x-protobuf;q=0.9, text/csv;q=0.5", want: FormatProtobuf},
+		{target: "/v1/pokemon", accept: "application/xml;q=0.5, application/vnd.api+json;q=0.8", want: FormatJSONAPI},
+		{target: "/v1/pokemon", accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", want: FormatXML},
+		// Earlier ranges win if the quality is the same, exact media types win over wildcards
+		{target: "/v1/pokemon", accept: "application/xml, text/csv", want: FormatXML},
+		{target: "/v1/pokemon", accept: "*/*, text/csv", want: FormatCSV},
+		{target: "/v1/pokemon", accept: "*/*;q=0.9, text/csv;q=0.5", want: FormatJSON},
+		// Not acceptable and invalid ranges are ignored
+		{target: "/v1/pokemon", accept: "text/csv;q=0, application/xml;q=0.2", want: FormatXML},
+		{target: "/v1/pokemon", accept: "text/csv;q=high, application/xml;q=0.2", want: FormatXML},
+		{target: "/v1/pokemon", accept: "text/csv;q=2", want: FormatJSON},
+		// The format parameter takes precedence over the Accept header
+		{target: "/v1/pokemon?format=csv", accept: "application/json", want: FormatCSV},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.target, nil)
+		r.Header.Set("Accept", tt.accept)
+		if got := NegotiateFormat(r); got != tt.want {
+			t.Errorf("NegotiateFormat(%v with Accept %q) = %q, want %q", tt.target, tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestAnswerWithPokemonFlatWithFieldLimiting(t *testing.T) {
+	field := func(name string, children ...FieldSelection) FieldSelection {
+		return FieldSelection{Name: name, Children: children}
+	}
+	entry := models.PokemonEntryID{
+		Pokemon:   models.Pokemon{DexNumber: 1, PokemonName: "Bulbasaur"},
+		Camp:      models.NamedResourceID{ID: 1, Name: "Mystic Forest"},
+		Abilities: []models.NamedResourceID{{ID: 1, Name: "Overgrow"}, {ID: 2, Name: "Chlorophyll"}},
+		Dungeons:  []models.PokemonDungeonID{{Dungeon: models.NamedResourceID{ID: 3, Name: "Mt. Bristle"}, IsSuper: true}},
+		Moves:     []models.PokemonMoveID{{Move: models.NamedResourceID{ID: 1, Name: "Tackle"}, Method: "level"}},
+	}
+	tests := []struct {
+		name   string
+		params FieldLimitingParams
+		want   string
+	}{
+		{
+			name:   "top-level fields",
+			params: FieldLimitingParams{FieldLimitingEnabled: true, Fields: []FieldSelection{field("name"), field("abilities")}},
+			want:   `{"name":"Bulbasaur","abilities":"Overgrow,Chlorophyll"}`,
+		},
+		{
+			name:   "nested selections",
+			params: FieldLimitingParams{FieldLimitingEnabled: true, Fields: []FieldSelection{field("camp", field("name")), field("moves", field("move", field("name")))}},
+			want:   `{"camp":"Mystic Forest","moves":"Tackle"}`,
+		},
+		{
+			name:   "strict nested selection",
+			params: FieldLimitingParams{FieldLimitingEnabled: true, StrictFields: true, Fields: []FieldSelection{field("dungeons", field("dungeon", field("name")))}},
+			want:   `{"dungeons":"Mt. Bristle"}`,
+		},
+		{
+			name:   "excluded nested fields",
+			params: FieldLimitingParams{ExcludeFields: []FieldSelection{field("id"), field("classification"), field("evolutionStage"), field("evolveCondition"), field("evolveLevel"), field("evolveCrystals"), field("types"), field("moves", field("level"))}},
+			want:   `{"name":"Bulbasaur","camp":"Mystic Forest","abilities":"Overgrow,Chlorophyll","dungeons":"Mt. Bristle","moves":"Tackle"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/v1/pokemon/1?flat=true", nil)
+			ctx := context.WithValue(r.Context(), FieldLimitingParamsKey, tt.params)
+			ctx = context.WithValue(ctx, FormatParamsKey, FormatParams{Flat: true})
+			w := httptest.NewRecorder()
+			answerWithPokemon(w, r.WithContext(ctx), nil, entry)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %v, want %v: %v", w.Code, http.StatusOK, w.Body)
+			}
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachePurgeHandlerPurgesAllFormats(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	cache.SetClient(client)
+	t.Cleanup(func() {
+		cache.SetClient(nil)
+		client.Close()
+	})
+	// store caches a response for the URL like the cache middleware
+	store := func(target string) {
+		u, err := url.Parse(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.StoreResponse("http://api.test"+cache.NormalizeURL(u), http.Header{}, []byte("{}"), `"etag"`, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name    string
+		purge   string
+		deleted int
+		kept    int
+	}{
+		{name: "all formats", purge: "/v1/pokemon/25?fields=name", deleted: 6, kept: 2},
+		{name: "single format", purge: "/v1/pokemon/25?format=csv&fields=name", deleted: 1, kept: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.FlushAll()
+			for _, format := range []string{"json", "csv", "jsonapi", "xml", "protobuf"} {
+				store("/v1/pokemon/25?fields=name&format=" + format)
+			}
+			store("/v1/pokemon/25?fields=name")
+			// Other URLs are kept
+			store("/v1/pokemon/25")
+			store("/v1/pokemon/26?fields=name&format=csv")
+			r := httptest.NewRequest("DELETE", "/admin/cache?url="+url.QueryEscape(tt.purge), nil)
+			w := httptest.NewRecorder()
+			CachePurgeHandler(w, r, nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %v, want %v: %v", w.Code, http.StatusOK, w.Body)
+			}
+			if want := fmt.Sprintf(`{"deleted":%v}`, tt.deleted); w.Body.String() != want {
+				t.Errorf("body = %v, want %v", w.Body, want)
+			}
+			if keys := server.Keys(); len(keys) != tt.kept {
+				t.Errorf("kept keys %v, want %v", keys, tt.kept)
+			}
+		})
+	}
+}
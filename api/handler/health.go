@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/janek64/pmd-dx-api/api/cache"
+	"github.com/janek64/pmd-dx-api/api/db"
+	"github.com/janek64/pmd-dx-api/api/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+// readinessTimeout limits the duration of the pings of the readiness check.
+const readinessTimeout = 2 * time.Second
+
+// HealthHandler handles requests on '/healthz' and always answers with code 200,
+// showing that the server is able to handle requests.
+func HealthHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	responseJSON := orderedmap.New()
+	responseJSON.Set("status", "ok")
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	writeJSONOnly(w, json)
+}
+
+// ReadyHandler handles requests on '/readyz' and checks if the database and redis can be reached.
+// If either of them is down, the request is answered with code 503 (Service Unavailable).
+func ReadyHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+	ready := true
+	checks := orderedmap.New()
+	for _, dependency := range []struct {
+		name string
+		ping func(context.Context) error
+	}{{"database", db.Ping}, {"redis", cache.Ping}} {
+		if err := dependency.ping(ctx); err != nil {
+			ready = false
+			checks.Set(dependency.name, "down")
+			logReadinessError(fmt.Errorf("readiness check of %v failed: %w", dependency.name, err))
+		} else {
+			checks.Set(dependency.name, "ok")
+		}
+	}
+	responseJSON := orderedmap.New()
+	if ready {
+		responseJSON.Set("status", "ok")
+	} else {
+		responseJSON.Set("status", "unavailable")
+	}
+	responseJSON.Set("checks", checks)
+	json, err := json.Marshal(responseJSON)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	if !ready {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write(json)
+		return
+	}
+	writeJSONOnly(w, json)
+}
+
+// logReadinessError logs the error of a failed readiness check to the error log.
+func logReadinessError(err error) {
+	pc, file, line, ok := runtime.Caller(1)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "ReadyHandler: failed to fetch caller information")
+		return
+	}
+	caller := logger.CallerInformation{Pc: pc, File: file, Line: line}
+	logger.LogError(err, caller)
+}
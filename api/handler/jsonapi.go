@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+// jsonAPIMediaType is the media type of JSON:API documents.
+const jsonAPIMediaType = "application/vnd.api+json"
+
+// JSONAPIRequested checks if the response should be encoded as a JSON:API document, either requested
+// with the query parameter "format=jsonapi" or by preferring "application/vnd.api+json" in the Accept header.
+func JSONAPIRequested(r *http.Request) bool {
+	return requestedFormat(r) == FormatJSONAPI
+}
+
+// writeJSONAPI converts the JSON of a response into a JSON:API document and writes it as a
+// successful response. The keys are sorted if requested like for the standard representation.
+func writeJSONAPI(w http.ResponseWriter, r *http.Request, responseJSON []byte, sortKeys bool) {
+	object := orderedmap.New()
+	if err := json.Unmarshal(responseJSON, object); err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	document, err := json.Marshal(jsonAPIDocument(requestResourceType(r), object))
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	if sortKeys {
+		if document, err = sortJSONKeys(document); err != nil {
+			ErrorAndLog500(w, err)
+			return
+		}
+	}
+	setChecksumHeader(w, document)
+	w.Header().Set("Content-Type", jsonAPIMediaType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
+
+// jsonAPIDocument wraps a response in the JSON:API envelope. Lists of resources become an array of
+// resource objects with the remaining fields, e.g. the count, as meta information. Responses with
+// an id become a single resource object and all other responses are only returned as meta information.
+func jsonAPIDocument(resourceType string, object *orderedmap.OrderedMap) *orderedmap.OrderedMap {
+	document := orderedmap.New()
+	if results, ok := object.Get("results"); ok {
+		if elements, ok := results.([]interface{}); ok {
+			data := make([]interface{}, 0, len(elements))
+			for _, element := range elements {
+				if resource, ok := element.(orderedmap.OrderedMap); ok {
+					data = append(data, jsonAPIResource(resourceType, &resource))
+				}
+			}
+			document.Set("data", data)
+			object.Delete("results")
+			if len(object.Keys()) > 0 {
+				document.Set("meta", object)
+			}
+			return document
+		}
+	}
+	if _, ok := object.Get("id"); ok {
+		document.Set("data", jsonAPIResource(resourceType, object))
+		return document
+	}
+	document.Set("meta", object)
+	return document
+}
+
+// jsonAPIResource converts a resource into a JSON:API resource object. Nested resources and arrays
+// of nested resources become relationships, all other fields are attributes. The URL of the resource
+// is used as its self link.
+func jsonAPIResource(resourceType string, object *orderedmap.OrderedMap) *orderedmap.OrderedMap {
+	resource := orderedmap.New()
+	resource.Set("type", resourceType)
+	id, _ := object.Get("id")
+	resource.Set("id", fmt.Sprint(id))
+	attributes := orderedmap.New()
+	relationships := orderedmap.New()
+	for _, key := range object.Keys() {
+		if key == "id" || key == "url" {
+			continue
+		}
+		value, _ := object.Get(key)
+		if relationship, ok := jsonAPIRelationship(value); ok {
+			relationships.Set(key, relationship)
+		} else {
+			attributes.Set(key, value)
+		}
+	}
+	if len(attributes.Keys()) > 0 {
+		resource.Set("attributes", attributes)
+	}
+	if len(relationships.Keys()) > 0 {
+		resource.Set("relationships", relationships)
+	}
+	if resourceURL, ok := object.Get("url"); ok {
+		links := orderedmap.New()
+		links.Set("self", resourceURL)
+		resource.Set("links", links)
+	}
+	return resource
+}
+
+// jsonAPIRelationship converts a nested resource, an array of nested resources or a paginated list
+// of nested resources into a JSON:API relationship. ok is false if the value is none of these.
+// To-one relationships link to the related resource, the other fields of entries wrapping a nested
+// resource, e.g. the level of a move, are kept as meta information of the resource identifier.
+func jsonAPIRelationship(value interface{}) (relationship *orderedmap.OrderedMap, ok bool) {
+	switch v := value.(type) {
+	case orderedmap.OrderedMap:
+		if results, isList := v.Get("results"); isList {
+			// Paginated nested list with the total count
+			relationship, ok = jsonAPIRelationship(results)
+			if ok {
+				if count, hasCount := v.Get("count"); hasCount {
+					meta := orderedmap.New()
+					meta.Set("count", count)
+					relationship.Set("meta", meta)
+				}
+			}
+			return relationship, ok
+		}
+		identifier, related, isResource := jsonAPIIdentifier(&v)
+		if !isResource {
+			return nil, false
+		}
+		relationship = orderedmap.New()
+		links := orderedmap.New()
+		links.Set("related", related)
+		relationship.Set("links", links)
+		relationship.Set("data", identifier)
+		return relationship, true
+	case []interface{}:
+		// Empty arrays can not be distinguished from other arrays
+		if len(v) == 0 {
+			return nil, false
+		}
+		data := make([]interface{}, len(v))
+		for i, element := range v {
+			object, isObject := element.(orderedmap.OrderedMap)
+			if !isObject {
+				return nil, false
+			}
+			identifier, _, isResource := jsonAPIIdentifier(&object)
+			if !isResource {
+				return nil, false
+			}
+			data[i] = identifier
+		}
+		relationship = orderedmap.New()
+		relationship.Set("data", data)
+		return relationship, true
+	}
+	return nil, false
+}
+
+// jsonAPIIdentifier returns the JSON:API resource identifier and the URL of a nested resource. Objects
+// wrapping exactly one nested resource are identified by it with the other fields as meta information.
+// ok is false if the object is neither a nested resource nor wraps one.
+func jsonAPIIdentifier(object *orderedmap.OrderedMap) (identifier *orderedmap.OrderedMap, related interface{}, ok bool) {
+	if resourceType, isResource := nestedResourceType(object); isResource {
+		identifier = orderedmap.New()
+		identifier.Set("type", resourceType)
+		id, _ := object.Get("id")
+		identifier.Set("id", fmt.Sprint(id))
+		related, _ = object.Get("url")
+		return identifier, related, true
+	}
+	var wrapped string
+	for _, key := range object.Keys() {
+		value, _ := object.Get(key)
+		nested, isObject := value.(orderedmap.OrderedMap)
+		if !isObject {
+			continue
+		}
+		if _, isResource := nestedResourceType(&nested); isResource {
+			if wrapped != "" {
+				return nil, nil, false
+			}
+			wrapped = key
+		}
+	}
+	if wrapped == "" {
+		return nil, nil, false
+	}
+	value, _ := object.Get(wrapped)
+	nested := value.(orderedmap.OrderedMap)
+	identifier, related, _ = jsonAPIIdentifier(&nested)
+	meta := orderedmap.New()
+	for _, key := range object.Keys() {
+		if key != wrapped {
+			value, _ := object.Get(key)
+			meta.Set(key, value)
+		}
+	}
+	if len(meta.Keys()) > 0 {
+		identifier.Set("meta", meta)
+	}
+	return identifier, related, true
+}
+
+// nestedResourceType returns the type of a nested resource, which is the collection in its URL.
+// ok is false if the object is not a nested resource with an id and a URL.
+func nestedResourceType(object *orderedmap.OrderedMap) (resourceType string, ok bool) {
+	if _, hasID := object.Get("id"); !hasID {
+		return "", false
+	}
+	value, _ := object.Get("url")
+	resourceURL, isString := value.(string)
+	if !isString {
+		return "", false
+	}
+	// Only the path matters, so the URL is split instead of parsed
+	segments := strings.Split(strings.Trim(resourceURL, "/"), "/")
+	if len(segments) < 2 {
+		return "", false
+	}
+	return segments[len(segments)-2], true
+}
+
+// requestResourceType returns the type of the resources answered by the request, which is
+// the collection following the version in the path, e.g. "pokemon" for "/v1/pokemon/1".
+func requestResourceType(r *http.Request) string {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 2 {
+		return segments[0]
+	}
+	return segments[1]
+}
@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/janek64/pmd-dx-api/api/db"
+	"github.com/janek64/pmd-dx-api/api/debug"
+	"github.com/julienschmidt/httprouter"
+)
+
+// QueryParameter describes a query parameter supported by an endpoint of this API.
+type QueryParameter struct {
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	AllowedValues []string `json:"allowedValues,omitempty"`
+	Description   string   `json:"description"`
+}
+
+// Allows checks if the value is allowed for the QueryParameter. All values
+// are allowed if the QueryParameter does not define any AllowedValues.
+func (q *QueryParameter) Allows(value string) bool {
+	if len(q.AllowedValues) == 0 {
+		return true
+	}
+	for _, v := range q.AllowedValues {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceParameters lists the query parameters supported by the list and detail endpoints of a resource.
+type ResourceParameters struct {
+	List     []QueryParameter `json:"list"`
+	Detail   []QueryParameter `json:"detail"`
+	Stats    []QueryParameter `json:"stats,omitempty"`
+	Random   []QueryParameter `json:"random,omitempty"`
+	Matrix   []QueryParameter `json:"matrix,omitempty"`
+	Coverage []QueryParameter `json:"coverage,omitempty"`
+}
+
+// Definitions of all query parameters used by the middleware
+var (
+	FieldsParameter = QueryParameter{
+		Name:        "fields",
+		Type:        "string",
+		Description: "Comma-separated list of the fields that should be included in the response, the fields of nested objects can be selected in brackets or with dotted paths, e.g. moves(name) or moves.name.",
+	}
+	StrictFieldsParameter = QueryParameter{
+		Name:        "strict_fields",
+		Type:        "boolean",
+		Description: "Reject selected fields that do not exist in the response instead of omitting them.",
+	}
+	ExcludeParameter = QueryParameter{
+		Name:        "exclude",
+		Type:        "string",
+		Description: "Comma-separated list of the fields that should be omitted from the response, ignored if fields is provided.",
+	}
+	FlatParameter = QueryParameter{
+		Name:          "flat",
+		Type:          "boolean",
+		AllowedValues: []string{"true", "false"},
+		Description:   "Replace nested resources with their names.",
+	}
+	FormatParameter = QueryParameter{
+		Name:          "format",
+		Type:          "string",
+		AllowedValues: []string{"json", "csv", "jsonapi", "xml", "protobuf"},
+		Description:   "Representation of the response, can also be requested with the Accept header.",
+	}
+	SortKeysParameter = QueryParameter{
+		Name:        "sort_keys",
+		Type:        "boolean",
+		Description: "Sort the keys of all JSON objects alphabetically instead of the default order.",
+	}
+	TemplateParameter = QueryParameter{
+		Name:        "template",
+		Type:        "string",
+		Description: "Name of a registered response template the response is reshaped with.",
+	}
+	NoCacheParameter = QueryParameter{
+		Name:        "nocache",
+		Type:        "boolean",
+		Description: "Generate a fresh response instead of answering from the cache, like the header Cache-Control: no-cache.",
+	}
+	AsParameter = QueryParameter{
+		Name:          "as",
+		Type:          "string",
+		AllowedValues: []string{"array", "map"},
+		Description:   "Representation of the results, either an array (default) or a map using the names as keys.",
+	}
+	RawParameter = QueryParameter{
+		Name:          "raw",
+		Type:          "boolean",
+		AllowedValues: []string{"true", "false"},
+		Description:   "Return the raw database representation. Requires the admin token, not stable.",
+	}
+	SortParameter = QueryParameter{
+		Name:          "sort",
+		Type:          "string",
+		AllowedValues: []string{db.IDAsc, db.IDDesc, db.NameAsc, db.NameDesc, db.UpdatedAsc, db.UpdatedDesc},
+		Description:   "Comma-separated sorting of the resource list, later values sort entries that are equal for the previous ones.",
+	}
+	PerPageParameter = QueryParameter{
+		Name:        "per_page",
+		Type:        "integer",
+		Description: "Number of resources per page.",
+	}
+	PageParameter = QueryParameter{
+		Name:        "page",
+		Type:        "integer",
+		Description: "Page of the resource list, beginning with 1.",
+	}
+	OffsetParameter = QueryParameter{
+		Name:        "offset",
+		Type:        "integer",
+		Description: "Number of resources to skip. Alternative to page, ignored if page or per_page are provided.",
+	}
+	LimitParameter = QueryParameter{
+		Name:        "limit",
+		Type:        "integer",
+		Description: "Number of resources per page. Alternative to per_page, ignored if page or per_page are provided.",
+	}
+	GroupByParameter = QueryParameter{
+		Name:          "group_by",
+		Type:          "string",
+		AllowedValues: []string{db.GroupByType, db.GroupByCamp, db.GroupByEvolutionStage},
+		Description:   "Dimension the resources are grouped by. Required.",
+	}
+	MoveSortParameter = QueryParameter{
+		Name:          "sort",
+		Type:          "string",
+		AllowedValues: append(append([]string{}, SortParameter.AllowedValues...), db.PowerAsc, db.PowerDesc, db.AccuracyAsc, db.AccuracyDesc, db.PPAsc, db.PPDesc),
+		Description:   "Comma-separated sorting of the move list, later values sort entries that are equal for the previous ones.",
+	}
+	CategoryParameter = QueryParameter{
+		Name:          "category",
+		Type:          "string",
+		AllowedValues: []string{db.Physical, db.Special, db.Status},
+		Description:   "Only include moves of this category.",
+	}
+	LearnTypeParameter = QueryParameter{
+		Name:          "learn_type",
+		Type:          "string",
+		AllowedValues: []string{db.LearnByLevel, db.LearnByTutor, db.LearnByTM},
+		Description:   "Only include moves that at least one pokemon learns this way.",
+	}
+	HasPokemonParameter = QueryParameter{
+		Name:        "has_pokemon",
+		Type:        "boolean",
+		Description: "Only include resources with (true) or without (false) any pokemon.",
+	}
+	MinEvolveCrystalsParameter = QueryParameter{
+		Name:        "evolve_crystals_gte",
+		Type:        "integer",
+		Description: "Only include pokemon evolving with at least this number of crystals, pokemon not evolving with crystals are excluded.",
+	}
+	MaxEvolveCrystalsParameter = QueryParameter{
+		Name:        "evolve_crystals_lte",
+		Type:        "integer",
+		Description: "Only include pokemon evolving with at most this number of crystals, pokemon not evolving with crystals are excluded.",
+	}
+	MaxStartLevelParameter = QueryParameter{
+		Name:        "max_start_level",
+		Type:        "integer",
+		Description: "Only include dungeons starting at this level or below, dungeons without a start level are excluded.",
+	}
+	MinPowerParameter = QueryParameter{
+		Name:        "min_power",
+		Type:        "integer",
+		Description: "Only include moves with at least this power.",
+	}
+	MaxPowerParameter = QueryParameter{
+		Name:        "max_power",
+		Type:        "integer",
+		Description: "Only include moves with at most this power.",
+	}
+	MinAccuracyParameter = QueryParameter{
+		Name:        "min_accuracy",
+		Type:        "integer",
+		Description: "Only include moves with at least this accuracy.",
+	}
+	MaxAccuracyParameter = QueryParameter{
+		Name:        "max_accuracy",
+		Type:        "integer",
+		Description: "Only include moves with at most this accuracy.",
+	}
+	TypeParameter = QueryParameter{
+		Name:        "type",
+		Type:        "string",
+		Description: "Only include pokemon of the type with this ID or name.",
+	}
+	IncludeParameter = QueryParameter{
+		Name:          "include",
+		Type:          "string",
+		AllowedValues: []string{"types"},
+		Description:   "Comma-separated list of related resources to include in each listed resource.",
+	}
+	IncludeCountsParameter = QueryParameter{
+		Name:          "include",
+		Type:          "string",
+		AllowedValues: []string{"counts"},
+		Description:   "Include the number of resources referencing the resource, e.g. pokemonCount.",
+	}
+	FormParameter = QueryParameter{
+		Name:        "form",
+		Type:        "string",
+		Description: "Form of the pokemon species requested by name, e.g. 'attack' for 'Deoxys (Attack)'.",
+	}
+	NamesParameter = QueryParameter{
+		Name:        "names",
+		Type:        "string",
+		Description: "Comma-separated list of names to look up, replaces the list with the matching resources. At most 50 names are allowed.",
+	}
+	IDsParameter = QueryParameter{
+		Name:        "ids",
+		Type:        "string",
+		Description: "Comma-separated list of IDs to look up, replaces the list with the full representation of the matching resources. At most 100 IDs are allowed.",
+	}
+	CountOnlyParameter = QueryParameter{
+		Name:          "count_only",
+		Type:          "boolean",
+		AllowedValues: []string{"true", "false"},
+		Description:   "Only return the number of resources matching the filters.",
+	}
+	StreamParameter = QueryParameter{
+		Name:          "stream",
+		Type:          "boolean",
+		AllowedValues: []string{"true", "false"},
+		Description:   "Stream the response as newline-delimited JSON.",
+	}
+	NoCountParameter = QueryParameter{
+		Name:          "no_count",
+		Type:          "boolean",
+		AllowedValues: []string{"true", "false"},
+		Description:   "Omit the count of the list and the last page link for a faster response.",
+	}
+	InteractionSortParameter = QueryParameter{
+		Name:          "interactions_sort",
+		Type:          "string",
+		AllowedValues: []string{DefenderAsc, DefenderDesc, StrengthAsc, StrengthDesc},
+		Description:   "Sorting of the interactions, ordered by the defender ID by default.",
+	}
+	InteractionPerPageParameter = QueryParameter{
+		Name:        "interactions_per_page",
+		Type:        "integer",
+		Description: "Number of interactions per page. All interactions are returned if neither this nor interactions_page is provided.",
+	}
+	InteractionPageParameter = QueryParameter{
+		Name:        "interactions_page",
+		Type:        "integer",
+		Description: "Page of the interactions, beginning with 1.",
+	}
+	MatchParameter = QueryParameter{
+		Name:          "match",
+		Type:          "string",
+		AllowedValues: []string{db.Prefix, db.Fuzzy},
+		Description:   "Return the resources with similar names if no resource has the exact name.",
+	}
+	AtLevelParameter = QueryParameter{
+		Name:        "at_level",
+		Type:        "integer",
+		Description: "Include the PP and power of the move at this level (1-100) next to the base values.",
+	}
+	TypesParameter = QueryParameter{
+		Name:        "types",
+		Type:        "string",
+		Description: "Comma-separated list of the names of the attacking types. Required.",
+	}
+	UpdatedSinceParameter = QueryParameter{
+		Name:        "updated_since",
+		Type:        "RFC3339 timestamp",
+		Description: "Only include resources updated after this point in time.",
+	}
+)
+
+// nestedPageParameters returns the query parameters paginating the lists with the names that are nested in a single resource.
+func nestedPageParameters(listNames ...string) []QueryParameter {
+	var parameters []QueryParameter
+	for _, listName := range listNames {
+		parameters = append(parameters, QueryParameter{
+			Name:        listName + "_per_page",
+			Type:        "integer",
+			Description: "Number of " + listName + " per page. All " + listName + " are returned as an array if neither this nor " + listName + "_page is provided.",
+		}, QueryParameter{
+			Name:        listName + "_page",
+			Type:        "integer",
+			Description: "Page of the " + listName + ", beginning with 1. The page is returned as an object with the total count and the results.",
+		})
+	}
+	return parameters
+}
+
+// debugParameters are the query parameters of debug features, which are only supported by debug builds.
+var debugParameters = func() []QueryParameter {
+	if debug.Enabled {
+		return []QueryParameter{RawParameter}
+	}
+	return nil
+}()
+
+// defaultListParameters are the query parameters supported by all resource lists.
+var defaultListParameters = append([]QueryParameter{FieldsParameter, StrictFieldsParameter, ExcludeParameter, FormatParameter, SortKeysParameter, TemplateParameter, NoCacheParameter, AsParameter, SortParameter, PerPageParameter, PageParameter, OffsetParameter, LimitParameter, UpdatedSinceParameter, CountOnlyParameter, NoCountParameter}, debugParameters...)
+
+// defaultDetailParameters are the query parameters supported by all single resources.
+var defaultDetailParameters = append([]QueryParameter{FieldsParameter, StrictFieldsParameter, ExcludeParameter, FormatParameter, SortKeysParameter, TemplateParameter, NoCacheParameter, MatchParameter}, debugParameters...)
+
+// ParameterRegistry contains the query parameters supported by the endpoints of
+// each resource, using the resource type name of the URL as the key.
+var ParameterRegistry = map[string]ResourceParameters{
+	"abilities": {List: append([]QueryParameter{HasPokemonParameter}, defaultListParameters...), Detail: append([]QueryParameter{IncludeCountsParameter}, defaultDetailParameters...)},
+	"camps":     {List: defaultListParameters, Detail: append([]QueryParameter{IncludeCountsParameter}, defaultDetailParameters...)},
+	"dungeons":  {List: append([]QueryParameter{MaxStartLevelParameter}, defaultListParameters...), Detail: append([]QueryParameter{IncludeCountsParameter}, defaultDetailParameters...)},
+	"moves": {
+		List:   append([]QueryParameter{FieldsParameter, StrictFieldsParameter, ExcludeParameter, FormatParameter, SortKeysParameter, TemplateParameter, NoCacheParameter, AsParameter, MoveSortParameter, PerPageParameter, PageParameter, OffsetParameter, LimitParameter, UpdatedSinceParameter, CategoryParameter, LearnTypeParameter, MinPowerParameter, MaxPowerParameter, MinAccuracyParameter, MaxAccuracyParameter, HasPokemonParameter, CountOnlyParameter, NoCountParameter}, debugParameters...),
+		Detail: append([]QueryParameter{AtLevelParameter, IncludeCountsParameter}, defaultDetailParameters...),
+	},
+	"pokemon": {
+		List:   append([]QueryParameter{NamesParameter, IDsParameter, TypeParameter, MinEvolveCrystalsParameter, MaxEvolveCrystalsParameter, IncludeParameter}, defaultListParameters...),
+		Detail: append(append([]QueryParameter{FlatParameter, FormParameter}, nestedPageParameters("abilities", "dungeons", "moves")...), defaultDetailParameters...),
+		Stats:  []QueryParameter{GroupByParameter, FieldsParameter},
+		Random: append(append([]QueryParameter{TypeParameter, FlatParameter}, nestedPageParameters("abilities", "dungeons", "moves")...), append([]QueryParameter{FieldsParameter, StrictFieldsParameter, ExcludeParameter, FormatParameter, SortKeysParameter, TemplateParameter}, debugParameters...)...),
+	},
+	"types": {
+		List:     append([]QueryParameter{HasPokemonParameter}, defaultListParameters...),
+		Detail:   append([]QueryParameter{InteractionSortParameter, InteractionPerPageParameter, InteractionPageParameter, IncludeCountsParameter}, defaultDetailParameters...),
+		Matrix:   []QueryParameter{StreamParameter},
+		Coverage: []QueryParameter{TypesParameter, FieldsParameter},
+	},
+}
+
+// ParametersHandler returns a handler for OPTIONS requests on '/v1/<resourceTypeName>' that
+// lists the query parameters supported by the endpoints of the resource.
+func ParametersHandler(resourceTypeName string) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		params := ParameterRegistry[resourceTypeName]
+		// Build the response JSON with a map
+		responseJSON := orderedmap.New()
+		responseJSON.Set("resource", resourceTypeName)
+		responseJSON.Set("list", params.List)
+		responseJSON.Set("detail", params.Detail)
+		if len(params.Stats) > 0 {
+			responseJSON.Set("stats", params.Stats)
+		}
+		if len(params.Random) > 0 {
+			responseJSON.Set("random", params.Random)
+		}
+		if len(params.Matrix) > 0 {
+			responseJSON.Set("matrix", params.Matrix)
+		}
+		if len(params.Coverage) > 0 {
+			responseJSON.Set("coverage", params.Coverage)
+		}
+		// Transform the map to JSON
+		json, err := json.Marshal(responseJSON)
+		if err != nil {
+			ErrorAndLog500(w, err)
+			return
+		}
+		// Write the response
+		w.Header().Set("Allow", "GET, OPTIONS")
+		writeJSONOnly(w, json)
+	}
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/janek64/pmd-dx-api/api/pb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtobufRequested checks if the response should be encoded as Protocol Buffers, either requested with
+// the query parameter "format=protobuf" or by preferring "application/x-protobuf" in the Accept header.
+func ProtobufRequested(r *http.Request) bool {
+	return requestedFormat(r) == FormatProtobuf
+}
+
+// writeProtobuf converts the JSON of a response into the message of the requested endpoint, defined in
+// api/pb/resources.proto, and writes it as a successful response. The message is marshaled deterministically,
+// so the same response always has the same body and checksum, e.g. for cached responses. Representations
+// without a fixed schema, e.g. flattened pokemon or templates, are answered with status 400 (Bad Request).
+func writeProtobuf(w http.ResponseWriter, r *http.Request, responseJSON []byte) {
+	if errs := protobufRepresentationErrors(r); len(errs) > 0 {
+		AnswerWithValidationErrors(w, errs)
+		return
+	}
+	message, ok := protobufMessage(r)
+	if !ok {
+		AnswerWithValidationErrors(w, []ValidationError{{Parameter: "format", Reason: "the response is not available as protobuf"}})
+		return
+	}
+	messageJSON, err := protobufJSON(responseJSON, message)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(messageJSON, message); err != nil {
+		ErrorAndLog500(w, fmt.Errorf("converting the response to %v failed: %w", message.ProtoReflect().Descriptor().FullName(), err))
+		return
+	}
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	setChecksumHeader(w, body)
+	// The name of the message allows clients to decode the body without knowing the endpoint
+	w.Header().Set("Content-Type", fmt.Sprintf("application/x-protobuf; messageType=%v", message.ProtoReflect().Descriptor().FullName()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// protobufRepresentationErrors returns the parameters of the request that change the representation of
+// the response in a way the messages can not express.
+func protobufRepresentationErrors(r *http.Request) []ValidationError {
+	var errs []ValidationError
+	if formatParams, ok := r.Context().Value(FormatParamsKey).(FormatParams); ok {
+		if formatParams.Flat {
+			errs = append(errs, ValidationError{Parameter: "flat", Reason: "flattened responses are not available as protobuf"})
+		}
+		if formatParams.Template != "" {
+			errs = append(errs, ValidationError{Parameter: "template", Reason: "response templates are not available as protobuf"})
+		}
+	}
+	if r.URL.Query().Get("as") == "map" {
+		errs = append(errs, ValidationError{Parameter: "as", Reason: "the map representation is not available as protobuf"})
+	}
+	return errs
+}
+
+// protobufMessage returns an empty message for the response of the request, chosen by its path like
+// the routes registered in main.go. ok is false if the response has no message.
+func protobufMessage(r *http.Request) (message proto.Message, ok bool) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "v1" {
+		return nil, false
+	}
+	resourceType, segments := segments[1], segments[2:]
+	queryParams := r.URL.Query()
+	listParams, _ := r.Context().Value(ResourceListParamsKey).(ResourceListParams)
+	switch {
+	case resourceType == "search" && len(segments) == 0:
+		return &pb.SearchResults{}, true
+	case len(segments) == 0 && listParams.CountOnly, len(segments) == 1 && segments[0] == "count":
+		return &pb.Count{}, true
+	case resourceType == "pokemon" && len(segments) == 0 && queryParams.Get("names") != "":
+		return &pb.PokemonNameLookup{}, true
+	case resourceType == "pokemon" && len(segments) == 0 && queryParams.Get("ids") != "":
+		return &pb.PokemonIDLookup{}, true
+	case len(segments) == 0:
+		return &pb.ResourceList{}, true
+	}
+	switch resourceType {
+	case "abilities":
+		if len(segments) == 1 {
+			return &pb.Ability{}, true
+		}
+	case "camps":
+		if len(segments) == 1 {
+			return &pb.Camp{}, true
+		}
+	case "dungeons":
+		if len(segments) == 1 {
+			return &pb.Dungeon{}, true
+		}
+	case "moves":
+		switch {
+		case len(segments) == 1 && segments[0] == "by-type":
+			return &pb.MovesByType{}, true
+		case len(segments) == 1:
+			return &pb.Move{}, true
+		}
+	case "pokemon":
+		switch {
+		case len(segments) == 1 && segments[0] == "stats":
+			return &pb.PokemonGroupCounts{}, true
+		case len(segments) == 1:
+			return &pb.Pokemon{}, true
+		case len(segments) == 2 && segments[1] == "defenses":
+			return &pb.PokemonDefenses{}, true
+		case len(segments) == 2 && segments[1] == "full-learnset":
+			return &pb.PokemonFullLearnset{}, true
+		case len(segments) == 2 && segments[1] == "evolution":
+			return &pb.PokemonEvolution{}, true
+		}
+	case "types":
+		switch {
+		case len(segments) == 1 && segments[0] == "matrix":
+			return &pb.TypeMatrix{}, true
+		case len(segments) == 1 && segments[0] == "coverage":
+			return &pb.TypeCoverage{}, true
+		case len(segments) == 1:
+			return &pb.Type{}, true
+		case len(segments) == 3 && segments[1] == "effectiveness":
+			return &pb.TypeEffectiveness{}, true
+		}
+	}
+	return nil, false
+}
+
+// protobufJSON adapts the JSON of a response to the JSON form of the message. Responses keyed by names,
+// e.g. the moves by type, become the map field of their message. The pages of paginated nested lists
+// become the list field and their total number of entries the field named like the list with the
+// suffix "Count", e.g. "movesCount". Numbers are kept as they are instead of being converted to floats.
+func protobufJSON(responseJSON []byte, message proto.Message) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(responseJSON))
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	fields := message.ProtoReflect().Descriptor().Fields()
+	if fields.Len() == 1 && fields.Get(0).IsMap() {
+		return json.Marshal(map[string]interface{}{fields.Get(0).JSONName(): object})
+	}
+	for key, value := range object {
+		page, isObject := value.(map[string]interface{})
+		countField := fields.ByJSONName(key + "Count")
+		if !isObject || countField == nil {
+			continue
+		}
+		object[key] = page["results"]
+		object[countField.JSONName()] = page["count"]
+	}
+	return json.Marshal(object)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+// responseTemplates contains the registered response templates by their name. Each template maps
+// the keys of the reshaped response to the dotted paths of their values in the standard response.
+var responseTemplates = make(map[string]*orderedmap.OrderedMap)
+
+// LoadResponseTemplates registers the response templates of the JSON file at the path. The file contains
+// an object with the template names as keys, each template is an object mapping the keys of the reshaped
+// response to a dotted path in the standard response, e.g. {"chatbot": {"title": "name", "types": "types.*.name"}}.
+// Returns an error if the file can not be read or a template is invalid.
+func LoadResponseTemplates(path string) error {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	templates := orderedmap.New()
+	if err := json.Unmarshal(file, templates); err != nil {
+		return err
+	}
+	registered := make(map[string]*orderedmap.OrderedMap)
+	for _, name := range templates.Keys() {
+		value, _ := templates.Get(name)
+		template, ok := value.(orderedmap.OrderedMap)
+		if !ok {
+			return fmt.Errorf("template '%v' must be an object", name)
+		}
+		for _, key := range template.Keys() {
+			path, _ := template.Get(key)
+			if p, ok := path.(string); !ok || p == "" {
+				return fmt.Errorf("key '%v' of template '%v' must map to a non-empty path", key, name)
+			}
+		}
+		registered[name] = &template
+	}
+	responseTemplates = registered
+	return nil
+}
+
+// TemplateRegistered checks if a response template with the name is registered.
+func TemplateRegistered(name string) bool {
+	_, ok := responseTemplates[name]
+	return ok
+}
+
+// applyTemplate reshapes the JSON response with the registered template of the name. Values whose
+// path does not exist in the response are set to null.
+func applyTemplate(name string, responseJSON []byte) ([]byte, error) {
+	template, ok := responseTemplates[name]
+	if !ok {
+		return nil, fmt.Errorf("response template '%v' is not registered", name)
+	}
+	// Numbers are kept as they are instead of being converted to floats
+	decoder := json.NewDecoder(bytes.NewReader(responseJSON))
+	decoder.UseNumber()
+	var response interface{}
+	if err := decoder.Decode(&response); err != nil {
+		return nil, err
+	}
+	reshaped := orderedmap.New()
+	for _, key := range template.Keys() {
+		path, _ := template.Get(key)
+		reshaped.Set(key, resolvePath(response, strings.Split(path.(string), ".")))
+	}
+	return json.Marshal(reshaped)
+}
+
+// resolvePath returns the value at the path in the value. Elements of arrays are selected by their
+// index, while "*" selects the rest of the path of each element. Returns nil if the path does not exist.
+func resolvePath(value interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return resolvePath(v[path[0]], path[1:])
+	case []interface{}:
+		if path[0] == "*" {
+			values := make([]interface{}, len(v))
+			for i, element := range v {
+				values[i] = resolvePath(element, path[1:])
+			}
+			return values
+		}
+		if i, err := strconv.Atoi(path[0]); err == nil && i >= 0 && i < len(v) {
+			return resolvePath(v[i], path[1:])
+		}
+	}
+	return nil
+}
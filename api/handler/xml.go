@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+// XMLRequested checks if the response should be encoded as XML, either requested with the query
+// parameter "format=xml" or by preferring "application/xml" or "text/xml" in the Accept header.
+func XMLRequested(r *http.Request) bool {
+	return requestedFormat(r) == FormatXML
+}
+
+// writeXML converts the JSON of a response into XML with a "response" root element and writes it
+// as a successful response. The elements keep the order of the keys in the JSON.
+func writeXML(w http.ResponseWriter, responseJSON []byte) {
+	object := orderedmap.New()
+	if err := json.Unmarshal(responseJSON, object); err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buffer)
+	if err := encodeXMLValue(encoder, "response", *object); err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	if err := encoder.Flush(); err != nil {
+		ErrorAndLog500(w, err)
+		return
+	}
+	setChecksumHeader(w, buffer.Bytes())
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buffer.Bytes())
+}
+
+// encodeXMLValue encodes a JSON value as an element with the name. Objects contain an element for each
+// key and arrays an "item" element for each entry. Keys that are no valid element names, e.g. the names
+// of resources in the map representation, are encoded as "entry" elements with the key as attribute.
+// null is encoded as an empty element.
+func encodeXMLValue(encoder *xml.Encoder, name string, value interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if !validXMLName(name) {
+		start = xml.StartElement{Name: xml.Name{Local: "entry"}, Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}}}
+	}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case orderedmap.OrderedMap:
+		for _, key := range v.Keys() {
+			nested, _ := v.Get(key)
+			if err := encodeXMLValue(encoder, key, nested); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, element := range v {
+			if err := encodeXMLValue(encoder, "item", element); err != nil {
+				return err
+			}
+		}
+	case nil:
+	case string:
+		if err := encoder.EncodeToken(xml.CharData(v)); err != nil {
+			return err
+		}
+	case float64:
+		if err := encoder.EncodeToken(xml.CharData(strconv.FormatFloat(v, 'f', -1, 64))); err != nil {
+			return err
+		}
+	default:
+		if err := encoder.EncodeToken(xml.CharData(fmt.Sprint(v))); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// validXMLName checks if the name can be used as an element name. Only ASCII letters, digits,
+// hyphens and underscores are accepted and the name must start with a letter or an underscore.
+func validXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		case i > 0 && (c >= '0' && c <= '9' || c == '-'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -4,6 +4,8 @@
 package logger
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -12,6 +14,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strings"
 	"time"
 
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -23,8 +26,104 @@ var errorLogger *log.Logger
 var accessLogFile *lumberjack.Logger
 var errorLogFile *lumberjack.Logger
 
+// logToStdout is true if the logs are written to stdout/stderr instead of log files.
+var logToStdout bool
+
+// logJSON is true if the access log contains a JSON object per request instead of the Combined Log Format.
+var logJSON bool
+
+// Level represents the severity of the entries of the error log.
+type Level int
+
+const (
+	LevelInfo Level = iota
+	LevelWarning
+	LevelError
+)
+
+// logLevel is the minimum level of the entries written to the error log.
+var logLevel = LevelInfo
+
+// logLevels maps the values of LOG_LEVEL to the levels.
+var logLevels = map[string]Level{
+	"info":    LevelInfo,
+	"warn":    LevelWarning,
+	"warning": LevelWarning,
+	"error":   LevelError,
+}
+
+// RequestIDHeader is the header containing the ID of a request, which is sent with the
+// response and logged to correlate reported failures with the logs.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the key of the request ID in the context of a request.
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of the context containing the request ID.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID of the context or an empty string if it has none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// excludedPaths contains the request paths that are not written to the access log.
+var excludedPaths = make(map[string]bool)
+
 // InitLogger opens all necessary log files and creates the log.Logger used by this package.
+// If LOG_OUTPUT is set to "stdout", access logs are written to stdout and errors to stderr instead.
+// If LOG_FORMAT is set to "json", the access log is written as one JSON object per request.
+// LOG_LEVEL ("info", "warn" or "error", "info" by default) omits error log entries of lower levels
+// and the requests of the comma-separated paths of LOG_EXCLUDE_PATHS are not written to the access log.
 func InitLogger() error {
+	// Get the log level and the excluded paths from environment
+	if value, ok := os.LookupEnv("LOG_LEVEL"); ok && value != "" {
+		level, ok := logLevels[strings.ToLower(value)]
+		if !ok {
+			return fmt.Errorf("invalid value '%v' for LOG_LEVEL, must be 'info', 'warn' or 'error'", value)
+		}
+		logLevel = level
+	}
+	if value, ok := os.LookupEnv("LOG_EXCLUDE_PATHS"); ok {
+		for _, path := range strings.Split(value, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				excludedPaths[path] = true
+			}
+		}
+	}
+	// Get the access log format from environment
+	logFormat, ok := os.LookupEnv("LOG_FORMAT")
+	if !ok {
+		logFormat = "combined"
+	}
+	switch logFormat {
+	case "json":
+		logJSON = true
+	case "combined":
+		logJSON = false
+	default:
+		return fmt.Errorf("invalid value '%v' for LOG_FORMAT, must be 'combined' or 'json'", logFormat)
+	}
+	// Get log output from environment
+	logOutput, ok := os.LookupEnv("LOG_OUTPUT")
+	if !ok {
+		logOutput = "file"
+	}
+	switch logOutput {
+	case "stdout":
+		// Bypass the log files so the logs can be collected by the platform
+		logToStdout = true
+		accessLogger = log.New(os.Stdout, "", 0)
+		errorLogger = log.New(os.Stderr, "", log.Ldate|log.Ltime)
+		return nil
+	case "file":
+		logToStdout = false
+	default:
+		return fmt.Errorf("invalid value '%v' for LOG_OUTPUT, must be 'stdout' or 'file'", logOutput)
+	}
 	// Get log path from environment
 	logPath, ok := os.LookupEnv("LOG_PATH")
 	if !ok {
@@ -32,13 +131,13 @@ func InitLogger() error {
 	}
 	// Use lumberjack instead of default logger for automated log rotation
 	// Open the log files - error handling and flags are handled by the lumberjack package
-	accessLogFile := &lumberjack.Logger{
+	accessLogFile = &lumberjack.Logger{
 		Filename:   filepath.Join(logPath, "access.log"),
 		MaxSize:    1,
 		MaxBackups: 3,
 		MaxAge:     28,
 	}
-	errorLogFile := &lumberjack.Logger{
+	errorLogFile = &lumberjack.Logger{
 		Filename:   filepath.Join(logPath, "error.log"),
 		MaxSize:    1,
 		MaxBackups: 3,
@@ -52,6 +151,10 @@ func InitLogger() error {
 
 // CloseLogger closes the log files used by this package.
 func CloseLogger() error {
+	// Nothing to close if stdout/stderr are used
+	if logToStdout {
+		return nil
+	}
 	if accessLogFile == nil || errorLogFile == nil {
 		return errors.New("no logging files to close")
 	}
@@ -67,33 +170,108 @@ func CloseLogger() error {
 }
 
 // LogResponseRecorder is a custom http.ResponseWriter recording status and body size
-// of a HTTP response for logging purposes.
+// of a HTTP response for logging purposes. Duration is the time needed to answer the
+// request and has to be set before logging. If Start is set, the time until the headers
+// are written is sent in the Server-Timing header.
 type LogResponseRecorder struct {
 	http.ResponseWriter
-	Status int
-	Size   int
+	Status      int
+	Size        int
+	Duration    time.Duration
+	Start       time.Time
+	wroteHeader bool
 }
 
 // WriteHeader - implementation of http.ResponseWriter interface storing the status code.
 func (l *LogResponseRecorder) WriteHeader(status int) {
 	l.Status = status
+	l.setServerTiming()
 	l.ResponseWriter.WriteHeader(status)
 }
 
 // Write - implementation of http.ResponseWriter interface storing the body size.
 func (l *LogResponseRecorder) Write(b []byte) (int, error) {
-	l.Size = len(b)
+	// The headers are written implicitly with the first write
+	l.setServerTiming()
+	l.Size += len(b)
 	return l.ResponseWriter.Write(b)
 }
 
+// setServerTiming adds the time since Start to the headers before they are written.
+func (l *LogResponseRecorder) setServerTiming() {
+	if l.wroteHeader {
+		return
+	}
+	l.wroteHeader = true
+	if !l.Start.IsZero() {
+		l.Header().Set("Server-Timing", fmt.Sprintf("app;dur=%.3f", durationMS(time.Since(l.Start))))
+	}
+}
+
+// durationMS returns the duration in milliseconds.
+func durationMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Flush - implementation of http.Flusher interface for streamed responses.
+func (l *LogResponseRecorder) Flush() {
+	if flusher, ok := l.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// accessLogEntry is an entry of the access log in the JSON format.
+type accessLogEntry struct {
+	Timestamp  string  `json:"timestamp"`
+	RemoteAddr string  `json:"remote_addr"`
+	Method     string  `json:"method"`
+	Path       string  `json:"path"`
+	Query      string  `json:"query,omitempty"`
+	Protocol   string  `json:"protocol"`
+	Status     int     `json:"status"`
+	Size       int     `json:"size"`
+	DurationMS float64 `json:"duration_ms"`
+	UserAgent  string  `json:"user_agent"`
+	RequestID  string  `json:"request_id,omitempty"`
+}
+
+// AccessLogExcluded checks if requests of the path are excluded from the access log with LOG_EXCLUDE_PATHS.
+func AccessLogExcluded(path string) bool {
+	return excludedPaths[path]
+}
+
 // LogRequest logs a HTTP request and the data of the ResponseRecorder to the accessLogger.
 func LogRequest(request *http.Request, response LogResponseRecorder) error {
 	if accessLogger == nil {
 		return errors.New("access logger not initialized")
 	}
-	// Logging in "Combined Log Format" without referrer
 	t := time.Now()
-	accessLogger.Printf("%s - - [%s] \"%s %s %s\" %v %v \"%s\"\n",
+	if logJSON {
+		entry, err := json.Marshal(accessLogEntry{
+			Timestamp:  t.Format(time.RFC3339Nano),
+			RemoteAddr: request.RemoteAddr,
+			Method:     request.Method,
+			Path:       request.URL.Path,
+			Query:      request.URL.RawQuery,
+			Protocol:   request.Proto,
+			Status:     response.Status,
+			Size:       response.Size,
+			DurationMS: durationMS(response.Duration),
+			UserAgent:  request.UserAgent(),
+			RequestID:  RequestIDFromContext(request.Context()),
+		})
+		if err != nil {
+			return err
+		}
+		accessLogger.Println(string(entry))
+		return nil
+	}
+	// Logging in "Combined Log Format" without referrer, followed by the duration in milliseconds and the request ID
+	requestID := RequestIDFromContext(request.Context())
+	if requestID == "" {
+		requestID = "-"
+	}
+	accessLogger.Printf("%s - - [%s] \"%s %s %s\" %v %v \"%s\" %.3fms %s\n",
 		request.RemoteAddr,
 		t.Format("02/Jan/2006:15:04:05 -0700"),
 		request.Method,
@@ -102,6 +280,8 @@ func LogRequest(request *http.Request, response LogResponseRecorder) error {
 		response.Status,
 		response.Size,
 		request.UserAgent(),
+		durationMS(response.Duration),
+		requestID,
 	)
 	return nil
 }
@@ -130,16 +310,35 @@ func (c *CallerInformation) String() (string, error) {
 	return fmt.Sprintf("%s(%s:%v)", function, file, c.Line), nil
 }
 
-// LogError logs an error to the errorLogger.
+// LogError logs an unexpected error to the errorLogger.
 func LogError(err error, caller CallerInformation) error {
+	return logWithLevel(LevelError, "", err, caller)
+}
+
+// LogWarning logs a problem the request could recover from together with the CallerInformation to the error log.
+func LogWarning(err error, caller CallerInformation) error {
+	return logWithLevel(LevelWarning, "WARNING ", err, caller)
+}
+
+// LogInfo logs an expected event worth noting, e.g. a diagnostic finding, together with the CallerInformation to the error log.
+func LogInfo(err error, caller CallerInformation) error {
+	return logWithLevel(LevelInfo, "INFO ", err, caller)
+}
+
+// logWithLevel logs the error with the prefix and the CallerInformation to the errorLogger
+// if the level is not below the configured LOG_LEVEL.
+func logWithLevel(level Level, prefix string, err error, caller CallerInformation) error {
 	if errorLogger == nil {
 		return errors.New("error logger not initialized")
 	}
+	if level < logLevel {
+		return nil
+	}
 	// Log the caller information and the error
 	callerString, stringErr := caller.String()
 	if stringErr != nil {
 		return stringErr
 	}
-	errorLogger.Println(fmt.Sprintf("%s - %v", callerString, err.Error()))
+	errorLogger.Println(fmt.Sprintf("%s%s - %v", prefix, callerString, err.Error()))
 	return nil
 }
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// openFiles returns the paths of the files currently opened by the process.
+func openFiles(t *testing.T) []string {
+	t.Helper()
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skipf("open files can not be listed: %v", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		if target, err := os.Readlink(filepath.Join("/proc/self/fd", entry.Name())); err == nil {
+			files = append(files, target)
+		}
+	}
+	return files
+}
+
+func TestCloseLoggerClosesLogFiles(t *testing.T) {
+	logPath := t.TempDir()
+	t.Setenv("LOG_PATH", logPath)
+	t.Setenv("LOG_OUTPUT", "file")
+	t.Setenv("LOG_FORMAT", "combined")
+	t.Cleanup(func() {
+		accessLogger, errorLogger, accessLogFile, errorLogFile = nil, nil, nil, nil
+	})
+	if err := InitLogger(); err != nil {
+		t.Fatalf("InitLogger() failed: %v", err)
+	}
+	// Write to both logs so the files are opened
+	pc, file, line, _ := runtime.Caller(0)
+	if err := LogError(errors.New("test error"), CallerInformation{Pc: pc, File: file, Line: line}); err != nil {
+		t.Fatalf("LogError() failed: %v", err)
+	}
+	accessLogger.Print("test request")
+	logFiles := []string{filepath.Join(logPath, "access.log"), filepath.Join(logPath, "error.log")}
+	for _, logFile := range logFiles {
+		content, err := os.ReadFile(logFile)
+		if err != nil {
+			t.Fatalf("log file was not created: %v", err)
+		}
+		if !strings.Contains(string(content), "test") {
+			t.Errorf("log file %v does not contain the entry: %q", logFile, content)
+		}
+	}
+	if err := CloseLogger(); err != nil {
+		t.Fatalf("CloseLogger() failed: %v", err)
+	}
+	for _, openFile := range openFiles(t) {
+		for _, logFile := range logFiles {
+			if openFile == logFile {
+				t.Errorf("log file %v is still open after CloseLogger()", logFile)
+			}
+		}
+	}
+}
+
+func TestCloseLoggerWithoutInit(t *testing.T) {
+	if accessLogFile != nil || errorLogFile != nil {
+		t.Fatal("log files are already initialized")
+	}
+	if err := CloseLogger(); err == nil {
+		t.Error("CloseLogger() without InitLogger() returned no error")
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/janek64/pmd-dx-api/api/cache"
+	"github.com/janek64/pmd-dx-api/api/handler"
+	"github.com/janek64/pmd-dx-api/api/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+// DiffResponse sets an ETag for all JSON responses and stores their body in the redis cache.
+// If the request contains the "diff_from" argument with the ETag of a stored body, the
+// response only contains the differences to this body as a JSON merge patch (RFC 7396).
+// Falls back to the full response if the body for the ETag is not available.
+func DiffResponse(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		// Remove the argument from the request so it does not affect caching
+		queryParams := r.URL.Query()
+		diffFrom := strings.Trim(queryParams.Get("diff_from"), `"`)
+		queryParams.Del("diff_from")
+		r.URL.RawQuery = queryParams.Encode()
+		// Record the full response
+		responseRecorder := cache.NewCacheResponseRecorder()
+		seedRecorderHeaders(responseRecorder, w)
+		h(responseRecorder, r, ps)
+		if responseRecorder.Status != http.StatusOK || !strings.HasPrefix(responseRecorder.Header().Get("Content-Type"), "application/json") {
+			responseRecorder.WriteResponse(w)
+			return
+		}
+		// Store the body with its ETag so it can be used for later diffs
+		etag := fmt.Sprintf("%x", sha256.Sum256(responseRecorder.Json))
+		responseRecorder.Header().Set("ETag", fmt.Sprintf("%q", etag))
+		if err := cache.StoreBody(etag, responseRecorder.Json); err != nil {
+			logDiffError(err)
+		}
+		// Return the full response if no diff was requested or it is identical
+		if diffFrom == "" || diffFrom == etag {
+			responseRecorder.WriteResponse(w)
+			return
+		}
+		previous, err := cache.GetBody(diffFrom)
+		if err != nil {
+			if _, ok := err.(*cache.CacheMissError); !ok {
+				logDiffError(err)
+			}
+			responseRecorder.WriteResponse(w)
+			return
+		}
+		// Calculate the merge patch from the previous to the current body
+		var previousJSON, currentJSON interface{}
+		if json.Unmarshal(previous, &previousJSON) != nil || json.Unmarshal(responseRecorder.Json, &currentJSON) != nil {
+			responseRecorder.WriteResponse(w)
+			return
+		}
+		patch, err := json.Marshal(mergePatch(previousJSON, currentJSON))
+		if err != nil {
+			logDiffError(err)
+			responseRecorder.WriteResponse(w)
+			return
+		}
+		responseRecorder.Header().Set("Content-Type", "application/merge-patch+json; charset=utf-8")
+		responseRecorder.Header().Set(handler.ChecksumHeader, fmt.Sprintf("%x", sha256.Sum256(patch)))
+		responseRecorder.Json = patch
+		responseRecorder.WriteResponse(w)
+	}
+}
+
+// mergePatch returns the JSON merge patch (RFC 7396) transforming the previous into the current value.
+func mergePatch(previous interface{}, current interface{}) interface{} {
+	previousObject, previousOk := previous.(map[string]interface{})
+	currentObject, currentOk := current.(map[string]interface{})
+	// Values that are not objects on both sides are replaced completely
+	if !previousOk || !currentOk {
+		return current
+	}
+	patch := make(map[string]interface{})
+	// Removed keys are set to null
+	for k := range previousObject {
+		if _, ok := currentObject[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	// Changed keys are patched recursively
+	for k, v := range currentObject {
+		if p, ok := previousObject[k]; !ok || !reflect.DeepEqual(p, v) {
+			patch[k] = mergePatch(p, v)
+		}
+	}
+	return patch
+}
+
+// logDiffError logs an error of the DiffResponse middleware to the error log.
+func logDiffError(err error) {
+	pc, file, line, ok := runtime.Caller(1)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "DiffResponse: failed to fetch caller information")
+		return
+	}
+	caller := logger.CallerInformation{Pc: pc, File: file, Line: line}
+	logger.LogError(err, caller)
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// headWriter is a http.ResponseWriter that discards the body of the response and only counts its
+// size. The status code is held back until the handler finished, so the Content-Length of the
+// discarded body can be sent with the headers.
+type headWriter struct {
+	w      http.ResponseWriter
+	status int
+	size   int
+}
+
+// Header returns the header map of the underlying http.ResponseWriter.
+func (hw *headWriter) Header() http.Header {
+	return hw.w.Header()
+}
+
+// WriteHeader stores the status code until the response is finished.
+func (hw *headWriter) WriteHeader(status int) {
+	if hw.status == 0 {
+		hw.status = status
+	}
+}
+
+// Write discards the data and only adds its length to the size of the body.
+func (hw *headWriter) Write(data []byte) (int, error) {
+	if hw.status == 0 {
+		hw.status = http.StatusOK
+	}
+	hw.size += len(data)
+	return len(data), nil
+}
+
+// Flush - implementation of http.Flusher interface, nothing is sent before the handler finished.
+func (hw *headWriter) Flush() {}
+
+// finish sends the stored status code and the headers with the Content-Length of the discarded body.
+func (hw *headWriter) finish() {
+	if hw.status == 0 {
+		hw.status = http.StatusOK
+	}
+	// Responses without a body must not declare a length
+	if hw.status != http.StatusNoContent && hw.status != http.StatusNotModified && hw.w.Header().Get("Content-Length") == "" {
+		hw.w.Header().Set("Content-Length", strconv.Itoa(hw.size))
+	}
+	hw.w.WriteHeader(hw.status)
+}
+
+// HeadRequest answers HEAD requests with the handler of the GET route, running the same logic
+// but discarding the body. All headers, e.g. ETag and Link, are sent as for GET requests together
+// with the Content-Length of the body. Must not be used for long-lived streams.
+func HeadRequest(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		hw := &headWriter{w: w}
+		h(hw, r, ps)
+		hw.finish()
+	}
+}
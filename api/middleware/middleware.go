@@ -4,22 +4,171 @@ package middleware
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"net/url"
 	"os"
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/janek64/pmd-dx-api/api/cache"
 	"github.com/janek64/pmd-dx-api/api/db"
+	"github.com/janek64/pmd-dx-api/api/debug"
 	"github.com/janek64/pmd-dx-api/api/handler"
 	"github.com/janek64/pmd-dx-api/api/logger"
 	"github.com/julienschmidt/httprouter"
+	"golang.org/x/sync/singleflight"
 )
 
+// cacheMissGroup coalesces concurrent requests for the same uncached response.
+var cacheMissGroup singleflight.Group
+
+// adminToken is the token authorizing clients for internal features, which are disabled if it is empty.
+var adminToken string
+
+// strictParams is true if present pagination parameters must be positive integers instead of
+// zero values being replaced with the default.
+var strictParams bool
+
+// maxPerPage is the maximum number of resources per page of resource lists, larger page sizes are reduced to it.
+var maxPerPage = 200
+
+// trustProxyHeaders is true if the client address forwarded by a reverse proxy in X-Forwarded-For identifies the clients.
+var trustProxyHeaders bool
+
+// allowedOrigins contains the origins allowed to access the API from browsers, "*" allows all origins.
+var allowedOrigins = []string{"*"}
+
+// InitMiddleware reads the configuration of the middleware from the environment.
+// The optional ADMIN_TOKEN enables internal features for clients sending it as a bearer token.
+// The optional ALLOWED_ORIGINS restricts the origins allowed by CORS to a comma-separated list.
+// The optional RATE_LIMIT_RPS and RATE_LIMIT_BURST enable rate limiting.
+// The optional STRICT_PARAMS rejects pagination parameters that are zero or empty, invalid values are ignored.
+// The optional MAX_PER_PAGE changes the maximum page size of resource lists, invalid values are ignored.
+// The optional TRUST_PROXY_HEADERS identifies the clients by their forwarded address, invalid values are ignored.
+func InitMiddleware() {
+	adminToken, _ = os.LookupEnv("ADMIN_TOKEN")
+	trustProxyHeaders, _ = strconv.ParseBool(os.Getenv("TRUST_PROXY_HEADERS"))
+	strictParams, _ = strconv.ParseBool(os.Getenv("STRICT_PARAMS"))
+	if value, ok := os.LookupEnv("MAX_PER_PAGE"); ok {
+		if max, err := strconv.Atoi(value); err == nil && max > 0 {
+			maxPerPage = max
+		}
+	}
+	initRateLimit()
+	if value, ok := os.LookupEnv("ALLOWED_ORIGINS"); ok && strings.TrimSpace(value) != "" {
+		allowedOrigins = nil
+		for _, origin := range strings.Split(value, ",") {
+			allowedOrigins = append(allowedOrigins, strings.TrimSpace(origin))
+		}
+	}
+}
+
+// hasAdminToken checks if the request contains the admin token in its Authorization header.
+func hasAdminToken(r *http.Request) bool {
+	if adminToken == "" {
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1
+}
+
+// RequireAdminToken only calls the handler for requests containing the admin token
+// and answers all other requests with code 403 (Forbidden).
+func RequireAdminToken(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if !hasAdminToken(r) {
+			handler.AnswerWithErrors(w, http.StatusForbidden, []handler.ValidationError{{Parameter: "Authorization", Reason: "a valid admin token is required"}})
+			return
+		}
+		h(w, r, ps)
+	}
+}
+
+// parseNonNegativeInt parses the query parameter as a non-negative integer. Returns the default value if the
+// parameter is missing or zero. Other invalid values are added to the errors and the default is returned.
+func parseNonNegativeInt(queryParams url.Values, name string, defaultValue int, errs *[]handler.ValidationError) int {
+	value := queryParams.Get(name)
+	if value == "" {
+		return defaultValue
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil || i < 0 {
+		*errs = append(*errs, handler.ValidationError{Parameter: name, Reason: fmt.Sprintf("invalid value '%v', expected a non-negative integer", value)})
+		return defaultValue
+	}
+	if i == 0 {
+		return defaultValue
+	}
+	return i
+}
+
+// parsePageInt parses a page number or page size like parseNonNegativeInt. If STRICT_PARAMS is enabled,
+// present values must be positive integers and zero or empty values are added to the errors as well.
+func parsePageInt(queryParams url.Values, name string, defaultValue int, errs *[]handler.ValidationError) int {
+	if !strictParams {
+		return parseNonNegativeInt(queryParams, name, defaultValue, errs)
+	}
+	if !queryParams.Has(name) {
+		return defaultValue
+	}
+	value := queryParams.Get(name)
+	i, err := strconv.Atoi(value)
+	if err != nil || i <= 0 {
+		*errs = append(*errs, handler.ValidationError{Parameter: name, Reason: fmt.Sprintf("invalid value '%v', expected a positive integer", value)})
+		return defaultValue
+	}
+	return i
+}
+
+// clampPerPage reduces the page size to maxPerPage if it is larger.
+func clampPerPage(perPage int) int {
+	if perPage > maxPerPage {
+		return maxPerPage
+	}
+	return perPage
+}
+
+// validatePageRange adds an error for the page parameter if the offset of the page can not be represented,
+// which would otherwise make the database query fail.
+func validatePageRange(pagination db.Pagination, pageName string, errs *[]handler.ValidationError) {
+	if pagination.Page-1 > math.MaxInt64/pagination.PerPage {
+		*errs = append(*errs, handler.ValidationError{Parameter: pageName, Reason: fmt.Sprintf("page %v is out of range for %v results per page", pagination.Page, pagination.PerPage)})
+	}
+}
+
+// parseOptionalInt parses the query parameter as a non-negative integer. Returns nil if the
+// parameter is missing or invalid, so the corresponding filter is ignored.
+func parseOptionalInt(queryParams url.Values, name string) *int {
+	i, err := strconv.Atoi(queryParams.Get(name))
+	if err != nil || i < 0 {
+		return nil
+	}
+	return &i
+}
+
+// parseSortTypes parses the comma-separated sort types of the "sort" argument, which are applied in their order.
+// Invalid ordering types are ignored instead of being answered with an error.
+func parseSortTypes(value string, sortParameter handler.QueryParameter) []db.SortType {
+	var sortTypes []db.SortType
+	for _, sort := range strings.Split(value, ",") {
+		sort = strings.TrimSpace(sort)
+		if sort != "" && sortParameter.Allows(sort) {
+			sortTypes = append(sortTypes, db.SortType(sort))
+		}
+	}
+	return sortTypes
+}
+
 // ResourceListParams checks for possible arguments of resource list queries, parses their
 // values and stores them in a struct which is added to the context of the request.
+// Invalid values are collected in the Errors of the struct, so all of them can be answered at once.
 func ResourceListParams(h httprouter.Handle) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		// Retrieve the parameters from the request
@@ -27,45 +176,274 @@ func ResourceListParams(h httprouter.Handle) httprouter.Handle {
 		// Generate the ResourceListParams struct and add it to the context
 		var params handler.ResourceListParams
 		// sorting
-		sort := queryParams.Get("sort")
-		// Check if the value is one of the sort types
-		if sort == db.IDAsc || sort == db.IDDesc || sort == db.NameAsc || sort == db.NameDesc {
-			params.Sort.SortEnabled = true
-			params.Sort.SortType = db.SortType(sort)
-		} else {
-			// Invalid ordering types are ignored instead of being answered with an error
-			params.Sort.SortEnabled = false
-		}
+		params.Sort.SortTypes = parseSortTypes(queryParams.Get("sort"), handler.SortParameter)
 		// pagination
-		var err error
-		// If page is zero, set to default value
-		if params.Pagination.PerPage, err = strconv.Atoi(queryParams.Get("per_page")); err != nil || params.Pagination.PerPage == 0 {
-			params.Pagination.PerPage = 50
+		// Missing or zero values are set to the default unless STRICT_PARAMS is enabled, other invalid values are collected as errors
+		// Larger page sizes than MAX_PER_PAGE are reduced to it instead of being answered with an error
+		params.Pagination.PerPage = clampPerPage(parsePageInt(queryParams, "per_page", 50, &params.Errors))
+		params.Pagination.Page = parsePageInt(queryParams, "page", 1, &params.Errors)
+		validatePageRange(params.Pagination, "page", &params.Errors)
+		// Accept offset and limit as an alternative if page and per_page are not provided
+		if !queryParams.Has("page") && !queryParams.Has("per_page") && (queryParams.Has("offset") || queryParams.Has("limit")) {
+			params.OffsetPagination = true
+			params.Pagination.PerPage = clampPerPage(parsePageInt(queryParams, "limit", 50, &params.Errors))
+			// The offset starts at the beginning by default
+			offset := parseNonNegativeInt(queryParams, "offset", 0, &params.Errors)
+			params.Pagination.Page = offset/params.Pagination.PerPage + 1
+		}
+		// filtering
+		if updatedSince := queryParams.Get("updated_since"); updatedSince != "" {
+			// Invalid timestamps are errors since ignoring them would return unfiltered results
+			var err error
+			if params.Filter.UpdatedSince, err = time.Parse(time.RFC3339, updatedSince); err != nil {
+				params.Errors = append(params.Errors, handler.ValidationError{Parameter: "updated_since", Reason: fmt.Sprintf("invalid value '%v', expected a RFC3339 timestamp", updatedSince)})
+			}
+		}
+		// filtering by the existence of pokemon, only applied to abilities, moves and types
+		if value := queryParams.Get("has_pokemon"); value != "" {
+			// Invalid values are errors since ignoring them would return unfiltered results
+			if hasPokemon, err := strconv.ParseBool(value); err == nil {
+				params.Filter.HasPokemon = &hasPokemon
+			} else {
+				params.Errors = append(params.Errors, handler.ValidationError{Parameter: "has_pokemon", Reason: fmt.Sprintf("invalid value '%v', expected a boolean", value)})
+			}
+		}
+		// filtering by type, only applied to pokemon
+		if pokemonType := queryParams.Get("type"); pokemonType != "" {
+			typeInput := handler.GenerateSearchInput(pokemonType)
+			params.Filter.Type = &typeInput
+		}
+		// filtering by evolution crystals, only applied to pokemon
+		// Invalid values are errors since ignoring them would return unfiltered results
+		for _, name := range []string{"evolve_crystals_gte", "evolve_crystals_lte"} {
+			value := queryParams.Get(name)
+			if value == "" {
+				continue
+			}
+			crystals, err := strconv.Atoi(value)
+			if err != nil || crystals < 0 {
+				params.Errors = append(params.Errors, handler.ValidationError{Parameter: name, Reason: fmt.Sprintf("invalid value '%v', expected a non-negative integer", value)})
+			} else if name == "evolve_crystals_gte" {
+				params.Filter.MinEvolveCrystals = &crystals
+			} else {
+				params.Filter.MaxEvolveCrystals = &crystals
+			}
 		}
-		// If per_page is zero, set to default value
-		if params.Pagination.Page, err = strconv.Atoi(queryParams.Get("page")); err != nil || params.Pagination.Page == 0 {
-			params.Pagination.Page = 1
+		// Invalid values are ignored and the full list is returned
+		params.CountOnly, _ = strconv.ParseBool(queryParams.Get("count_only"))
+		// Invalid values are ignored and the count is included
+		params.Pagination.SkipCount, _ = strconv.ParseBool(queryParams.Get("no_count"))
+		ctx := context.WithValue(r.Context(), handler.ResourceListParamsKey, params)
+		// Call the handler with the created context
+		h(w, r.WithContext(ctx), ps)
+	}
+}
+
+// MoveListParams parses the move specific parameters for category, learn type and stat range filtering
+// and sorting by power, accuracy and PP and adds them to the ResourceListParams of the request context.
+// Needs to be called after the ResourceListParams middleware.
+func MoveListParams(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		// Retrieve the parameters from the request
+		queryParams := r.URL.Query()
+		params := r.Context().Value(handler.ResourceListParamsKey).(handler.ResourceListParams)
+		// sorting by the move specific keys
+		params.Sort.SortTypes = parseSortTypes(queryParams.Get("sort"), handler.MoveSortParameter)
+		// filtering by category
+		if category := queryParams.Get("category"); category != "" {
+			// Invalid categories are errors since ignoring them would return unfiltered results
+			if handler.CategoryParameter.Allows(category) {
+				params.Filter.Category = db.MoveCategory(category)
+			} else {
+				params.Errors = append(params.Errors, handler.ValidationError{Parameter: "category", Reason: fmt.Sprintf("invalid value '%v', expected one of %v", category, strings.Join(handler.CategoryParameter.AllowedValues, ", "))})
+			}
 		}
+		// filtering by learn type, rejecting invalid values like invalid categories
+		if learnType := queryParams.Get("learn_type"); learnType != "" {
+			if handler.LearnTypeParameter.Allows(learnType) {
+				params.Filter.LearnType = db.LearnType(learnType)
+			} else {
+				params.Errors = append(params.Errors, handler.ValidationError{Parameter: "learn_type", Reason: fmt.Sprintf("invalid value '%v', expected one of %v", learnType, strings.Join(handler.LearnTypeParameter.AllowedValues, ", "))})
+			}
+		}
+		// filtering by stat ranges, invalid values are ignored like invalid sort types
+		params.Filter.MinPower = parseOptionalInt(queryParams, "min_power")
+		params.Filter.MaxPower = parseOptionalInt(queryParams, "max_power")
+		params.Filter.MinAccuracy = parseOptionalInt(queryParams, "min_accuracy")
+		params.Filter.MaxAccuracy = parseOptionalInt(queryParams, "max_accuracy")
 		ctx := context.WithValue(r.Context(), handler.ResourceListParamsKey, params)
+		// Call the handler with the modified context
+		h(w, r.WithContext(ctx), ps)
+	}
+}
+
+// DungeonListParams parses the dungeon specific parameter for start level filtering and adds it to the
+// ResourceListParams of the request context. Needs to be called after the ResourceListParams middleware.
+func DungeonListParams(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		// Retrieve the parameters from the request
+		queryParams := r.URL.Query()
+		params := r.Context().Value(handler.ResourceListParamsKey).(handler.ResourceListParams)
+		// filtering by start level, invalid values are errors since ignoring them would return unfiltered results
+		if value := queryParams.Get("max_start_level"); value != "" {
+			if level, err := strconv.Atoi(value); err == nil && level >= 0 {
+				params.Filter.MaxStartLevel = &level
+			} else {
+				params.Errors = append(params.Errors, handler.ValidationError{Parameter: "max_start_level", Reason: fmt.Sprintf("invalid value '%v', expected a non-negative integer", value)})
+			}
+		}
+		ctx := context.WithValue(r.Context(), handler.ResourceListParamsKey, params)
+		// Call the handler with the modified context
+		h(w, r.WithContext(ctx), ps)
+	}
+}
+
+// NestedListParams checks for the arguments sorting and paginating the list with the given name
+// that is nested in a single resource, e.g. "interactions_sort", "interactions_per_page" and
+// "interactions_page". sortParameter is nil for lists that can not be sorted. The parsed values
+// are stored in a struct which is added to the context of the request with the name of the list as
+// the key, so the middleware can be chained for multiple lists of the same resource.
+func NestedListParams(listName string, sortParameter *handler.QueryParameter, h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		// Retrieve the parameters from the request
+		queryParams := r.URL.Query()
+		// Generate the NestedListParams struct and add it to the context
+		var params handler.NestedListParams
+		// Invalid ordering types are ignored like for resource lists
+		if sort := queryParams.Get(listName + "_sort"); sort != "" && sortParameter != nil && sortParameter.Allows(sort) {
+			params.Sort = sort
+		}
+		// The full list is returned unless a page is requested
+		perPageName, pageName := listName+"_per_page", listName+"_page"
+		if queryParams.Has(perPageName) || queryParams.Has(pageName) {
+			params.Paginated = true
+			params.Pagination.PerPage = parsePageInt(queryParams, perPageName, 50, &params.Errors)
+			params.Pagination.Page = parsePageInt(queryParams, pageName, 1, &params.Errors)
+			validatePageRange(params.Pagination, pageName, &params.Errors)
+		}
+		// Keep the parameters of the other nested lists
+		allParams := map[string]handler.NestedListParams{listName: params}
+		if previous, ok := r.Context().Value(handler.NestedListParamsKey).(map[string]handler.NestedListParams); ok {
+			for name, p := range previous {
+				if name != listName {
+					allParams[name] = p
+				}
+			}
+		}
+		ctx := context.WithValue(r.Context(), handler.NestedListParamsKey, allParams)
 		// Call the handler with the created context
 		h(w, r.WithContext(ctx), ps)
 	}
 }
 
-// FieldLimitingParams checks for the "fields" argument of the query used for field limiting,
-// parses the value and stores it in a struct which is added to the context of the request.
+// parseFieldSelection parses the value of the "fields" argument, a comma-separated list of fields in which
+// each field can limit its nested objects with a list in brackets, e.g. "id,moves(name,level)", or with
+// a dotted path, e.g. "id,moves.name". Returns an error describing the problem if the value is malformed.
+func parseFieldSelection(value string) ([]handler.FieldSelection, error) {
+	fields, _, err := parseFieldList(value, 0)
+	return fields, err
+}
+
+// parseFieldList parses the comma-separated fields of the value until its end or, for nested lists with
+// a depth above 0, until the closing bracket. Returns the fields and the value after the closing bracket.
+// Empty fields are skipped like for the flat list, but a nested list must not be empty.
+func parseFieldList(value string, depth int) (fields []handler.FieldSelection, rest string, err error) {
+	for {
+		var name string
+		if i := strings.IndexAny(value, ",()"); i < 0 {
+			name, value = value, ""
+		} else {
+			name, value = value[:i], value[i:]
+		}
+		name = strings.TrimSpace(name)
+		var children []handler.FieldSelection
+		if strings.HasPrefix(value, "(") {
+			if name == "" {
+				return nil, "", errors.New("missing field name before '('")
+			}
+			children, value, err = parseFieldList(value[1:], depth+1)
+			if err != nil {
+				return nil, "", err
+			}
+			if len(children) == 0 {
+				return nil, "", fmt.Errorf("empty selection for field '%v'", name)
+			}
+			value = strings.TrimSpace(value)
+		}
+		if name != "" {
+			field, err := parseFieldPath(name, children)
+			if err != nil {
+				return nil, "", err
+			}
+			fields = append(fields, field)
+		}
+		switch {
+		case value == "":
+			if depth > 0 {
+				return nil, "", errors.New("missing ')'")
+			}
+			return fields, "", nil
+		case value[0] == ',':
+			value = value[1:]
+		case value[0] == ')':
+			if depth == 0 {
+				return nil, "", errors.New("unexpected ')'")
+			}
+			return fields, value[1:], nil
+		default:
+			return nil, "", fmt.Errorf("unexpected '%v' after the selection of field '%v'", string(value[0]), name)
+		}
+	}
+}
+
+// parseFieldPath converts a dotted field path like "moves.move.name" into nested selections,
+// with the children of a bracket selection added to the last field of the path.
+func parseFieldPath(path string, children []handler.FieldSelection) (handler.FieldSelection, error) {
+	names := strings.Split(path, ".")
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return handler.FieldSelection{}, fmt.Errorf("empty field name in '%v'", path)
+		}
+	}
+	field := handler.FieldSelection{Name: strings.TrimSpace(names[len(names)-1]), Children: children}
+	for i := len(names) - 2; i >= 0; i-- {
+		field = handler.FieldSelection{Name: strings.TrimSpace(names[i]), Children: []handler.FieldSelection{field}}
+	}
+	return field, nil
+}
+
+// FieldLimitingParams checks for the "fields" and "exclude" arguments of the query used for field limiting,
+// parses the values and stores them in a struct which is added to the context of the request.
+// If both are provided, "fields" takes precedence and "exclude" is ignored.
 func FieldLimitingParams(h httprouter.Handle) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		// Retrieve the parameters from the request
-		fields := strings.Split(r.URL.Query().Get("fields"), ",")
+		queryParams := r.URL.Query()
+		value := queryParams.Get("fields")
 		// Generate the FieldLimitingParams struct and add it to the context
 		var fieldLimitParams handler.FieldLimitingParams
 		// Check if at least one value was provided
-		if len(fields) > 0 && fields[0] != "" {
+		if value != "" {
+			fields, err := parseFieldSelection(value)
+			if err != nil {
+				handler.AnswerWithValidationErrors(w, []handler.ValidationError{{Parameter: "fields", Reason: err.Error()}})
+				return
+			}
 			fieldLimitParams.FieldLimitingEnabled = true
 			fieldLimitParams.Fields = fields
+			// Invalid values are ignored and unknown fields are silently omitted
+			fieldLimitParams.StrictFields, _ = strconv.ParseBool(queryParams.Get("strict_fields"))
 		} else {
 			fieldLimitParams.FieldLimitingEnabled = false
+			// The excluded fields use the same syntax as the selected fields
+			if exclude := queryParams.Get("exclude"); exclude != "" {
+				excludeFields, err := parseFieldSelection(exclude)
+				if err != nil {
+					handler.AnswerWithValidationErrors(w, []handler.ValidationError{{Parameter: "exclude", Reason: err.Error()}})
+					return
+				}
+				fieldLimitParams.ExcludeFields = excludeFields
+			}
 		}
 		ctx := context.WithValue(r.Context(), handler.FieldLimitingParamsKey, fieldLimitParams)
 		// Call the handler with the created context
@@ -73,11 +451,127 @@ func FieldLimitingParams(h httprouter.Handle) httprouter.Handle {
 	}
 }
 
+// FormatParams checks for arguments of the query that change the representation of the response,
+// parses their values and stores them in a struct which is added to the context of the request.
+func FormatParams(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		// Retrieve the parameters from the request
+		queryParams := r.URL.Query()
+		// Generate the FormatParams struct and add it to the context
+		var formatParams handler.FormatParams
+		// Invalid values are ignored and the default representation is used
+		formatParams.Flat, _ = strconv.ParseBool(queryParams.Get("flat"))
+		formatParams.SortKeys, _ = strconv.ParseBool(queryParams.Get("sort_keys"))
+		// Only the registered templates can be applied to responses
+		formatParams.Template = queryParams.Get("template")
+		if formatParams.Template != "" && !handler.TemplateRegistered(formatParams.Template) {
+			http.Error(w, fmt.Sprintf("response template '%v' is not registered", formatParams.Template), http.StatusBadRequest)
+			return
+		}
+		// The raw representation is a debug feature and the parameter is ignored in release builds
+		if debug.Enabled {
+			formatParams.Raw, _ = strconv.ParseBool(queryParams.Get("raw"))
+		}
+		// The raw representation is only available for clients with the admin token
+		if formatParams.Raw && !hasAdminToken(r) {
+			handler.AnswerWithErrors(w, http.StatusForbidden, []handler.ValidationError{{Parameter: "raw", Reason: "requires a valid admin token"}})
+			return
+		}
+		// The representation also depends on the Accept header, which is only negotiated once for the request
+		formatParams.Format = handler.NegotiateFormat(r)
+		cache.AddVary(w.Header(), "Accept")
+		ctx := context.WithValue(r.Context(), handler.FormatParamsKey, formatParams)
+		// Call the handler with the created context
+		h(w, r.WithContext(ctx), ps)
+	}
+}
+
+// setCORSHeaders sets the CORS headers allowing the origin of the request if it is one of the allowedOrigins.
+func setCORSHeaders(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	allowed := ""
+	for _, o := range allowedOrigins {
+		if o == "*" {
+			allowed = "*"
+			break
+		} else if o == origin {
+			allowed = origin
+			break
+		}
+	}
+	// The response depends on the origin if not all origins are allowed
+	if allowed != "*" {
+		cache.AddVary(w.Header(), "Origin")
+	}
+	if allowed == "" {
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", allowed)
+	w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, If-None-Match")
+	// Allow clients to read the pagination links, the ETag of diff responses, the data version, the checksum and the rate limit
+	w.Header().Set("Access-Control-Expose-Headers", "ETag, Link, X-Request-ID, X-Data-Version, X-Generated-At, X-Content-SHA256, X-RateLimit-Limit, X-RateLimit-Remaining, X-Cache")
+}
+
+// isPreflight checks if the request is a CORS preflight request.
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// CORS sets the CORS headers for allowed origins, configured with ALLOWED_ORIGINS. Preflight requests are
+// answered with code 204 (No Content) without calling the handler. The headers are set before the handler
+// is called, so they are also included in responses served from the cache.
+func CORS(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		setCORSHeaders(w, r)
+		if isPreflight(r) {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h(w, r, ps)
+	}
+}
+
+// PreflightHandler answers OPTIONS requests on routes without their own OPTIONS handler with code
+// 204 (No Content) and the CORS headers, so preflight requests never reach the handlers of the route.
+func PreflightHandler(w http.ResponseWriter, r *http.Request) {
+	responseRecorder := logger.LogResponseRecorder{ResponseWriter: w}
+	setCORSHeaders(&responseRecorder, r)
+	responseRecorder.WriteHeader(http.StatusNoContent)
+	if err := logger.LogRequest(r, responseRecorder); err != nil {
+		fmt.Fprintf(os.Stderr, "Writing to the access log failed: %v", err)
+	}
+}
+
+// dataVersionHeaders are the headers showing which data a response reflects.
+var dataVersionHeaders = []string{"X-Data-Version", "X-Generated-At"}
+
+// DataVersionHeaders sets the X-Data-Version header to the time of the latest update of the data and
+// X-Generated-At to the current time before the handler is called, so all responses contain them, including
+// errors. Cached responses keep the headers of the time they were generated, so they show which data a
+// cached response reflects.
+func DataVersionHeaders(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if version, ok := db.DataVersion(); ok {
+			w.Header().Set("X-Data-Version", version.UTC().Format(time.RFC3339))
+		}
+		w.Header().Set("X-Generated-At", time.Now().UTC().Format(time.RFC3339))
+		h(w, r, ps)
+	}
+}
+
 // LogRequest logs the request with the logger package by using a custom http.ResponseWriter.
+// The duration of the request is logged and the time until the headers are written is sent
+// in the Server-Timing header. Requests of the paths excluded with LOG_EXCLUDE_PATHS are not logged.
 func LogRequest(h httprouter.Handle) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-		responseRecorder := logger.LogResponseRecorder{ResponseWriter: w}
+		start := time.Now()
+		responseRecorder := logger.LogResponseRecorder{ResponseWriter: w, Start: start}
 		h(&responseRecorder, r, ps)
+		responseRecorder.Duration = time.Since(start)
+		if logger.AccessLogExcluded(r.URL.Path) {
+			return
+		}
 		err := logger.LogRequest(r, responseRecorder)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Writing to the access log failed: %v", err)
@@ -85,19 +579,52 @@ func LogRequest(h httprouter.Handle) httprouter.Handle {
 	}
 }
 
+// cacheStatusHeader is the header showing if the response was served from the cache ("HIT") or generated ("MISS").
+const cacheStatusHeader = "X-Cache"
+
 // CacheResponse tries to fetch the response for the requested URL from
 // the redis instance and returns it if it exists. If there is no cache entry,
 // it will record the json and headers of the generated response and store
-// them in the redis cache if the status code is 200.
-func CacheResponse(h httprouter.Handle) httprouter.Handle {
+// them in the redis cache if the status code is 200. Concurrent requests for
+// the same uncached URL share a single call of the handler, which is not canceled
+// if the client that started it disconnects. Server errors of the shared call are
+// not shared, the other requests call the handler on their own. Requests with an
+// Authorization header bypass the cache since their responses may be individual.
+// Requests with "Cache-Control: no-cache" or "nocache=true" skip the lookup, but their
+// generated response is still stored for the following requests. The X-Cache header shows
+// if a response was served from the cache.
+// The stored responses expire after the TTL, a TTL of 0 stores them permanently.
+func CacheResponse(ttl time.Duration, h httprouter.Handle) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-		// Try to get the response from the redis cache
-		header, json, err := cache.GetCachedResponse(r.URL.String())
+		if r.Header.Get("Authorization") != "" {
+			w.Header().Set(cacheStatusHeader, "MISS")
+			h(w, r, ps)
+			return
+		}
+		bypass := noCacheRequested(r)
+		if r.URL.Query().Get("nocache") != "" {
+			// Remove the parameter so it is neither part of the key nor of the stored response, e.g. in its Link header
+			r = withoutQueryParam(r, "nocache")
+		}
+		key := cacheKey(r)
+		// Try to get the response from the redis cache unless it is bypassed
+		header, json, etag, err := cache.GetCachedResponse(key)
+		if bypass {
+			err = &cache.CacheMissError{MissingKey: key}
+		}
 		// If no error was provided, respond with the cache result
 		if err == nil {
-			for k, v := range header {
-				w.Header().Set(k, v[0])
+			// Answer conditional requests for an unchanged response without the body
+			if etag != "" && etagMatches(r.Header.Get("If-None-Match"), etag) {
+				w.Header().Set(cacheStatusHeader, "HIT")
+				writeNotModified(w, header)
+				return
 			}
+			// The individual headers of the request must not be replaced by the ones of the cached response
+			stripIndividualHeaders(header)
+			cache.CopyHeader(w.Header(), header)
+			// Set after restoring the cached headers so it can not be overwritten by them
+			w.Header().Set(cacheStatusHeader, "HIT")
 			w.WriteHeader(http.StatusOK)
 			w.Write(json)
 			return
@@ -115,22 +642,180 @@ func CacheResponse(h httprouter.Handle) httprouter.Handle {
 				return
 			}
 		}
-		// Create a CacheResponseRecorder to record the json and status code
-		responseRecorder := cache.CacheResponseRecorder{ResponseWriter: w}
-		h(&responseRecorder, r, ps)
-		// Write the generated response into the redis cache if it is code 200
-		if responseRecorder.Status == 200 {
-			err = cache.StoreResponse(r.URL.String(), responseRecorder.Header(), responseRecorder.Json)
-			if err != nil {
-				// Log the error to the error log
-				pc, file, line, ok := runtime.Caller(0)
-				if !ok {
-					fmt.Fprintf(os.Stderr, "CacheResponse: failed to fetch caller information")
-					return
-				}
+		// Coalesce concurrent requests for the same URL so the handler is only called once
+		// and all requests share the recorded response
+		leader := false
+		result, _, _ := cacheMissGroup.Do(key, func() (interface{}, error) {
+			leader = true
+			// The call is shared, so it must not be canceled if the client of this request disconnects
+			ctx, cancel := sharedContext(r.Context())
+			defer cancel()
+			return generateResponse(w, r.WithContext(ctx), ps, h, key, ttl), nil
+		})
+		responseRecorder := result.(*cache.CacheResponseRecorder)
+		// Errors of the shared call, e.g. its timeout, are individual and answered by a call for this request instead
+		if !leader && !sharableStatus(responseRecorder.Status) {
+			responseRecorder = generateResponse(w, r, ps, h, key, ttl)
+		}
+		// Write the recorded response to the client, which was generated for this or a concurrent request
+		w.Header().Set(cacheStatusHeader, "MISS")
+		if etag := responseRecorder.Header().Get("ETag"); etag != "" && etagMatches(r.Header.Get("If-None-Match"), etag) {
+			writeNotModified(w, responseRecorder.Header())
+			return
+		}
+		responseRecorder.WriteResponse(w)
+	}
+}
+
+// generateResponse calls the handler with a recorder and stores the recorded response
+// in the redis cache with the key if its status code is 200.
+func generateResponse(w http.ResponseWriter, r *http.Request, ps httprouter.Params, h httprouter.Handle, key string, ttl time.Duration) *cache.CacheResponseRecorder {
+	// Create a CacheResponseRecorder to record the header, json and status code
+	responseRecorder := cache.NewCacheResponseRecorder()
+	seedRecorderHeaders(responseRecorder, w)
+	h(responseRecorder, r, ps)
+	// The response is shared with concurrent requests and stored, so it must not contain individual headers
+	stripIndividualHeaders(responseRecorder.Header())
+	// Write the generated response into the redis cache if it is code 200
+	if responseRecorder.Status == 200 {
+		// Identify the response by the hash of its body for conditional requests,
+		// reusing the checksum of the body if the handler already calculated it
+		checksum := responseRecorder.Header().Get(handler.ChecksumHeader)
+		if checksum == "" {
+			checksum = fmt.Sprintf("%x", sha256.Sum256(responseRecorder.Json))
+		}
+		etag := fmt.Sprintf("%q", checksum)
+		responseRecorder.Header().Set("ETag", etag)
+		err := cache.StoreResponse(key, responseRecorder.Header(), responseRecorder.Json, etag, ttl)
+		if err != nil {
+			// Log the error to the error log
+			pc, file, line, ok := runtime.Caller(0)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "CacheResponse: failed to fetch caller information")
+			} else {
 				caller := logger.CallerInformation{Pc: pc, File: file, Line: line}
 				logger.LogError(err, caller)
 			}
 		}
 	}
+	return responseRecorder
+}
+
+// sharableStatus checks if a response generated by a call shared by concurrent requests can be used
+// for all of them. Server errors, e.g. timeouts, and canceled requests (499) depend on the request
+// that made the call and contain its request ID, so they are not shared.
+func sharableStatus(status int) bool {
+	return status < http.StatusInternalServerError && status != 499
+}
+
+// detachedContext is a context with the values of its parent, e.g. the request ID,
+// which is neither canceled nor limited by a deadline of the parent.
+type detachedContext struct {
+	parent context.Context
+}
+
+// Deadline - implementation of the context.Context interface, a detachedContext has no deadline.
+func (d detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done - implementation of the context.Context interface, a detachedContext is never canceled.
+func (d detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+// Err - implementation of the context.Context interface, a detachedContext is never canceled.
+func (d detachedContext) Err() error {
+	return nil
+}
+
+// Value - implementation of the context.Context interface returning the values of the parent.
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
+
+// sharedContext returns the context for a handler call shared by concurrent requests. It is not canceled when
+// the client of the request disconnects, but it keeps the deadline of the request timeout.
+func sharedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detachedContext{parent: ctx}, deadline)
+	}
+	return context.WithCancel(detachedContext{parent: ctx})
+}
+
+// stripIndividualHeaders removes the headers that are individual for each request, the rate limit
+// state of the client and the request ID, from the header of a response.
+func stripIndividualHeaders(header http.Header) {
+	stripRateLimitHeaders(header)
+	header.Del(logger.RequestIDHeader)
+}
+
+// seedRecorderHeaders copies the request ID and the data version headers of the response to the header of
+// a recorder, so errors of the handler writing into the recorder can still reference the request ID and
+// the stored response keeps the data version it was generated with.
+func seedRecorderHeaders(recorder http.ResponseWriter, w http.ResponseWriter) {
+	for _, k := range append([]string{logger.RequestIDHeader}, dataVersionHeaders...) {
+		if v := w.Header().Get(k); v != "" {
+			recorder.Header().Set(k, v)
+		}
+	}
+}
+
+// noCacheRequested checks if the request asks for a fresh response with the "no-cache" directive
+// of the Cache-Control header or the query parameter "nocache". Invalid values of the parameter are ignored.
+func noCacheRequested(r *http.Request) bool {
+	for _, directive := range strings.Split(r.Header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+			return true
+		}
+	}
+	noCache, _ := strconv.ParseBool(r.URL.Query().Get("nocache"))
+	return noCache
+}
+
+// withoutQueryParam returns a shallow copy of the request whose URL does not contain the query parameter.
+func withoutQueryParam(r *http.Request, name string) *http.Request {
+	queryParams := r.URL.Query()
+	queryParams.Del(name)
+	clone := r.Clone(r.Context())
+	clone.URL.RawQuery = queryParams.Encode()
+	clone.RequestURI = clone.URL.RequestURI()
+	return clone
+}
+
+// cacheKey returns the URL identifying the response of the request in the cache. The responses contain
+// resource URLs with the base URL of the request, so the key starts with it and requests with another
+// scheme or (forwarded) host never share an entry. Requests negotiating a format other than JSON via the
+// Accept header use the same key as requests with the corresponding format parameter, e.g. "format=csv". The
+// query parameters are normalized, so their order does not create separate entries for the same response.
+func cacheKey(r *http.Request) string {
+	queryParams := r.URL.Query()
+	if format := handler.NegotiateFormat(r); queryParams.Get("format") == "" && format != handler.FormatJSON {
+		queryParams.Set("format", format)
+	}
+	keyURL := url.URL{Path: r.URL.Path, RawPath: r.URL.RawPath, RawQuery: queryParams.Encode()}
+	return handler.BaseURL(r) + cache.NormalizeURL(&keyURL)
+}
+
+// etagMatches checks if the If-None-Match header matches the ETag, ignoring weak validators.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeNotModified answers a conditional request with code 304 (Not Modified) and no body.
+// Only the headers describing the cached response are sent.
+func writeNotModified(w http.ResponseWriter, header http.Header) {
+	for _, k := range append([]string{"ETag", "Cache-Control"}, dataVersionHeaders...) {
+		if v := header.Get(k); v != "" {
+			w.Header().Set(k, v)
+		}
+	}
+	cache.AddVary(w.Header(), header.Values("Vary")...)
+	w.WriteHeader(http.StatusNotModified)
 }
@@ -0,0 +1,779 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/janek64/pmd-dx-api/api/cache"
+	"github.com/janek64/pmd-dx-api/api/db"
+	"github.com/janek64/pmd-dx-api/api/handler"
+	"github.com/janek64/pmd-dx-api/api/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+// startTestCache starts an in-memory redis instance for the cache and stops it after the test.
+func startTestCache(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	cache.SetClient(client)
+	t.Cleanup(func() {
+		cache.SetClient(nil)
+		client.Close()
+	})
+	return server
+}
+
+// serve calls the handler with the request and returns the recorded response.
+func serve(h httprouter.Handle, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h(w, r, nil)
+	return w
+}
+
+func TestCacheResponseSeparatesSchemes(t *testing.T) {
+	startTestCache(t)
+	calls := 0
+	h := CacheResponse(0, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(handler.BaseURL(r) + "/v1/pokemon/25"))
+	})
+	newRequest := func(overTLS bool) *http.Request {
+		r := httptest.NewRequest("GET", "/v1/pokemon/25", nil)
+		r.Host = "api.test"
+		r.TLS = nil
+		if overTLS {
+			r.TLS = &tls.ConnectionState{}
+		}
+		return r
+	}
+	tests := []struct {
+		name    string
+		overTLS bool
+		status  string
+		body    string
+	}{
+		{name: "http miss", overTLS: false, status: "MISS", body: "http://api.test/v1/pokemon/25"},
+		{name: "https miss", overTLS: true, status: "MISS", body: "https://api.test/v1/pokemon/25"},
+		{name: "http hit", overTLS: false, status: "HIT", body: "http://api.test/v1/pokemon/25"},
+		{name: "https hit", overTLS: true, status: "HIT", body: "https://api.test/v1/pokemon/25"},
+	}
+	for _, tt := range tests {
+		w := serve(h, newRequest(tt.overTLS))
+		if got := w.Header().Get(cacheStatusHeader); got != tt.status {
+			t.Errorf("%v: %v = %q, want %q", tt.name, cacheStatusHeader, got, tt.status)
+		}
+		if got := w.Body.String(); got != tt.body {
+			t.Errorf("%v: body = %q, want %q", tt.name, got, tt.body)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("handler called %v times, want 2", calls)
+	}
+}
+
+func TestCacheResponseSeparatesProtobuf(t *testing.T) {
+	startTestCache(t)
+	calls := 0
+	h := CacheResponse(0, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(strconv.FormatBool(handler.ProtobufRequested(r))))
+	})
+	tests := []struct {
+		name   string
+		target string
+		accept string
+		status string
+		body   string
+	}{
+		{name: "json miss", target: "/v1/pokemon/25", status: "MISS", body: "false"},
+		{name: "protobuf miss", target: "/v1/pokemon/25", accept: "application/x-protobuf", status: "MISS", body: "true"},
+		{name: "protobuf parameter hit", target: "/v1/pokemon/25?format=protobuf", status: "HIT", body: "true"},
+		{name: "json hit", target: "/v1/pokemon/25", accept: "application/json", status: "HIT", body: "false"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.target, nil)
+		r.Header.Set("Accept", tt.accept)
+		w := serve(h, r)
+		if got := w.Header().Get(cacheStatusHeader); got != tt.status {
+			t.Errorf("%v: %v = %q, want %q", tt.name, cacheStatusHeader, got, tt.status)
+		}
+		if got := w.Body.String(); got != tt.body {
+			t.Errorf("%v: body = %q, want %q", tt.name, got, tt.body)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("handler called %v times, want 2", calls)
+	}
+}
+
+// concurrentRequests sends the requests to the handler at the same time and returns their responses.
+// The first request is sent first, so it is the one calling the handler if the responses are coalesced.
+func concurrentRequests(h httprouter.Handle, requests []*http.Request, started <-chan struct{}) []*httptest.ResponseRecorder {
+	responses := make([]*httptest.ResponseRecorder, len(requests))
+	for i := range responses {
+		responses[i] = httptest.NewRecorder()
+		responses[i].Header().Set(logger.RequestIDHeader, fmt.Sprintf("request-%v", i))
+	}
+	var wg sync.WaitGroup
+	for i, r := range requests {
+		wg.Add(1)
+		go func(i int, r *http.Request) {
+			defer wg.Done()
+			h(responses[i], r, nil)
+		}(i, r)
+		if i == 0 {
+			<-started
+		}
+	}
+	wg.Wait()
+	return responses
+}
+
+func TestCacheResponseCoalescesMisses(t *testing.T) {
+	startTestCache(t)
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h := CacheResponse(0, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"name":"Pikachu"}`))
+	})
+	requests := make([]*http.Request, 20)
+	for i := range requests {
+		requests[i] = httptest.NewRequest("GET", "/v1/pokemon/25", nil)
+	}
+	// Keep the handler busy until the other requests are waiting for it
+	time.AfterFunc(100*time.Millisecond, func() { close(release) })
+	for i, w := range concurrentRequests(h, requests, started) {
+		if w.Code != http.StatusOK || w.Body.String() != `{"name":"Pikachu"}` {
+			t.Errorf("request %v: got %v %q, want 200 with the shared body", i, w.Code, w.Body.String())
+		}
+	}
+	if calls != 1 {
+		t.Errorf("handler called %v times, want 1", calls)
+	}
+}
+
+func TestCacheResponseSharedCallIgnoresCanceledLeader(t *testing.T) {
+	startTestCache(t)
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h := CacheResponse(0, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		if err := r.Context().Err(); err != nil {
+			handler.ErrorAndLog500(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"name":"Pikachu"}`))
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	requests := []*http.Request{
+		httptest.NewRequest("GET", "/v1/pokemon/25", nil).WithContext(ctx),
+		httptest.NewRequest("GET", "/v1/pokemon/25", nil),
+	}
+	// The client of the first request disconnects while the other request waits for the shared call
+	time.AfterFunc(100*time.Millisecond, func() {
+		cancel()
+		close(release)
+	})
+	responses := concurrentRequests(h, requests, started)
+	if w := responses[1]; w.Code != http.StatusOK || w.Body.String() != `{"name":"Pikachu"}` {
+		t.Errorf("waiting request: got %v %q, want 200 with the shared body", w.Code, w.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("handler called %v times, want 1", calls)
+	}
+}
+
+func TestCacheResponseDoesNotShareServerErrors(t *testing.T) {
+	for _, status := range []int{http.StatusInternalServerError, http.StatusServiceUnavailable, 499} {
+		t.Run(strconv.Itoa(status), func(t *testing.T) {
+			startTestCache(t)
+			var calls int32
+			started := make(chan struct{})
+			release := make(chan struct{})
+			h := CacheResponse(0, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					close(started)
+					<-release
+					http.Error(w, "failed for "+w.Header().Get(logger.RequestIDHeader), status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"name":"Pikachu"}`))
+			})
+			requests := []*http.Request{
+				httptest.NewRequest("GET", "/v1/pokemon/25", nil),
+				httptest.NewRequest("GET", "/v1/pokemon/25", nil),
+			}
+			time.AfterFunc(100*time.Millisecond, func() { close(release) })
+			responses := concurrentRequests(h, requests, started)
+			if w := responses[0]; w.Code != status || !strings.Contains(w.Body.String(), "request-0") {
+				t.Errorf("first request: got %v %q, want its own error %v", w.Code, w.Body.String(), status)
+			}
+			if w := responses[1]; w.Code != http.StatusOK || w.Body.String() != `{"name":"Pikachu"}` {
+				t.Errorf("waiting request: got %v %q, want 200 from its own call", w.Code, w.Body.String())
+			}
+			if calls != 2 {
+				t.Errorf("handler called %v times, want 2", calls)
+			}
+		})
+	}
+}
+
+func TestCacheResponseNoCacheBypass(t *testing.T) {
+	server := startTestCache(t)
+	calls := 0
+	h := CacheResponse(0, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		calls++
+		w.Header().Set("Link", "<"+r.URL.String()+">")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("response " + strconv.Itoa(calls)))
+	})
+	tests := []struct {
+		name    string
+		target  string
+		headers map[string]string
+		status  string
+		body    string
+	}{
+		{name: "first request", target: "/v1/pokemon?page=2", status: "MISS", body: "response 1"},
+		{name: "cached", target: "/v1/pokemon?page=2", status: "HIT", body: "response 1"},
+		{name: "no-cache header", target: "/v1/pokemon?page=2", headers: map[string]string{"Cache-Control": "max-age=0, No-Cache"}, status: "MISS", body: "response 2"},
+		{name: "stored by no-cache header", target: "/v1/pokemon?page=2", status: "HIT", body: "response 2"},
+		{name: "nocache parameter", target: "/v1/pokemon?nocache=true&page=2", status: "MISS", body: "response 3"},
+		{name: "stored by nocache parameter", target: "/v1/pokemon?page=2", status: "HIT", body: "response 3"},
+		{name: "nocache parameter disabled", target: "/v1/pokemon?page=2&nocache=false", status: "HIT", body: "response 3"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.target, nil)
+		for k, v := range tt.headers {
+			r.Header.Set(k, v)
+		}
+		w := serve(h, r)
+		if got := w.Header().Get(cacheStatusHeader); got != tt.status {
+			t.Errorf("%v: %v = %q, want %q", tt.name, cacheStatusHeader, got, tt.status)
+		}
+		if got := w.Body.String(); got != tt.body {
+			t.Errorf("%v: body = %q, want %q", tt.name, got, tt.body)
+		}
+		if link := w.Header().Get("Link"); strings.Contains(link, "nocache") {
+			t.Errorf("%v: the response contains the nocache parameter: %v", tt.name, link)
+		}
+	}
+	// All requests share the key without the nocache parameter
+	if keys := server.Keys(); len(keys) != 1 || strings.Contains(keys[0], "nocache") {
+		t.Errorf("stored keys = %v, want a single key without nocache", keys)
+	}
+}
+
+// validationErrors calls the handler with the request and returns the errors of the 400 response.
+func validationErrors(t *testing.T, h httprouter.Handle, target string) []handler.ValidationError {
+	t.Helper()
+	w := serve(h, httptest.NewRequest("GET", target, nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("%v: status = %v, want %v: %v", target, w.Code, http.StatusBadRequest, w.Body)
+	}
+	var body struct {
+		Errors []handler.ValidationError `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("%v: invalid error response: %v", target, err)
+	}
+	return body.Errors
+}
+
+func TestResourceListParamsCollectsAllErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		h      httprouter.Handle
+		target string
+		want   []handler.ValidationError
+	}{
+		{
+			name:   "pagination",
+			h:      ResourceListParams(handler.AbilityListHandler),
+			target: "/v1/abilities?page=-1&per_page=many",
+			want: []handler.ValidationError{
+				{Parameter: "per_page", Reason: "invalid value 'many', expected a non-negative integer"},
+				{Parameter: "page", Reason: "invalid value '-1', expected a non-negative integer"},
+			},
+		},
+		{
+			name:   "offset pagination",
+			h:      ResourceListParams(handler.AbilityListHandler),
+			target: "/v1/abilities?offset=-5&limit=x",
+			want: []handler.ValidationError{
+				{Parameter: "limit", Reason: "invalid value 'x', expected a non-negative integer"},
+				{Parameter: "offset", Reason: "invalid value '-5', expected a non-negative integer"},
+			},
+		},
+		{
+			name:   "filters",
+			h:      ResourceListParams(handler.PokemonListHandler),
+			target: "/v1/pokemon?updated_since=yesterday&has_pokemon=maybe&evolve_crystals_gte=-1&evolve_crystals_lte=lots",
+			want: []handler.ValidationError{
+				{Parameter: "updated_since", Reason: "invalid value 'yesterday', expected a RFC3339 timestamp"},
+				{Parameter: "has_pokemon", Reason: "invalid value 'maybe', expected a boolean"},
+				{Parameter: "evolve_crystals_gte", Reason: "invalid value '-1', expected a non-negative integer"},
+				{Parameter: "evolve_crystals_lte", Reason: "invalid value 'lots', expected a non-negative integer"},
+			},
+		},
+		{
+			name:   "move filters",
+			h:      ResourceListParams(MoveListParams(handler.MoveListHandler)),
+			target: "/v1/moves?page=abc&category=Magic&learn_type=egg",
+			want: []handler.ValidationError{
+				{Parameter: "page", Reason: "invalid value 'abc', expected a non-negative integer"},
+				{Parameter: "category", Reason: "invalid value 'Magic', expected one of Physical, Special, Status"},
+				{Parameter: "learn_type", Reason: "invalid value 'egg', expected one of level, tutor, tm"},
+			},
+		},
+		{
+			name:   "count",
+			h:      ResourceListParams(handler.CountHandler(db.AbilityTable)),
+			target: "/v1/abilities/count?per_page=-2&has_pokemon=2",
+			want: []handler.ValidationError{
+				{Parameter: "per_page", Reason: "invalid value '-2', expected a non-negative integer"},
+				{Parameter: "has_pokemon", Reason: "invalid value '2', expected a boolean"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validationErrors(t, tt.h, tt.target); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceListParamsPagination(t *testing.T) {
+	defer func(strict bool, max int) { strictParams, maxPerPage = strict, max }(strictParams, maxPerPage)
+	maxPerPage = 200
+	tests := []struct {
+		name       string
+		strict     bool
+		query      string
+		wantStatus int
+		wantErrors []string
+		want       db.Pagination
+	}{
+		{name: "defaults", query: "", wantStatus: http.StatusOK, want: db.Pagination{PerPage: 50, Page: 1}},
+		{name: "zero page", query: "page=0", wantStatus: http.StatusOK, want: db.Pagination{PerPage: 50, Page: 1}},
+		{name: "strict zero page", strict: true, query: "page=0", wantStatus: http.StatusBadRequest, wantErrors: []string{"page"}},
+		{name: "negative page", query: "page=-1", wantStatus: http.StatusBadRequest, wantErrors: []string{"page"}},
+		{name: "strict negative page", strict: true, query: "page=-1", wantStatus: http.StatusBadRequest, wantErrors: []string{"page"}},
+		{name: "malformed page size", query: "per_page=notanumber", wantStatus: http.StatusBadRequest, wantErrors: []string{"per_page"}},
+		{name: "strict malformed page size", strict: true, query: "per_page=notanumber", wantStatus: http.StatusBadRequest, wantErrors: []string{"per_page"}},
+		{name: "strict empty page size", strict: true, query: "per_page=", wantStatus: http.StatusBadRequest, wantErrors: []string{"per_page"}},
+		{name: "strict zero limit", strict: true, query: "limit=0", wantStatus: http.StatusBadRequest, wantErrors: []string{"limit"}},
+		{name: "large page size", query: "per_page=1000000", wantStatus: http.StatusOK, want: db.Pagination{PerPage: 200, Page: 1}},
+		{name: "strict large page size", strict: true, query: "per_page=1000000&page=3", wantStatus: http.StatusOK, want: db.Pagination{PerPage: 200, Page: 3}},
+		{name: "page size exceeding int", query: "per_page=99999999999999999999", wantStatus: http.StatusBadRequest, wantErrors: []string{"per_page"}},
+		{name: "page out of range", query: "per_page=200&page=9223372036854775807", wantStatus: http.StatusBadRequest, wantErrors: []string{"page"}},
+		{name: "all invalid", strict: true, query: "page=0&per_page=abc", wantStatus: http.StatusBadRequest, wantErrors: []string{"per_page", "page"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strictParams = tt.strict
+			var got db.Pagination
+			h := ResourceListParams(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				params := r.Context().Value(handler.ResourceListParamsKey).(handler.ResourceListParams)
+				if len(params.Errors) > 0 {
+					handler.AnswerWithValidationErrors(w, params.Errors)
+					return
+				}
+				got = params.Pagination
+				w.WriteHeader(http.StatusOK)
+			})
+			target := "/v1/pokemon?" + tt.query
+			if tt.wantStatus == http.StatusBadRequest {
+				var parameters []string
+				for _, validationError := range validationErrors(t, h, target) {
+					parameters = append(parameters, validationError.Parameter)
+				}
+				if !reflect.DeepEqual(parameters, tt.wantErrors) {
+					t.Errorf("errors for %v, want %v", parameters, tt.wantErrors)
+				}
+				return
+			}
+			if w := serve(h, httptest.NewRequest("GET", target, nil)); w.Code != tt.wantStatus {
+				t.Fatalf("status = %v, want %v: %v", w.Code, tt.wantStatus, w.Body)
+			}
+			if got != tt.want {
+				t.Errorf("pagination = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// field returns a FieldSelection of the name with the nested selections.
+func field(name string, children ...handler.FieldSelection) handler.FieldSelection {
+	return handler.FieldSelection{Name: name, Children: children}
+}
+
+func TestParseFieldSelection(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []handler.FieldSelection
+	}{
+		{value: "name", want: []handler.FieldSelection{field("name")}},
+		{value: "id, name,", want: []handler.FieldSelection{field("id"), field("name")}},
+		{value: "moves(move(name),level)", want: []handler.FieldSelection{field("moves", field("move", field("name")), field("level"))}},
+		{value: "moves.level", want: []handler.FieldSelection{field("moves", field("level"))}},
+		{value: "moves.move.name", want: []handler.FieldSelection{field("moves", field("move", field("name")))}},
+		{value: "name,moves.move.name,moves.level", want: []handler.FieldSelection{field("name"), field("moves", field("move", field("name"))), field("moves", field("level"))}},
+		{value: "moves.move(id,name)", want: []handler.FieldSelection{field("moves", field("move", field("id"), field("name")))}},
+		{value: "moves(move.name,level)", want: []handler.FieldSelection{field("moves", field("move", field("name")), field("level"))}},
+		{value: "results.url", want: []handler.FieldSelection{field("results", field("url"))}},
+		{value: " moves . level ", want: []handler.FieldSelection{field("moves", field("level"))}},
+	}
+	for _, tt := range tests {
+		got, err := parseFieldSelection(tt.value)
+		if err != nil {
+			t.Errorf("parseFieldSelection(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseFieldSelection(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseFieldSelectionErrors(t *testing.T) {
+	for _, value := range []string{
+		"moves..name",
+		".name",
+		"moves.",
+		"moves.(name)",
+		"moves(name",
+		"moves(name))",
+		"moves()",
+		"(name)",
+		"moves(name)level",
+		"moves(move..name)",
+	} {
+		if fields, err := parseFieldSelection(value); err == nil {
+			t.Errorf("parseFieldSelection(%q) = %+v, want an error", value, fields)
+		}
+	}
+}
+
+func TestFieldLimitingParamsExclude(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  handler.FieldLimitingParams
+	}{
+		{
+			name:  "exclude",
+			query: "exclude=moves,camp.id",
+			want:  handler.FieldLimitingParams{ExcludeFields: []handler.FieldSelection{field("moves"), field("camp", field("id"))}},
+		},
+		{
+			name:  "exclude nested list",
+			query: "exclude=moves(move(id),method)",
+			want:  handler.FieldLimitingParams{ExcludeFields: []handler.FieldSelection{field("moves", field("move", field("id")), field("method"))}},
+		},
+		{
+			name:  "fields take precedence",
+			query: "fields=name&exclude=name",
+			want:  handler.FieldLimitingParams{FieldLimitingEnabled: true, Fields: []handler.FieldSelection{field("name")}},
+		},
+		{
+			name:  "empty exclude",
+			query: "exclude=",
+			want:  handler.FieldLimitingParams{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got handler.FieldLimitingParams
+			h := FieldLimitingParams(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				got = r.Context().Value(handler.FieldLimitingParamsKey).(handler.FieldLimitingParams)
+			})
+			serve(h, httptest.NewRequest("GET", "/v1/pokemon/1?"+tt.query, nil))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("params = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldLimitingParamsMalformedExclude(t *testing.T) {
+	h := FieldLimitingParams(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		t.Error("the handler was called for a malformed exclude")
+	})
+	errs := validationErrors(t, h, "/v1/pokemon/1?exclude=moves..id")
+	if len(errs) != 1 || errs[0].Parameter != "exclude" {
+		t.Errorf("errors = %+v, want one error for exclude", errs)
+	}
+}
+
+func TestFormatParamsSortKeys(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{query: "", want: false},
+		{query: "sort_keys=true", want: true},
+		{query: "sort_keys=1", want: true},
+		{query: "sort_keys=false", want: false},
+		{query: "sort_keys=alphabetical", want: false},
+	}
+	for _, tt := range tests {
+		var got handler.FormatParams
+		h := FormatParams(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+			got = r.Context().Value(handler.FormatParamsKey).(handler.FormatParams)
+		})
+		serve(h, httptest.NewRequest("GET", "/v1/pokemon/1?"+tt.query, nil))
+		if got.SortKeys != tt.want {
+			t.Errorf("%q: SortKeys = %v, want %v", tt.query, got.SortKeys, tt.want)
+		}
+	}
+}
+
+func TestResourceListParamsOffsetPagination(t *testing.T) {
+	defer func(strict bool, max int) { strictParams, maxPerPage = strict, max }(strictParams, maxPerPage)
+	strictParams, maxPerPage = false, 200
+	tests := []struct {
+		name       string
+		query      string
+		want       db.Pagination
+		wantOffset bool
+	}{
+		{name: "offset and limit", query: "offset=60&limit=20", want: db.Pagination{PerPage: 20, Page: 4}, wantOffset: true},
+		{name: "offset inside a page", query: "offset=45&limit=20", want: db.Pagination{PerPage: 20, Page: 3}, wantOffset: true},
+		{name: "zero offset", query: "offset=0&limit=20", want: db.Pagination{PerPage: 20, Page: 1}, wantOffset: true},
+		{name: "offset only", query: "offset=120", want: db.Pagination{PerPage: 50, Page: 3}, wantOffset: true},
+		{name: "limit only", query: "limit=10", want: db.Pagination{PerPage: 10, Page: 1}, wantOffset: true},
+		{name: "limit above the maximum", query: "offset=400&limit=1000", want: db.Pagination{PerPage: 200, Page: 3}, wantOffset: true},
+		{name: "page takes precedence", query: "page=2&offset=60&limit=20", want: db.Pagination{PerPage: 50, Page: 2}},
+		{name: "per_page takes precedence", query: "per_page=10&offset=60", want: db.Pagination{PerPage: 10, Page: 1}},
+		{name: "page and per_page", query: "page=3&per_page=10", want: db.Pagination{PerPage: 10, Page: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got handler.ResourceListParams
+			h := ResourceListParams(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				got = r.Context().Value(handler.ResourceListParamsKey).(handler.ResourceListParams)
+			})
+			serve(h, httptest.NewRequest("GET", "/v1/pokemon?"+tt.query, nil))
+			if len(got.Errors) > 0 {
+				t.Fatalf("unexpected errors: %+v", got.Errors)
+			}
+			if got.Pagination != tt.want || got.OffsetPagination != tt.wantOffset {
+				t.Errorf("pagination = %+v with offset style %v, want %+v with offset style %v", got.Pagination, got.OffsetPagination, tt.want, tt.wantOffset)
+			}
+		})
+	}
+}
+
+// varyTokens returns the tokens of the Vary header of the response.
+func varyTokens(w *httptest.ResponseRecorder) []string {
+	var tokens []string
+	for _, value := range w.Header().Values("Vary") {
+		for _, token := range strings.Split(value, ",") {
+			tokens = append(tokens, strings.TrimSpace(token))
+		}
+	}
+	return tokens
+}
+
+func TestVaryKeepsOriginAndAccept(t *testing.T) {
+	startTestCache(t)
+	defer func(origins []string) { allowedOrigins = origins }(allowedOrigins)
+	allowedOrigins = []string{"https://app.test"}
+	negotiated := FormatParams(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"csv":` + strconv.FormatBool(handler.CSVRequested(r)) + `}`))
+	})
+	tests := []struct {
+		name   string
+		h      httprouter.Handle
+		status []string
+	}{
+		{name: "cached", h: CORS(CacheResponse(0, negotiated)), status: []string{"MISS", "HIT", "HIT"}},
+		{name: "diff", h: CORS(DiffResponse(negotiated)), status: []string{"", "", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, status := range tt.status {
+				r := httptest.NewRequest("GET", "/v1/pokemon/25?tt="+tt.name, nil)
+				r.Header.Set("Origin", "https://app.test")
+				// The last request is a conditional one answered with 304 by the cache
+				if i == 2 && status == "HIT" {
+					r.Header.Set("If-None-Match", "*")
+				}
+				w := serve(tt.h, r)
+				if got := w.Header().Get(cacheStatusHeader); got != status {
+					t.Errorf("request %v: %v = %q, want %q", i, cacheStatusHeader, got, status)
+				}
+				if got, want := varyTokens(w), []string{"Origin", "Accept"}; !reflect.DeepEqual(got, want) {
+					t.Errorf("request %v with status %v: Vary = %q, want %q", i, w.Code, got, want)
+				}
+				if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.test" {
+					t.Errorf("request %v: Access-Control-Allow-Origin = %q", i, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDataVersionHeaders(t *testing.T) {
+	startTestCache(t)
+	h := DataVersionHeaders(CacheResponse(0, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		if r.URL.Query().Get("page") == "0" {
+			handler.AnswerWithValidationErrors(w, []handler.ValidationError{{Parameter: "page", Reason: "must be positive"}})
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("generated"))
+	}))
+	generatedAt := func(t *testing.T, w *httptest.ResponseRecorder) time.Time {
+		t.Helper()
+		generatedAt, err := time.Parse(time.RFC3339, w.Header().Get("X-Generated-At"))
+		if err != nil {
+			t.Fatalf("invalid X-Generated-At: %v", err)
+		}
+		return generatedAt
+	}
+
+	t.Run("error response", func(t *testing.T) {
+		w := serve(h, httptest.NewRequest("GET", "/v1/pokemon?page=0", nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %v, want %v", w.Code, http.StatusBadRequest)
+		}
+		if since := time.Since(generatedAt(t, w)); since > time.Minute {
+			t.Errorf("X-Generated-At is %v old, want the current time", since)
+		}
+	})
+	t.Run("generated response is stored with the headers", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/v1/pokemon", nil)
+		w := serve(h, r)
+		header, _, _, err := cache.GetCachedResponse(cacheKey(r))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := header.Get("X-Generated-At"), w.Header().Get("X-Generated-At"); got != want {
+			t.Errorf("stored X-Generated-At = %q, want %q", got, want)
+		}
+	})
+	t.Run("cached response keeps the headers of the time it was generated", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/v1/abilities", nil)
+		stored := http.Header{"X-Generated-At": {"2022-03-02T07:30:00Z"}, "X-Data-Version": {"2022-03-01T12:00:00Z"}}
+		if err := cache.StoreResponse(cacheKey(r), stored, []byte("cached"), `"etag"`, 0); err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range []struct {
+			name        string
+			ifNoneMatch string
+			status      int
+		}{
+			{name: "full response", status: http.StatusOK},
+			{name: "not modified", ifNoneMatch: `"etag"`, status: http.StatusNotModified},
+		} {
+			r := httptest.NewRequest("GET", "/v1/abilities", nil)
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := serve(h, r)
+			if w.Code != tt.status {
+				t.Fatalf("%v: status = %v, want %v", tt.name, w.Code, tt.status)
+			}
+			for k := range stored {
+				if got := w.Header().Get(k); got != stored.Get(k) {
+					t.Errorf("%v: %v = %q, want %q", tt.name, k, got, stored.Get(k))
+				}
+			}
+		}
+	})
+}
+
+func TestCacheKeyNegotiatedFormat(t *testing.T) {
+	tests := []struct {
+		target string
+		accept string
+		want   string
+	}{
+		{target: "/v1/pokemon", accept: "application/json", want: "http://example.com/v1/pokemon"},
+		{target: "/v1/pokemon", accept: "text/csv;q=0.1, application/json", want: "http://example.com/v1/pokemon"},
+		{target: "/v1/pokemon", accept: "application/json;q=0.5, text/csv", want: "http://example.com/v1/pokemon?format=csv"},
+		{target: "/v1/pokemon?format=csv", want: "http://example.com/v1/pokemon?format=csv"},
+		{target: "/v1/pokemon?format=xml", accept: "text/csv", want: "http://example.com/v1/pokemon?format=xml"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.target, nil)
+		r.Header.Set("Accept", tt.accept)
+		if got := cacheKey(r); got != tt.want {
+			t.Errorf("cacheKey(%v with Accept %q) = %q, want %q", tt.target, tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestRequireAdminTokenAnswersWithJSON(t *testing.T) {
+	adminToken = "secret"
+	t.Cleanup(func() { adminToken = "" })
+	h := RequireAdminToken(func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusOK)
+	})
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{name: "missing token", status: http.StatusForbidden},
+		{name: "wrong token", authorization: "Bearer wrong", status: http.StatusForbidden},
+		{name: "admin token", authorization: "Bearer secret", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/admin/cache/stats", nil)
+		if tt.authorization != "" {
+			r.Header.Set("Authorization", tt.authorization)
+		}
+		w := serve(h, r)
+		if w.Code != tt.status {
+			t.Fatalf("%v: status = %v, want %v", tt.name, w.Code, tt.status)
+		}
+		if tt.status != http.StatusForbidden {
+			continue
+		}
+		if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+			t.Errorf("%v: Content-Type = %q, want JSON", tt.name, got)
+		}
+		var body struct {
+			Errors []handler.ValidationError `json:"errors"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || len(body.Errors) != 1 || body.Errors[0].Parameter != "Authorization" {
+			t.Errorf("%v: body = %v, want an error of the Authorization header", tt.name, w.Body)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/janek64/pmd-dx-api/api/cache"
+	"github.com/janek64/pmd-dx-api/api/handler"
+	"github.com/janek64/pmd-dx-api/api/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+// rateLimitRPS is the number of requests per second each client can make on average, 0 disables rate limiting.
+var rateLimitRPS float64
+
+// rateLimitBurst is the number of requests each client can make at once.
+var rateLimitBurst int
+
+// initRateLimit reads the rate limit configuration from the environment.
+// Invalid values are ignored and rate limiting stays disabled.
+func initRateLimit() {
+	if value, ok := os.LookupEnv("RATE_LIMIT_RPS"); ok {
+		if rps, err := strconv.ParseFloat(value, 64); err == nil && rps > 0 {
+			rateLimitRPS = rps
+		}
+	}
+	// The burst defaults to the requests of one second
+	rateLimitBurst = int(math.Ceil(rateLimitRPS))
+	if value, ok := os.LookupEnv("RATE_LIMIT_BURST"); ok {
+		if burst, err := strconv.Atoi(value); err == nil && burst > 0 {
+			rateLimitBurst = burst
+		}
+	}
+}
+
+// tokenBucket is a token bucket of a client that is kept in memory.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// maxLocalBuckets is the number of local token buckets after which full buckets are removed.
+const maxLocalBuckets = 10000
+
+// localBuckets contains the token buckets used if redis is not available, using the client as the key.
+var localBuckets = make(map[string]*tokenBucket)
+
+// localBucketsMu protects localBuckets.
+var localBucketsMu sync.Mutex
+
+// takeLocalToken takes a token from the local token bucket of the key like cache.TakeToken.
+func takeLocalToken(key string, rate float64, burst int) (allowed bool, remaining float64) {
+	localBucketsMu.Lock()
+	defer localBucketsMu.Unlock()
+	now := time.Now()
+	// Remove the buckets that are full again, they are equal to new buckets
+	if len(localBuckets) >= maxLocalBuckets {
+		for k, b := range localBuckets {
+			if b.tokens+now.Sub(b.last).Seconds()*rate >= float64(burst) {
+				delete(localBuckets, k)
+			}
+		}
+	}
+	bucket, ok := localBuckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(burst), last: now}
+		localBuckets[key] = bucket
+	}
+	bucket.tokens = math.Min(float64(burst), bucket.tokens+now.Sub(bucket.last).Seconds()*rate)
+	bucket.last = now
+	if bucket.tokens < 1 {
+		return false, bucket.tokens
+	}
+	bucket.tokens--
+	return true, bucket.tokens
+}
+
+// rateLimitHeaders are the headers describing the rate limit state of the client. They are individual
+// for each client, so they must never be part of a cached response.
+var rateLimitHeaders = []string{"X-RateLimit-Limit", "X-RateLimit-Remaining"}
+
+// setRateLimitHeaders sets the rate limit policy and the number of requests the client can still make at once.
+func setRateLimitHeaders(w http.ResponseWriter, remaining float64) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rateLimitBurst))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(int(math.Max(0, math.Floor(remaining)))))
+}
+
+// stripRateLimitHeaders removes the rate limit headers from the header of a response.
+func stripRateLimitHeaders(header http.Header) {
+	for _, k := range rateLimitHeaders {
+		header.Del(k)
+	}
+}
+
+// clientKey returns the key identifying the client of the request for rate limiting. Behind a trusted
+// proxy, the last address of X-Forwarded-For is used, which was appended by the proxy and can not be
+// spoofed by the client like the earlier ones.
+func clientKey(r *http.Request) string {
+	if trustProxyHeaders {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			addresses := strings.Split(forwarded[len(forwarded)-1], ",")
+			if address := strings.TrimSpace(addresses[len(addresses)-1]); address != "" {
+				return address
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// RateLimit limits the requests of each client with a token bucket, configured with RATE_LIMIT_RPS
+// and RATE_LIMIT_BURST. The buckets are stored in redis to share them between all instances and
+// in memory if redis is not available. Requests exceeding the limit are answered with code
+// 429 (Too Many Requests) and a Retry-After header. All responses include the X-RateLimit-Limit
+// and X-RateLimit-Remaining headers, so clients can throttle themselves before being limited.
+func RateLimit(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if rateLimitRPS <= 0 {
+			h(w, r, ps)
+			return
+		}
+		key := clientKey(r)
+		allowed, remaining, err := cache.TakeToken(key, rateLimitRPS, rateLimitBurst)
+		if err != nil {
+			logRateLimitError(err)
+			allowed, remaining = takeLocalToken(key, rateLimitRPS, rateLimitBurst)
+		}
+		// Set the headers before calling the handler, so responses served from the cache also show the state
+		// of this client. They are never stored with the cached responses, see stripIndividualHeaders.
+		setRateLimitHeaders(w, remaining)
+		if allowed {
+			h(w, r, ps)
+			return
+		}
+		// Wait until the next token is available
+		retryAfter := int(math.Ceil((1 - remaining) / rateLimitRPS))
+		responseJSON := orderedmap.New()
+		responseJSON.Set("error", "rate limit exceeded")
+		responseJSON.Set("retryAfter", retryAfter)
+		json, err := json.Marshal(responseJSON)
+		if err != nil {
+			handler.ErrorAndLog500(w, err)
+			return
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write(json)
+	}
+}
+
+// logRateLimitError logs the error of the redis token bucket to the error log.
+func logRateLimitError(err error) {
+	pc, file, line, ok := runtime.Caller(1)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "RateLimit: failed to fetch caller information")
+		return
+	}
+	caller := logger.CallerInformation{Pc: pc, File: file, Line: line}
+	logger.LogError(err, caller)
+}
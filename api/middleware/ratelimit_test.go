@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/janek64/pmd-dx-api/api/cache"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestRateLimitHeadersOfCachedResponses(t *testing.T) {
+	startTestCache(t)
+	rateLimitRPS, rateLimitBurst = 0.001, 5
+	t.Cleanup(func() { rateLimitRPS, rateLimitBurst = 0, 0 })
+	h := RateLimit(CacheResponse(0, func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("generated"))
+	}))
+	tests := []struct {
+		status    string
+		remaining string
+	}{
+		{status: "MISS", remaining: "4"},
+		{status: "HIT", remaining: "3"},
+		{status: "HIT", remaining: "2"},
+	}
+	for i, tt := range tests {
+		w := serve(h, httptest.NewRequest("GET", "/v1/pokemon", nil))
+		if got := w.Header().Get(cacheStatusHeader); got != tt.status {
+			t.Errorf("request %v: %v = %q, want %q", i+1, cacheStatusHeader, got, tt.status)
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != tt.remaining {
+			t.Errorf("request %v: X-RateLimit-Remaining = %q, want %q", i+1, got, tt.remaining)
+		}
+	}
+	// The state of the client is not stored with the response
+	header, _, _, err := cache.GetCachedResponse(cacheKey(httptest.NewRequest("GET", "/v1/pokemon", nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range rateLimitHeaders {
+		if v := header.Get(k); v != "" {
+			t.Errorf("the cached response contains %v: %q", k, v)
+		}
+	}
+}
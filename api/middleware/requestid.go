@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+
+	"github.com/janek64/pmd-dx-api/api/logger"
+)
+
+// maxRequestIDLength limits the length of request IDs accepted from clients.
+const maxRequestIDLength = 128
+
+// RequestID assigns an ID to each request, which is sent in the X-Request-ID header of the response,
+// added to the context of the request and included in the access log and the errors of the request.
+// The ID of an incoming X-Request-ID header is reused if it is valid, e.g. when set by a proxy,
+// otherwise a random UUID is generated. Wraps the whole router, so all routes receive an ID.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logger.RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		if id != "" {
+			w.Header().Set(logger.RequestIDHeader, id)
+		}
+		next.ServeHTTP(w, r.WithContext(logger.ContextWithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID checks if the ID is not empty, not too long and only contains visible ASCII
+// characters, so it can be written to the logs and headers without escaping.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID generates a random version 4 UUID.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// The random source of the OS does not fail in practice, an empty ID only affects the correlation
+		return ""
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/janek64/pmd-dx-api/api/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+// timeoutWriter is a http.ResponseWriter that stops forwarding the response of the handler
+// once the request timed out. The headers are kept separately until the response is started,
+// so the handler can not modify them while the timeout response is written. The context of the
+// handler is kept to reject its response as soon as the context is done.
+type timeoutWriter struct {
+	w           http.ResponseWriter
+	header      http.Header
+	ctx         context.Context
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+}
+
+// expired checks if the response of the handler must not be forwarded anymore. Besides the timeout
+// marked by Timeout, the context is checked, since the handler may notice the deadline first.
+func (tw *timeoutWriter) expired() bool {
+	return tw.timedOut || tw.ctx.Err() != nil
+}
+
+// Header returns the header map of the handler.
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+// WriteHeader copies the headers of the handler and sends them with the status code
+// if the request has not timed out yet.
+func (tw *timeoutWriter) WriteHeader(statusCode int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.writeHeaderLocked(statusCode)
+}
+
+// writeHeaderLocked is WriteHeader for callers already holding the lock.
+func (tw *timeoutWriter) writeHeaderLocked(statusCode int) {
+	if tw.expired() || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	for k, v := range tw.header {
+		tw.w.Header()[k] = v
+	}
+	tw.w.WriteHeader(statusCode)
+}
+
+// Write forwards the data to the client or returns http.ErrHandlerTimeout if the request timed out.
+func (tw *timeoutWriter) Write(data []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.expired() {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.writeHeaderLocked(http.StatusOK)
+	return tw.w.Write(data)
+}
+
+// Flush sends the buffered data to the client if the request has not timed out,
+// so streamed responses keep working.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if flusher, ok := tw.w.(http.Flusher); ok && !tw.expired() {
+		flusher.Flush()
+	}
+}
+
+// Timeout limits the duration of the request by adding a deadline to its context, which also
+// cancels the database queries of the handler. If the handler does not finish in time, the request
+// is answered with code 503 (Service Unavailable) and the timeout is logged. A response that
+// has already been started can not be replaced anymore and is only cut off.
+func Timeout(timeout time.Duration, h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		// The handler starts with the headers set before, e.g. the request ID
+		tw := &timeoutWriter{w: w, header: w.Header().Clone(), ctx: ctx}
+		done := make(chan struct{})
+		panicChan := make(chan interface{}, 1)
+		// Run the handler in its own goroutine so the request can be answered while it is still running
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicChan <- p
+				}
+			}()
+			h(tw, r.WithContext(ctx), ps)
+			close(done)
+		}()
+		select {
+		case p := <-panicChan:
+			// Pass the panic on to the goroutine of the server
+			panic(p)
+		case <-done:
+			return
+		case <-ctx.Done():
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			tw.timedOut = true
+			// The handler may have finished at the deadline, a response it started is complete
+			select {
+			case <-done:
+				if tw.wroteHeader {
+					return
+				}
+			default:
+			}
+			// Nothing needs to be answered if the client canceled the request
+			if ctx.Err() != context.DeadlineExceeded {
+				return
+			}
+			logTimeout(fmt.Errorf("request %v %v timed out after %v", r.Method, r.URL.String(), timeout))
+			if !tw.wroteHeader {
+				http.Error(w, fmt.Sprintf("the request could not be completed within %v", timeout), http.StatusServiceUnavailable)
+			}
+		}
+	}
+}
+
+// logTimeout logs the error of a timed out request to the error log as a warning,
+// since the request is answered with a proper error.
+func logTimeout(err error) {
+	pc, file, line, ok := runtime.Caller(1)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Timeout: failed to fetch caller information")
+		return
+	}
+	caller := logger.CallerInformation{Pc: pc, File: file, Line: line}
+	logger.LogWarning(err, caller)
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestTimeoutPassesFastResponses(t *testing.T) {
+	h := Timeout(time.Second, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.Header().Set("X-Test", "fast")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("done"))
+	})
+	w := serve(h, httptest.NewRequest("GET", "/v1/pokemon/1", nil))
+	if w.Code != http.StatusCreated || w.Body.String() != "done" || w.Header().Get("X-Test") != "fast" {
+		t.Errorf("response = %v %q with X-Test %q, want the response of the handler", w.Code, w.Body, w.Header().Get("X-Test"))
+	}
+}
+
+func TestTimeoutAnswersSlowHandlers(t *testing.T) {
+	// handlerErrors contains the error of the context and of the write of the handler
+	type handlerErrors struct{ ctxErr, writeErr error }
+	handlerErr := make(chan handlerErrors, 1)
+	h := Timeout(20*time.Millisecond, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		// A slow handler, e.g. waiting for a database query, is canceled by the deadline
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.Header().Set("X-Test", "late")
+		_, err := w.Write([]byte("late response"))
+		handlerErr <- handlerErrors{ctxErr: r.Context().Err(), writeErr: err}
+	})
+	start := time.Now()
+	w := serve(h, httptest.NewRequest("GET", "/v1/pokemon/1", nil))
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("the request was answered after %v", elapsed)
+	}
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %v, want %v", w.Code, http.StatusServiceUnavailable)
+	}
+	if !strings.Contains(w.Body.String(), "could not be completed") {
+		t.Errorf("body = %q, want the timeout message", w.Body)
+	}
+	select {
+	case errs := <-handlerErr:
+		if errs.ctxErr != context.DeadlineExceeded {
+			t.Errorf("context error of the handler = %v, want %v", errs.ctxErr, context.DeadlineExceeded)
+		}
+		if !errors.Is(errs.writeErr, http.ErrHandlerTimeout) {
+			t.Errorf("late write of the handler returned %v, want %v", errs.writeErr, http.ErrHandlerTimeout)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the handler did not finish")
+	}
+	if w.Header().Get("X-Test") != "" || strings.Contains(w.Body.String(), "late response") {
+		t.Errorf("the late response of the handler was written: %v %q", w.Header(), w.Body)
+	}
+}
+
+func TestTimeoutCutsOffStreamedResponses(t *testing.T) {
+	writeErr := make(chan error, 1)
+	h := Timeout(20*time.Millisecond, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		// The first part is sent before the timeout, so the response can not be replaced anymore
+		w.Write([]byte("part 1;"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+		_, err := w.Write([]byte("part 2;"))
+		writeErr <- err
+	})
+	w := serve(h, httptest.NewRequest("GET", "/v1/types/matrix", nil))
+	select {
+	case err := <-writeErr:
+		if !errors.Is(err, http.ErrHandlerTimeout) {
+			t.Errorf("write after the timeout returned %v, want %v", err, http.ErrHandlerTimeout)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the handler did not finish")
+	}
+	if w.Code != http.StatusOK || w.Body.String() != "part 1;" {
+		t.Errorf("response = %v %q, want the started response cut off after the first part", w.Code, w.Body)
+	}
+	if !w.Flushed {
+		t.Error("the first part was not flushed")
+	}
+}
+
+func TestTimeoutIgnoresCanceledRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan struct{})
+	h := Timeout(time.Second, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		defer close(finished)
+		<-r.Context().Done()
+		w.Write([]byte("late response"))
+	})
+	r := httptest.NewRequest("GET", "/v1/pokemon/1", nil).WithContext(ctx)
+	cancel()
+	w := serve(h, r)
+	<-finished
+	// The client is gone, so neither the timeout response nor the late response is written
+	if w.Body.Len() != 0 {
+		t.Errorf("body = %q, want no response for the canceled request", w.Body)
+	}
+}
@@ -104,6 +104,16 @@ type Pokemon struct {
 	CampID          int
 }
 
+// PokemonEntryID represents a pokemon with its camp and all its abilities, dungeons, moves and types with their IDs.
+type PokemonEntryID struct {
+	Pokemon   Pokemon
+	Camp      NamedResourceID
+	Abilities []NamedResourceID
+	Dungeons  []PokemonDungeonID
+	Moves     []PokemonMoveID
+	Types     []NamedResourceID
+}
+
 // PokemonType represents a pokemon_type entry from the database.
 type PokemonType struct {
 	TypeID   int
@@ -111,21 +121,37 @@ type PokemonType struct {
 }
 
 // NamedResourceID is a short representation of an API resource with its name and ID (for URL construction).
+// The ID of pokemon is always their dex number, the ID used in their URLs and as "id" of their details.
 type NamedResourceID struct {
 	Name string
 	ID   int
 }
 
-// ToNamedResourceURL returns the named resource with its URL instead of the ID.
+// ToNamedResourceURL returns the named resource with its URL. The ID is kept, so every reference
+// of a resource contains the same "id" as its details.
 func (n *NamedResourceID) ToNamedResourceURL(instanceURL string, resourceTypeName string) NamedResourceURL {
 	url := fmt.Sprintf("%v/v1/%v/%v", instanceURL, resourceTypeName, n.ID)
-	return NamedResourceURL{Name: n.Name, URL: url}
+	return NamedResourceURL{ID: n.ID, Name: n.Name, URL: url}
 }
 
-// NamedResourceURL is a short representation of an API resource with its name and URL.
+// NamedResourceURL is a short representation of an API resource with its ID, name and URL.
 type NamedResourceURL struct {
+	ID   int    `json:"id"`
 	Name string `json:"name"`
 	URL  string `json:"url"`
+	// Sprite is only set for pokemon if sprites are configured
+	Sprite string `json:"sprite,omitempty"`
+	// Types is only set for pokemon in lists requested with include=types
+	Types []NamedResourceURL `json:"types,omitempty"`
+}
+
+// SpriteURL returns the URL of the sprite for the pokemon with the dex number.
+// Returns an empty string if no spriteBaseURL is configured.
+func SpriteURL(spriteBaseURL string, dexNumber int) string {
+	if spriteBaseURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%v/%v.png", spriteBaseURL, dexNumber)
 }
 
 // DungeonPokemonID is a short representation of a pokemon appearing in a dungeon with its ID.
@@ -212,11 +238,191 @@ type TypeInteractionID struct {
 
 // ToTypeInteractionURL returns the TypeInteraction with its URL instead of the ID.
 func (t *TypeInteractionID) ToTypeInteractionURL(instanceURL string) TypeInteractionURL {
-	return TypeInteractionURL{Defender: t.Defender.ToNamedResourceURL(instanceURL, "types"), Interaction: t.Interaction}
+	return TypeInteractionURL{Defender: t.Defender.ToNamedResourceURL(instanceURL, "types"), Interaction: t.Interaction, Multiplier: InteractionMultiplier(t.Interaction)}
 }
 
 // TypeInteractionID represents an interaction of a type attacking another type with its URL.
 type TypeInteractionURL struct {
 	Defender    NamedResourceURL `json:"defender"`
 	Interaction string           `json:"interaction"`
+	Multiplier  float64          `json:"multiplier"`
+}
+
+// InteractionMultiplier returns the damage multiplier for an interaction of a type attacking another type.
+func InteractionMultiplier(interaction string) float64 {
+	switch interaction {
+	case "super effective":
+		return 2
+	case "not very effective":
+		return 0.5
+	case "not effective":
+		return 0
+	default:
+		return 1
+	}
+}
+
+// TypeDefenseID represents the damage multiplier a pokemon takes from an attacking type with its ID.
+type TypeDefenseID struct {
+	Attacker   NamedResourceID
+	Multiplier float64
+}
+
+// ToTypeDefenseURL returns the TypeDefense with its URL instead of the ID.
+func (t *TypeDefenseID) ToTypeDefenseURL(instanceURL string) TypeDefenseURL {
+	return TypeDefenseURL{Attacker: t.Attacker.ToNamedResourceURL(instanceURL, "types"), Multiplier: t.Multiplier}
+}
+
+// TypeDefenseURL represents the damage multiplier a pokemon takes from an attacking type with its URL.
+type TypeDefenseURL struct {
+	Attacker   NamedResourceURL `json:"attacker"`
+	Multiplier float64          `json:"multiplier"`
+}
+
+// TypeMatchupID represents the damage multiplier of an attack against a defending type with its ID.
+type TypeMatchupID struct {
+	Defender   NamedResourceID
+	Multiplier float64
+}
+
+// TypeMatchupURL represents the damage multiplier of an attack against a defending type with its URL.
+type TypeMatchupURL struct {
+	Defender   NamedResourceURL `json:"defender"`
+	Multiplier float64          `json:"multiplier"`
+}
+
+// TypeMatrixRowID represents the matchups of an attacking type against all types with IDs.
+type TypeMatrixRowID struct {
+	Attacker NamedResourceID
+	Matchups []TypeMatchupID
+}
+
+// ToTypeMatrixRowURL returns the TypeMatrixRow with URLs instead of IDs.
+func (t *TypeMatrixRowID) ToTypeMatrixRowURL(instanceURL string) TypeMatrixRowURL {
+	matchups := make([]TypeMatchupURL, 0, len(t.Matchups))
+	for _, m := range t.Matchups {
+		matchups = append(matchups, TypeMatchupURL{Defender: m.Defender.ToNamedResourceURL(instanceURL, "types"), Multiplier: m.Multiplier})
+	}
+	return TypeMatrixRowURL{Attacker: t.Attacker.ToNamedResourceURL(instanceURL, "types"), Matchups: matchups}
+}
+
+// TypeMatrixRowURL represents the matchups of an attacking type against all types with URLs.
+type TypeMatrixRowURL struct {
+	Attacker NamedResourceURL `json:"attacker"`
+	Matchups []TypeMatchupURL `json:"matchups"`
+}
+
+// EvolutionNodeID represents a pokemon in an evolution chain with the pokemon evolving from it with IDs.
+type EvolutionNodeID struct {
+	Pokemon         NamedResourceID
+	EvolveCondition string
+	EvolveLevel     NullInt64
+	EvolveCrystals  NullInt64
+	EvolvesTo       []EvolutionNodeID
+}
+
+// ToEvolutionNodeURL returns the EvolutionNode and all of its evolutions with URLs instead of IDs.
+func (e *EvolutionNodeID) ToEvolutionNodeURL(instanceURL string) EvolutionNodeURL {
+	evolvesTo := make([]EvolutionNodeURL, 0, len(e.EvolvesTo))
+	for _, n := range e.EvolvesTo {
+		evolvesTo = append(evolvesTo, n.ToEvolutionNodeURL(instanceURL))
+	}
+	return EvolutionNodeURL{Pokemon: e.Pokemon.ToNamedResourceURL(instanceURL, "pokemon"), EvolveCondition: e.EvolveCondition, EvolveLevel: e.EvolveLevel, EvolveCrystals: e.EvolveCrystals, EvolvesTo: evolvesTo}
+}
+
+// EvolutionNodeURL represents a pokemon in an evolution chain with the pokemon evolving from it with URLs.
+type EvolutionNodeURL struct {
+	Pokemon         NamedResourceURL   `json:"pokemon"`
+	EvolveCondition string             `json:"evolveCondition"`
+	EvolveLevel     NullInt64          `json:"evolveLevel"`
+	EvolveCrystals  NullInt64          `json:"evolveCrystals"`
+	EvolvesTo       []EvolutionNodeURL `json:"evolvesTo"`
+}
+
+// TypeCoverageID represents the best damage multiplier a set of attacking types achieves against a
+// defending type with IDs. Attackers contains all attacking types of the set achieving the multiplier.
+type TypeCoverageID struct {
+	Defender   NamedResourceID
+	Multiplier float64
+	Attackers  []NamedResourceID
+}
+
+// ToTypeCoverageURL returns the TypeCoverage with URLs instead of IDs.
+func (t *TypeCoverageID) ToTypeCoverageURL(instanceURL string) TypeCoverageURL {
+	attackers := make([]NamedResourceURL, 0, len(t.Attackers))
+	for _, a := range t.Attackers {
+		attackers = append(attackers, a.ToNamedResourceURL(instanceURL, "types"))
+	}
+	return TypeCoverageURL{Defender: t.Defender.ToNamedResourceURL(instanceURL, "types"), Multiplier: t.Multiplier, Attackers: attackers}
+}
+
+// TypeCoverageURL represents the best damage multiplier a set of attacking types achieves against a
+// defending type with URLs.
+type TypeCoverageURL struct {
+	Defender   NamedResourceURL   `json:"defender"`
+	Multiplier float64            `json:"multiplier"`
+	Attackers  []NamedResourceURL `json:"attackers"`
+}
+
+// LearnsetMoveID represents a move of a learnset with all pokemon of the evolution line learning it with IDs.
+type LearnsetMoveID struct {
+	Move      NamedResourceID
+	LearnedBy []LearnsetEntryID
+}
+
+// ToLearnsetMoveURL returns the LearnsetMove with URLs instead of IDs.
+func (l *LearnsetMoveID) ToLearnsetMoveURL(instanceURL string) LearnsetMoveURL {
+	learnedBy := make([]LearnsetEntryURL, 0, len(l.LearnedBy))
+	for _, e := range l.LearnedBy {
+		learnedBy = append(learnedBy, LearnsetEntryURL{Pokemon: e.Pokemon.ToNamedResourceURL(instanceURL, "pokemon"), EvolutionStage: e.EvolutionStage, Method: e.Method, Level: e.Level, Cost: e.Cost})
+	}
+	return LearnsetMoveURL{Move: l.Move.ToNamedResourceURL(instanceURL, "moves"), LearnedBy: learnedBy}
+}
+
+// LearnsetMoveURL represents a move of a learnset with all pokemon of the evolution line learning it with URLs.
+type LearnsetMoveURL struct {
+	Move      NamedResourceURL   `json:"move"`
+	LearnedBy []LearnsetEntryURL `json:"learnedBy"`
+}
+
+// LearnsetEntryID represents how a pokemon of an evolution line learns a move with its ID.
+type LearnsetEntryID struct {
+	Pokemon        NamedResourceID
+	EvolutionStage int
+	Method         string
+	Level          NullInt64
+	Cost           NullInt64
+}
+
+// LearnsetEntryURL represents how a pokemon of an evolution line learns a move with its URL.
+type LearnsetEntryURL struct {
+	Pokemon        NamedResourceURL `json:"pokemon"`
+	EvolutionStage int              `json:"evolutionStage"`
+	Method         string           `json:"method"`
+	Level          NullInt64        `json:"level"`
+	Cost           NullInt64        `json:"cost"`
+}
+
+// TypeMovesID represents a type with all moves of this type.
+type TypeMovesID struct {
+	Type  NamedResourceID
+	Moves []NamedResourceID
+}
+
+// SearchResultID represents the resources of one type matching a search term with their IDs.
+type SearchResultID struct {
+	ResourceType string
+	Resources    []NamedResourceID
+}
+
+// RelationshipCount represents the number of resources referencing a resource through a relationship.
+type RelationshipCount struct {
+	Name  string
+	Count int
+}
+
+// GroupCount represents the number of resources in a group.
+type GroupCount struct {
+	Group string
+	Count int
 }
@@ -0,0 +1,50 @@
+package models
+
+import "testing"
+
+func TestTransformsKeepIDs(t *testing.T) {
+	const instanceURL = "http://api.test"
+	pikachu := NamedResourceID{ID: 25, Name: "Pikachu"}
+	raichu := NamedResourceID{ID: 26, Name: "Raichu"}
+	thunderbolt := NamedResourceID{ID: 85, Name: "Thunderbolt"}
+	electric := NamedResourceID{ID: 4, Name: "Electric"}
+	ground := NamedResourceID{ID: 9, Name: "Ground"}
+	beachCave := NamedResourceID{ID: 1, Name: "Beach Cave"}
+
+	evolution := EvolutionNodeID{Pokemon: pikachu, EvolvesTo: []EvolutionNodeID{{Pokemon: raichu}}}
+	evolutionURL := evolution.ToEvolutionNodeURL(instanceURL)
+	learnset := LearnsetMoveID{Move: thunderbolt, LearnedBy: []LearnsetEntryID{{Pokemon: pikachu}}}
+	learnsetURL := learnset.ToLearnsetMoveURL(instanceURL)
+	matrixRow := TypeMatrixRowID{Attacker: electric, Matchups: []TypeMatchupID{{Defender: ground}}}
+	matrixRowURL := matrixRow.ToTypeMatrixRowURL(instanceURL)
+	coverage := TypeCoverageID{Defender: ground, Attackers: []NamedResourceID{electric}}
+	coverageURL := coverage.ToTypeCoverageURL(instanceURL)
+
+	tests := []struct {
+		name    string
+		got     NamedResourceURL
+		wantID  int
+		wantURL string
+	}{
+		{"named resource", pikachu.ToNamedResourceURL(instanceURL, "pokemon"), 25, "http://api.test/v1/pokemon/25"},
+		{"pokemon of a dungeon", (&DungeonPokemonID{Pokemon: pikachu}).ToDungeonPokemonURL(instanceURL).Pokemon, 25, "http://api.test/v1/pokemon/25"},
+		{"pokemon of a move", (&MovePokemonID{Pokemon: pikachu}).ToMovePokemonURL(instanceURL).Pokemon, 25, "http://api.test/v1/pokemon/25"},
+		{"pokemon of an evolution chain", evolutionURL.Pokemon, 25, "http://api.test/v1/pokemon/25"},
+		{"evolution of a pokemon", evolutionURL.EvolvesTo[0].Pokemon, 26, "http://api.test/v1/pokemon/26"},
+		{"pokemon of a learnset", learnsetURL.LearnedBy[0].Pokemon, 25, "http://api.test/v1/pokemon/25"},
+		{"move of a learnset", learnsetURL.Move, 85, "http://api.test/v1/moves/85"},
+		{"dungeon of a pokemon", (&PokemonDungeonID{Dungeon: beachCave}).ToPokemonDungeonURL(instanceURL).Dungeon, 1, "http://api.test/v1/dungeons/1"},
+		{"move of a pokemon", (&PokemonMoveID{Move: thunderbolt}).ToPokemonMoveURL(instanceURL).Move, 85, "http://api.test/v1/moves/85"},
+		{"defender of an interaction", (&TypeInteractionID{Defender: ground}).ToTypeInteractionURL(instanceURL).Defender, 9, "http://api.test/v1/types/9"},
+		{"attacker of a defense", (&TypeDefenseID{Attacker: electric}).ToTypeDefenseURL(instanceURL).Attacker, 4, "http://api.test/v1/types/4"},
+		{"attacker of a matrix row", matrixRowURL.Attacker, 4, "http://api.test/v1/types/4"},
+		{"defender of a matrix row", matrixRowURL.Matchups[0].Defender, 9, "http://api.test/v1/types/9"},
+		{"defender of a coverage", coverageURL.Defender, 9, "http://api.test/v1/types/9"},
+		{"attacker of a coverage", coverageURL.Attackers[0], 4, "http://api.test/v1/types/4"},
+	}
+	for _, tt := range tests {
+		if tt.got.ID != tt.wantID || tt.got.URL != tt.wantURL {
+			t.Errorf("%v: id = %v and url = %v, want %v and %v", tt.name, tt.got.ID, tt.got.URL, tt.wantID, tt.wantURL)
+		}
+	}
+}
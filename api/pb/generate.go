@@ -0,0 +1,5 @@
+// Package pb contains the Protocol Buffers messages of the API responses, generated from resources.proto
+// with protoc and protoc-gen-go v1.28.1.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative resources.proto
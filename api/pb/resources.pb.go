@@ -0,0 +1,3474 @@
+// Protocol Buffers messages of the responses of the API, returned for requests with
+// "format=protobuf" or "Accept: application/x-protobuf". The fields mirror the JSON
+// responses described in docs/api.md and use the same names in their JSON form.
+//
+// After changing this file, regenerate resources.pb.go with "go generate ./api/pb".
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        (unknown)
+// source: resources.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// NamedResource is the short representation of a resource used in lists and references.
+type NamedResource struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   int32  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Url  string `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	// Only set for pokemon if the instance provides sprites.
+	Sprite *string `protobuf:"bytes,4,opt,name=sprite,proto3,oneof" json:"sprite,omitempty"`
+	// Only set for pokemon in lists requested with "include=types".
+	Types []*NamedResource `protobuf:"bytes,5,rep,name=types,proto3" json:"types,omitempty"`
+}
+
+func (x *NamedResource) Reset() {
+	*x = NamedResource{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NamedResource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NamedResource) ProtoMessage() {}
+
+func (x *NamedResource) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NamedResource.ProtoReflect.Descriptor instead.
+func (*NamedResource) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *NamedResource) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NamedResource) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NamedResource) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *NamedResource) GetSprite() string {
+	if x != nil && x.Sprite != nil {
+		return *x.Sprite
+	}
+	return ""
+}
+
+func (x *NamedResource) GetTypes() []*NamedResource {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+// ResourceList is a page of a list of resources, e.g. '/v1/pokemon'.
+type ResourceList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Not set if counting was skipped with "no_count=true" or failed.
+	Count   *int32           `protobuf:"varint,1,opt,name=count,proto3,oneof" json:"count,omitempty"`
+	PerPage int32            `protobuf:"varint,2,opt,name=per_page,json=perPage,proto3" json:"per_page,omitempty"`
+	Results []*NamedResource `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *ResourceList) Reset() {
+	*x = ResourceList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResourceList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceList) ProtoMessage() {}
+
+func (x *ResourceList) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceList.ProtoReflect.Descriptor instead.
+func (*ResourceList) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ResourceList) GetCount() int32 {
+	if x != nil && x.Count != nil {
+		return *x.Count
+	}
+	return 0
+}
+
+func (x *ResourceList) GetPerPage() int32 {
+	if x != nil {
+		return x.PerPage
+	}
+	return 0
+}
+
+func (x *ResourceList) GetResults() []*NamedResource {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Count is the number of matching resources, e.g. of '/v1/pokemon/count' or "count_only=true".
+type Count struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count int32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *Count) Reset() {
+	*x = Count{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Count) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Count) ProtoMessage() {}
+
+func (x *Count) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Count.ProtoReflect.Descriptor instead.
+func (*Count) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Count) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// SearchResults are the resources of all types matching '/v1/search'.
+type SearchResults struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pokemon   []*NamedResource `protobuf:"bytes,1,rep,name=pokemon,proto3" json:"pokemon,omitempty"`
+	Moves     []*NamedResource `protobuf:"bytes,2,rep,name=moves,proto3" json:"moves,omitempty"`
+	Abilities []*NamedResource `protobuf:"bytes,3,rep,name=abilities,proto3" json:"abilities,omitempty"`
+	Dungeons  []*NamedResource `protobuf:"bytes,4,rep,name=dungeons,proto3" json:"dungeons,omitempty"`
+	Camps     []*NamedResource `protobuf:"bytes,5,rep,name=camps,proto3" json:"camps,omitempty"`
+	Types     []*NamedResource `protobuf:"bytes,6,rep,name=types,proto3" json:"types,omitempty"`
+}
+
+func (x *SearchResults) Reset() {
+	*x = SearchResults{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchResults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResults) ProtoMessage() {}
+
+func (x *SearchResults) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResults.ProtoReflect.Descriptor instead.
+func (*SearchResults) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SearchResults) GetPokemon() []*NamedResource {
+	if x != nil {
+		return x.Pokemon
+	}
+	return nil
+}
+
+func (x *SearchResults) GetMoves() []*NamedResource {
+	if x != nil {
+		return x.Moves
+	}
+	return nil
+}
+
+func (x *SearchResults) GetAbilities() []*NamedResource {
+	if x != nil {
+		return x.Abilities
+	}
+	return nil
+}
+
+func (x *SearchResults) GetDungeons() []*NamedResource {
+	if x != nil {
+		return x.Dungeons
+	}
+	return nil
+}
+
+func (x *SearchResults) GetCamps() []*NamedResource {
+	if x != nil {
+		return x.Camps
+	}
+	return nil
+}
+
+func (x *SearchResults) GetTypes() []*NamedResource {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+type Ability struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          int32            `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string           `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string           `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Pokemon     []*NamedResource `protobuf:"bytes,4,rep,name=pokemon,proto3" json:"pokemon,omitempty"`
+	// Only set with "include=counts".
+	PokemonCount *int32 `protobuf:"varint,5,opt,name=pokemon_count,json=pokemonCount,proto3,oneof" json:"pokemon_count,omitempty"`
+}
+
+func (x *Ability) Reset() {
+	*x = Ability{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Ability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ability) ProtoMessage() {}
+
+func (x *Ability) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ability.ProtoReflect.Descriptor instead.
+func (*Ability) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Ability) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Ability) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Ability) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Ability) GetPokemon() []*NamedResource {
+	if x != nil {
+		return x.Pokemon
+	}
+	return nil
+}
+
+func (x *Ability) GetPokemonCount() int32 {
+	if x != nil && x.PokemonCount != nil {
+		return *x.PokemonCount
+	}
+	return 0
+}
+
+type Camp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          int32            `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string           `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string           `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	UnlockType  string           `protobuf:"bytes,4,opt,name=unlock_type,json=unlockType,proto3" json:"unlock_type,omitempty"`
+	Cost        *int32           `protobuf:"varint,5,opt,name=cost,proto3,oneof" json:"cost,omitempty"`
+	Pokemon     []*NamedResource `protobuf:"bytes,6,rep,name=pokemon,proto3" json:"pokemon,omitempty"`
+	// Only set with "include=counts".
+	PokemonCount *int32 `protobuf:"varint,7,opt,name=pokemon_count,json=pokemonCount,proto3,oneof" json:"pokemon_count,omitempty"`
+}
+
+func (x *Camp) Reset() {
+	*x = Camp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Camp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Camp) ProtoMessage() {}
+
+func (x *Camp) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Camp.ProtoReflect.Descriptor instead.
+func (*Camp) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Camp) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Camp) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Camp) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Camp) GetUnlockType() string {
+	if x != nil {
+		return x.UnlockType
+	}
+	return ""
+}
+
+func (x *Camp) GetCost() int32 {
+	if x != nil && x.Cost != nil {
+		return *x.Cost
+	}
+	return 0
+}
+
+func (x *Camp) GetPokemon() []*NamedResource {
+	if x != nil {
+		return x.Pokemon
+	}
+	return nil
+}
+
+func (x *Camp) GetPokemonCount() int32 {
+	if x != nil && x.PokemonCount != nil {
+		return *x.PokemonCount
+	}
+	return 0
+}
+
+type Dungeon struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id             int32             `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string            `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Levels         int32             `protobuf:"varint,3,opt,name=levels,proto3" json:"levels,omitempty"`
+	StartLevel     *int32            `protobuf:"varint,4,opt,name=start_level,json=startLevel,proto3,oneof" json:"start_level,omitempty"`
+	TeamSize       int32             `protobuf:"varint,5,opt,name=team_size,json=teamSize,proto3" json:"team_size,omitempty"`
+	ItemsAllowed   bool              `protobuf:"varint,6,opt,name=items_allowed,json=itemsAllowed,proto3" json:"items_allowed,omitempty"`
+	PokemonJoining bool              `protobuf:"varint,7,opt,name=pokemon_joining,json=pokemonJoining,proto3" json:"pokemon_joining,omitempty"`
+	MapVisible     bool              `protobuf:"varint,8,opt,name=map_visible,json=mapVisible,proto3" json:"map_visible,omitempty"`
+	Pokemon        []*DungeonPokemon `protobuf:"bytes,9,rep,name=pokemon,proto3" json:"pokemon,omitempty"`
+	// Only set with "include=counts".
+	PokemonCount *int32 `protobuf:"varint,10,opt,name=pokemon_count,json=pokemonCount,proto3,oneof" json:"pokemon_count,omitempty"`
+}
+
+func (x *Dungeon) Reset() {
+	*x = Dungeon{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Dungeon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dungeon) ProtoMessage() {}
+
+func (x *Dungeon) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dungeon.ProtoReflect.Descriptor instead.
+func (*Dungeon) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Dungeon) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Dungeon) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Dungeon) GetLevels() int32 {
+	if x != nil {
+		return x.Levels
+	}
+	return 0
+}
+
+func (x *Dungeon) GetStartLevel() int32 {
+	if x != nil && x.StartLevel != nil {
+		return *x.StartLevel
+	}
+	return 0
+}
+
+func (x *Dungeon) GetTeamSize() int32 {
+	if x != nil {
+		return x.TeamSize
+	}
+	return 0
+}
+
+func (x *Dungeon) GetItemsAllowed() bool {
+	if x != nil {
+		return x.ItemsAllowed
+	}
+	return false
+}
+
+func (x *Dungeon) GetPokemonJoining() bool {
+	if x != nil {
+		return x.PokemonJoining
+	}
+	return false
+}
+
+func (x *Dungeon) GetMapVisible() bool {
+	if x != nil {
+		return x.MapVisible
+	}
+	return false
+}
+
+func (x *Dungeon) GetPokemon() []*DungeonPokemon {
+	if x != nil {
+		return x.Pokemon
+	}
+	return nil
+}
+
+func (x *Dungeon) GetPokemonCount() int32 {
+	if x != nil && x.PokemonCount != nil {
+		return *x.PokemonCount
+	}
+	return 0
+}
+
+type DungeonPokemon struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pokemon *NamedResource `protobuf:"bytes,1,opt,name=pokemon,proto3" json:"pokemon,omitempty"`
+	IsSuper bool           `protobuf:"varint,2,opt,name=is_super,json=isSuper,proto3" json:"is_super,omitempty"`
+}
+
+func (x *DungeonPokemon) Reset() {
+	*x = DungeonPokemon{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DungeonPokemon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DungeonPokemon) ProtoMessage() {}
+
+func (x *DungeonPokemon) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DungeonPokemon.ProtoReflect.Descriptor instead.
+func (*DungeonPokemon) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DungeonPokemon) GetPokemon() *NamedResource {
+	if x != nil {
+		return x.Pokemon
+	}
+	return nil
+}
+
+func (x *DungeonPokemon) GetIsSuper() bool {
+	if x != nil {
+		return x.IsSuper
+	}
+	return false
+}
+
+// MovesByType are the moves of '/v1/moves/by-type', keyed by the names of their types.
+type MovesByType struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Types map[string]*TypeMoves `protobuf:"bytes,1,rep,name=types,proto3" json:"types,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *MovesByType) Reset() {
+	*x = MovesByType{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MovesByType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MovesByType) ProtoMessage() {}
+
+func (x *MovesByType) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MovesByType.ProtoReflect.Descriptor instead.
+func (*MovesByType) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MovesByType) GetTypes() map[string]*TypeMoves {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+type TypeMoves struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count int32            `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Moves []*NamedResource `protobuf:"bytes,2,rep,name=moves,proto3" json:"moves,omitempty"`
+}
+
+func (x *TypeMoves) Reset() {
+	*x = TypeMoves{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypeMoves) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeMoves) ProtoMessage() {}
+
+func (x *TypeMoves) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeMoves.ProtoReflect.Descriptor instead.
+func (*TypeMoves) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TypeMoves) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *TypeMoves) GetMoves() []*NamedResource {
+	if x != nil {
+		return x.Moves
+	}
+	return nil
+}
+
+type Move struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           int32          `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name         string         `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Category     string         `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	Range        string         `protobuf:"bytes,4,opt,name=range,proto3" json:"range,omitempty"`
+	Target       string         `protobuf:"bytes,5,opt,name=target,proto3" json:"target,omitempty"`
+	InitialPp    int32          `protobuf:"varint,6,opt,name=initial_pp,json=initialPP,proto3" json:"initial_pp,omitempty"`
+	InitialPower int32          `protobuf:"varint,7,opt,name=initial_power,json=initialPower,proto3" json:"initial_power,omitempty"`
+	Accuracy     int32          `protobuf:"varint,8,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	Description  string         `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
+	Type         *NamedResource `protobuf:"bytes,10,opt,name=type,proto3" json:"type,omitempty"`
+	// Only set with "at_level".
+	AtLevel *MoveAtLevel   `protobuf:"bytes,11,opt,name=at_level,json=atLevel,proto3" json:"at_level,omitempty"`
+	Pokemon []*MovePokemon `protobuf:"bytes,12,rep,name=pokemon,proto3" json:"pokemon,omitempty"`
+	// Only set with "include=counts".
+	PokemonCount *int32 `protobuf:"varint,13,opt,name=pokemon_count,json=pokemonCount,proto3,oneof" json:"pokemon_count,omitempty"`
+}
+
+func (x *Move) Reset() {
+	*x = Move{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Move) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Move) ProtoMessage() {}
+
+func (x *Move) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Move.ProtoReflect.Descriptor instead.
+func (*Move) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Move) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Move) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Move) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Move) GetRange() string {
+	if x != nil {
+		return x.Range
+	}
+	return ""
+}
+
+func (x *Move) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *Move) GetInitialPp() int32 {
+	if x != nil {
+		return x.InitialPp
+	}
+	return 0
+}
+
+func (x *Move) GetInitialPower() int32 {
+	if x != nil {
+		return x.InitialPower
+	}
+	return 0
+}
+
+func (x *Move) GetAccuracy() int32 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+func (x *Move) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Move) GetType() *NamedResource {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *Move) GetAtLevel() *MoveAtLevel {
+	if x != nil {
+		return x.AtLevel
+	}
+	return nil
+}
+
+func (x *Move) GetPokemon() []*MovePokemon {
+	if x != nil {
+		return x.Pokemon
+	}
+	return nil
+}
+
+func (x *Move) GetPokemonCount() int32 {
+	if x != nil && x.PokemonCount != nil {
+		return *x.PokemonCount
+	}
+	return 0
+}
+
+type MoveAtLevel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Level int32   `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
+	Pp    int32   `protobuf:"varint,2,opt,name=pp,proto3" json:"pp,omitempty"`
+	Power int32   `protobuf:"varint,3,opt,name=power,proto3" json:"power,omitempty"`
+	Note  *string `protobuf:"bytes,4,opt,name=note,proto3,oneof" json:"note,omitempty"`
+}
+
+func (x *MoveAtLevel) Reset() {
+	*x = MoveAtLevel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MoveAtLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveAtLevel) ProtoMessage() {}
+
+func (x *MoveAtLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveAtLevel.ProtoReflect.Descriptor instead.
+func (*MoveAtLevel) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MoveAtLevel) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *MoveAtLevel) GetPp() int32 {
+	if x != nil {
+		return x.Pp
+	}
+	return 0
+}
+
+func (x *MoveAtLevel) GetPower() int32 {
+	if x != nil {
+		return x.Power
+	}
+	return 0
+}
+
+func (x *MoveAtLevel) GetNote() string {
+	if x != nil && x.Note != nil {
+		return *x.Note
+	}
+	return ""
+}
+
+type MovePokemon struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pokemon *NamedResource `protobuf:"bytes,1,opt,name=pokemon,proto3" json:"pokemon,omitempty"`
+	Method  string         `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	Level   *int32         `protobuf:"varint,3,opt,name=level,proto3,oneof" json:"level,omitempty"`
+	Cost    *int32         `protobuf:"varint,4,opt,name=cost,proto3,oneof" json:"cost,omitempty"`
+}
+
+func (x *MovePokemon) Reset() {
+	*x = MovePokemon{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MovePokemon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MovePokemon) ProtoMessage() {}
+
+func (x *MovePokemon) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MovePokemon.ProtoReflect.Descriptor instead.
+func (*MovePokemon) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *MovePokemon) GetPokemon() *NamedResource {
+	if x != nil {
+		return x.Pokemon
+	}
+	return nil
+}
+
+func (x *MovePokemon) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *MovePokemon) GetLevel() int32 {
+	if x != nil && x.Level != nil {
+		return *x.Level
+	}
+	return 0
+}
+
+func (x *MovePokemon) GetCost() int32 {
+	if x != nil && x.Cost != nil {
+		return *x.Cost
+	}
+	return 0
+}
+
+type Pokemon struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              int32             `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name            string            `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Sprite          *string           `protobuf:"bytes,3,opt,name=sprite,proto3,oneof" json:"sprite,omitempty"`
+	Classification  string            `protobuf:"bytes,4,opt,name=classification,proto3" json:"classification,omitempty"`
+	EvolutionStage  int32             `protobuf:"varint,5,opt,name=evolution_stage,json=evolutionStage,proto3" json:"evolution_stage,omitempty"`
+	EvolveCondition string            `protobuf:"bytes,6,opt,name=evolve_condition,json=evolveCondition,proto3" json:"evolve_condition,omitempty"`
+	EvolveLevel     *int32            `protobuf:"varint,7,opt,name=evolve_level,json=evolveLevel,proto3,oneof" json:"evolve_level,omitempty"`
+	EvolveCrystals  *int32            `protobuf:"varint,8,opt,name=evolve_crystals,json=evolveCrystals,proto3,oneof" json:"evolve_crystals,omitempty"`
+	Camp            *NamedResource    `protobuf:"bytes,9,opt,name=camp,proto3" json:"camp,omitempty"`
+	Abilities       []*NamedResource  `protobuf:"bytes,10,rep,name=abilities,proto3" json:"abilities,omitempty"`
+	Dungeons        []*PokemonDungeon `protobuf:"bytes,11,rep,name=dungeons,proto3" json:"dungeons,omitempty"`
+	Moves           []*PokemonMove    `protobuf:"bytes,12,rep,name=moves,proto3" json:"moves,omitempty"`
+	Types           []*NamedResource  `protobuf:"bytes,13,rep,name=types,proto3" json:"types,omitempty"`
+	// The total number of entries of the nested lists, only set if the list is paginated,
+	// e.g. with "moves_per_page". The list fields then only contain the requested page.
+	AbilitiesCount *int32 `protobuf:"varint,14,opt,name=abilities_count,json=abilitiesCount,proto3,oneof" json:"abilities_count,omitempty"`
+	DungeonsCount  *int32 `protobuf:"varint,15,opt,name=dungeons_count,json=dungeonsCount,proto3,oneof" json:"dungeons_count,omitempty"`
+	MovesCount     *int32 `protobuf:"varint,16,opt,name=moves_count,json=movesCount,proto3,oneof" json:"moves_count,omitempty"`
+}
+
+func (x *Pokemon) Reset() {
+	*x = Pokemon{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Pokemon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pokemon) ProtoMessage() {}
+
+func (x *Pokemon) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pokemon.ProtoReflect.Descriptor instead.
+func (*Pokemon) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Pokemon) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Pokemon) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Pokemon) GetSprite() string {
+	if x != nil && x.Sprite != nil {
+		return *x.Sprite
+	}
+	return ""
+}
+
+func (x *Pokemon) GetClassification() string {
+	if x != nil {
+		return x.Classification
+	}
+	return ""
+}
+
+func (x *Pokemon) GetEvolutionStage() int32 {
+	if x != nil {
+		return x.EvolutionStage
+	}
+	return 0
+}
+
+func (x *Pokemon) GetEvolveCondition() string {
+	if x != nil {
+		return x.EvolveCondition
+	}
+	return ""
+}
+
+func (x *Pokemon) GetEvolveLevel() int32 {
+	if x != nil && x.EvolveLevel != nil {
+		return *x.EvolveLevel
+	}
+	return 0
+}
+
+func (x *Pokemon) GetEvolveCrystals() int32 {
+	if x != nil && x.EvolveCrystals != nil {
+		return *x.EvolveCrystals
+	}
+	return 0
+}
+
+func (x *Pokemon) GetCamp() *NamedResource {
+	if x != nil {
+		return x.Camp
+	}
+	return nil
+}
+
+func (x *Pokemon) GetAbilities() []*NamedResource {
+	if x != nil {
+		return x.Abilities
+	}
+	return nil
+}
+
+func (x *Pokemon) GetDungeons() []*PokemonDungeon {
+	if x != nil {
+		return x.Dungeons
+	}
+	return nil
+}
+
+func (x *Pokemon) GetMoves() []*PokemonMove {
+	if x != nil {
+		return x.Moves
+	}
+	return nil
+}
+
+func (x *Pokemon) GetTypes() []*NamedResource {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *Pokemon) GetAbilitiesCount() int32 {
+	if x != nil && x.AbilitiesCount != nil {
+		return *x.AbilitiesCount
+	}
+	return 0
+}
+
+func (x *Pokemon) GetDungeonsCount() int32 {
+	if x != nil && x.DungeonsCount != nil {
+		return *x.DungeonsCount
+	}
+	return 0
+}
+
+func (x *Pokemon) GetMovesCount() int32 {
+	if x != nil && x.MovesCount != nil {
+		return *x.MovesCount
+	}
+	return 0
+}
+
+type PokemonDungeon struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dungeon *NamedResource `protobuf:"bytes,1,opt,name=dungeon,proto3" json:"dungeon,omitempty"`
+	IsSuper bool           `protobuf:"varint,2,opt,name=is_super,json=isSuper,proto3" json:"is_super,omitempty"`
+}
+
+func (x *PokemonDungeon) Reset() {
+	*x = PokemonDungeon{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PokemonDungeon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PokemonDungeon) ProtoMessage() {}
+
+func (x *PokemonDungeon) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PokemonDungeon.ProtoReflect.Descriptor instead.
+func (*PokemonDungeon) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PokemonDungeon) GetDungeon() *NamedResource {
+	if x != nil {
+		return x.Dungeon
+	}
+	return nil
+}
+
+func (x *PokemonDungeon) GetIsSuper() bool {
+	if x != nil {
+		return x.IsSuper
+	}
+	return false
+}
+
+type PokemonMove struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Move   *NamedResource `protobuf:"bytes,1,opt,name=move,proto3" json:"move,omitempty"`
+	Method string         `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	Level  *int32         `protobuf:"varint,3,opt,name=level,proto3,oneof" json:"level,omitempty"`
+	Cost   *int32         `protobuf:"varint,4,opt,name=cost,proto3,oneof" json:"cost,omitempty"`
+}
+
+func (x *PokemonMove) Reset() {
+	*x = PokemonMove{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PokemonMove) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PokemonMove) ProtoMessage() {}
+
+func (x *PokemonMove) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PokemonMove.ProtoReflect.Descriptor instead.
+func (*PokemonMove) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PokemonMove) GetMove() *NamedResource {
+	if x != nil {
+		return x.Move
+	}
+	return nil
+}
+
+func (x *PokemonMove) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *PokemonMove) GetLevel() int32 {
+	if x != nil && x.Level != nil {
+		return *x.Level
+	}
+	return 0
+}
+
+func (x *PokemonMove) GetCost() int32 {
+	if x != nil && x.Cost != nil {
+		return *x.Cost
+	}
+	return 0
+}
+
+// PokemonNameLookup is the response of '/v1/pokemon?names=...'.
+type PokemonNameLookup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count    int32            `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Results  []*NamedResource `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	NotFound []string         `protobuf:"bytes,3,rep,name=not_found,json=notFound,proto3" json:"not_found,omitempty"`
+}
+
+func (x *PokemonNameLookup) Reset() {
+	*x = PokemonNameLookup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PokemonNameLookup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PokemonNameLookup) ProtoMessage() {}
+
+func (x *PokemonNameLookup) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PokemonNameLookup.ProtoReflect.Descriptor instead.
+func (*PokemonNameLookup) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PokemonNameLookup) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PokemonNameLookup) GetResults() []*NamedResource {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *PokemonNameLookup) GetNotFound() []string {
+	if x != nil {
+		return x.NotFound
+	}
+	return nil
+}
+
+// PokemonIDLookup is the response of '/v1/pokemon?ids=...'.
+type PokemonIDLookup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count   int32         `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Results []*Pokemon    `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	Errors  []*BatchError `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *PokemonIDLookup) Reset() {
+	*x = PokemonIDLookup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PokemonIDLookup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PokemonIDLookup) ProtoMessage() {}
+
+func (x *PokemonIDLookup) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PokemonIDLookup.ProtoReflect.Descriptor instead.
+func (*PokemonIDLookup) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PokemonIDLookup) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PokemonIDLookup) GetResults() []*Pokemon {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *PokemonIDLookup) GetErrors() []*BatchError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type BatchError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     int32  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *BatchError) Reset() {
+	*x = BatchError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchError) ProtoMessage() {}
+
+func (x *BatchError) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchError.ProtoReflect.Descriptor instead.
+func (*BatchError) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *BatchError) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BatchError) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// PokemonGroupCounts are the numbers of pokemon of '/v1/pokemon/stats', keyed by the names of the groups.
+type PokemonGroupCounts struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Groups map[string]int32 `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (x *PokemonGroupCounts) Reset() {
+	*x = PokemonGroupCounts{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PokemonGroupCounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PokemonGroupCounts) ProtoMessage() {}
+
+func (x *PokemonGroupCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PokemonGroupCounts.ProtoReflect.Descriptor instead.
+func (*PokemonGroupCounts) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PokemonGroupCounts) GetGroups() map[string]int32 {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type PokemonDefenses struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pokemon  *NamedResource `protobuf:"bytes,1,opt,name=pokemon,proto3" json:"pokemon,omitempty"`
+	Defenses []*TypeDefense `protobuf:"bytes,2,rep,name=defenses,proto3" json:"defenses,omitempty"`
+}
+
+func (x *PokemonDefenses) Reset() {
+	*x = PokemonDefenses{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PokemonDefenses) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PokemonDefenses) ProtoMessage() {}
+
+func (x *PokemonDefenses) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PokemonDefenses.ProtoReflect.Descriptor instead.
+func (*PokemonDefenses) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PokemonDefenses) GetPokemon() *NamedResource {
+	if x != nil {
+		return x.Pokemon
+	}
+	return nil
+}
+
+func (x *PokemonDefenses) GetDefenses() []*TypeDefense {
+	if x != nil {
+		return x.Defenses
+	}
+	return nil
+}
+
+type TypeDefense struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Attacker   *NamedResource `protobuf:"bytes,1,opt,name=attacker,proto3" json:"attacker,omitempty"`
+	Multiplier float64        `protobuf:"fixed64,2,opt,name=multiplier,proto3" json:"multiplier,omitempty"`
+}
+
+func (x *TypeDefense) Reset() {
+	*x = TypeDefense{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypeDefense) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeDefense) ProtoMessage() {}
+
+func (x *TypeDefense) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeDefense.ProtoReflect.Descriptor instead.
+func (*TypeDefense) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TypeDefense) GetAttacker() *NamedResource {
+	if x != nil {
+		return x.Attacker
+	}
+	return nil
+}
+
+func (x *TypeDefense) GetMultiplier() float64 {
+	if x != nil {
+		return x.Multiplier
+	}
+	return 0
+}
+
+type PokemonFullLearnset struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pokemon *NamedResource  `protobuf:"bytes,1,opt,name=pokemon,proto3" json:"pokemon,omitempty"`
+	Moves   []*LearnsetMove `protobuf:"bytes,2,rep,name=moves,proto3" json:"moves,omitempty"`
+}
+
+func (x *PokemonFullLearnset) Reset() {
+	*x = PokemonFullLearnset{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PokemonFullLearnset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PokemonFullLearnset) ProtoMessage() {}
+
+func (x *PokemonFullLearnset) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PokemonFullLearnset.ProtoReflect.Descriptor instead.
+func (*PokemonFullLearnset) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PokemonFullLearnset) GetPokemon() *NamedResource {
+	if x != nil {
+		return x.Pokemon
+	}
+	return nil
+}
+
+func (x *PokemonFullLearnset) GetMoves() []*LearnsetMove {
+	if x != nil {
+		return x.Moves
+	}
+	return nil
+}
+
+type LearnsetMove struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Move      *NamedResource   `protobuf:"bytes,1,opt,name=move,proto3" json:"move,omitempty"`
+	LearnedBy []*LearnsetEntry `protobuf:"bytes,2,rep,name=learned_by,json=learnedBy,proto3" json:"learned_by,omitempty"`
+}
+
+func (x *LearnsetMove) Reset() {
+	*x = LearnsetMove{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LearnsetMove) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LearnsetMove) ProtoMessage() {}
+
+func (x *LearnsetMove) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LearnsetMove.ProtoReflect.Descriptor instead.
+func (*LearnsetMove) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *LearnsetMove) GetMove() *NamedResource {
+	if x != nil {
+		return x.Move
+	}
+	return nil
+}
+
+func (x *LearnsetMove) GetLearnedBy() []*LearnsetEntry {
+	if x != nil {
+		return x.LearnedBy
+	}
+	return nil
+}
+
+type LearnsetEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pokemon        *NamedResource `protobuf:"bytes,1,opt,name=pokemon,proto3" json:"pokemon,omitempty"`
+	EvolutionStage int32          `protobuf:"varint,2,opt,name=evolution_stage,json=evolutionStage,proto3" json:"evolution_stage,omitempty"`
+	Method         string         `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	Level          *int32         `protobuf:"varint,4,opt,name=level,proto3,oneof" json:"level,omitempty"`
+	Cost           *int32         `protobuf:"varint,5,opt,name=cost,proto3,oneof" json:"cost,omitempty"`
+}
+
+func (x *LearnsetEntry) Reset() {
+	*x = LearnsetEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LearnsetEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LearnsetEntry) ProtoMessage() {}
+
+func (x *LearnsetEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LearnsetEntry.ProtoReflect.Descriptor instead.
+func (*LearnsetEntry) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *LearnsetEntry) GetPokemon() *NamedResource {
+	if x != nil {
+		return x.Pokemon
+	}
+	return nil
+}
+
+func (x *LearnsetEntry) GetEvolutionStage() int32 {
+	if x != nil {
+		return x.EvolutionStage
+	}
+	return 0
+}
+
+func (x *LearnsetEntry) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *LearnsetEntry) GetLevel() int32 {
+	if x != nil && x.Level != nil {
+		return *x.Level
+	}
+	return 0
+}
+
+func (x *LearnsetEntry) GetCost() int32 {
+	if x != nil && x.Cost != nil {
+		return *x.Cost
+	}
+	return 0
+}
+
+type PokemonEvolution struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Chain *EvolutionNode `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+}
+
+func (x *PokemonEvolution) Reset() {
+	*x = PokemonEvolution{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PokemonEvolution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PokemonEvolution) ProtoMessage() {}
+
+func (x *PokemonEvolution) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PokemonEvolution.ProtoReflect.Descriptor instead.
+func (*PokemonEvolution) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PokemonEvolution) GetChain() *EvolutionNode {
+	if x != nil {
+		return x.Chain
+	}
+	return nil
+}
+
+type EvolutionNode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pokemon         *NamedResource   `protobuf:"bytes,1,opt,name=pokemon,proto3" json:"pokemon,omitempty"`
+	EvolveCondition string           `protobuf:"bytes,2,opt,name=evolve_condition,json=evolveCondition,proto3" json:"evolve_condition,omitempty"`
+	EvolveLevel     *int32           `protobuf:"varint,3,opt,name=evolve_level,json=evolveLevel,proto3,oneof" json:"evolve_level,omitempty"`
+	EvolveCrystals  *int32           `protobuf:"varint,4,opt,name=evolve_crystals,json=evolveCrystals,proto3,oneof" json:"evolve_crystals,omitempty"`
+	EvolvesTo       []*EvolutionNode `protobuf:"bytes,5,rep,name=evolves_to,json=evolvesTo,proto3" json:"evolves_to,omitempty"`
+}
+
+func (x *EvolutionNode) Reset() {
+	*x = EvolutionNode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvolutionNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvolutionNode) ProtoMessage() {}
+
+func (x *EvolutionNode) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvolutionNode.ProtoReflect.Descriptor instead.
+func (*EvolutionNode) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *EvolutionNode) GetPokemon() *NamedResource {
+	if x != nil {
+		return x.Pokemon
+	}
+	return nil
+}
+
+func (x *EvolutionNode) GetEvolveCondition() string {
+	if x != nil {
+		return x.EvolveCondition
+	}
+	return ""
+}
+
+func (x *EvolutionNode) GetEvolveLevel() int32 {
+	if x != nil && x.EvolveLevel != nil {
+		return *x.EvolveLevel
+	}
+	return 0
+}
+
+func (x *EvolutionNode) GetEvolveCrystals() int32 {
+	if x != nil && x.EvolveCrystals != nil {
+		return *x.EvolveCrystals
+	}
+	return 0
+}
+
+func (x *EvolutionNode) GetEvolvesTo() []*EvolutionNode {
+	if x != nil {
+		return x.EvolvesTo
+	}
+	return nil
+}
+
+type Type struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           int32              `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name         string             `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Interactions []*TypeInteraction `protobuf:"bytes,3,rep,name=interactions,proto3" json:"interactions,omitempty"`
+	// The total number of interactions, only set if they are paginated, e.g. with "interactions_per_page".
+	InteractionsCount *int32 `protobuf:"varint,4,opt,name=interactions_count,json=interactionsCount,proto3,oneof" json:"interactions_count,omitempty"`
+	// Only set with "include=counts".
+	MoveCount    *int32 `protobuf:"varint,5,opt,name=move_count,json=moveCount,proto3,oneof" json:"move_count,omitempty"`
+	PokemonCount *int32 `protobuf:"varint,6,opt,name=pokemon_count,json=pokemonCount,proto3,oneof" json:"pokemon_count,omitempty"`
+}
+
+func (x *Type) Reset() {
+	*x = Type{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Type) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Type) ProtoMessage() {}
+
+func (x *Type) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Type.ProtoReflect.Descriptor instead.
+func (*Type) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Type) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Type) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Type) GetInteractions() []*TypeInteraction {
+	if x != nil {
+		return x.Interactions
+	}
+	return nil
+}
+
+func (x *Type) GetInteractionsCount() int32 {
+	if x != nil && x.InteractionsCount != nil {
+		return *x.InteractionsCount
+	}
+	return 0
+}
+
+func (x *Type) GetMoveCount() int32 {
+	if x != nil && x.MoveCount != nil {
+		return *x.MoveCount
+	}
+	return 0
+}
+
+func (x *Type) GetPokemonCount() int32 {
+	if x != nil && x.PokemonCount != nil {
+		return *x.PokemonCount
+	}
+	return 0
+}
+
+type TypeInteraction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Defender    *NamedResource `protobuf:"bytes,1,opt,name=defender,proto3" json:"defender,omitempty"`
+	Interaction string         `protobuf:"bytes,2,opt,name=interaction,proto3" json:"interaction,omitempty"`
+	Multiplier  float64        `protobuf:"fixed64,3,opt,name=multiplier,proto3" json:"multiplier,omitempty"`
+}
+
+func (x *TypeInteraction) Reset() {
+	*x = TypeInteraction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypeInteraction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeInteraction) ProtoMessage() {}
+
+func (x *TypeInteraction) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeInteraction.ProtoReflect.Descriptor instead.
+func (*TypeInteraction) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *TypeInteraction) GetDefender() *NamedResource {
+	if x != nil {
+		return x.Defender
+	}
+	return nil
+}
+
+func (x *TypeInteraction) GetInteraction() string {
+	if x != nil {
+		return x.Interaction
+	}
+	return ""
+}
+
+func (x *TypeInteraction) GetMultiplier() float64 {
+	if x != nil {
+		return x.Multiplier
+	}
+	return 0
+}
+
+type TypeMatrix struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count   int32            `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Results []*TypeMatrixRow `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *TypeMatrix) Reset() {
+	*x = TypeMatrix{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypeMatrix) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeMatrix) ProtoMessage() {}
+
+func (x *TypeMatrix) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeMatrix.ProtoReflect.Descriptor instead.
+func (*TypeMatrix) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *TypeMatrix) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *TypeMatrix) GetResults() []*TypeMatrixRow {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type TypeMatrixRow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Attacker *NamedResource `protobuf:"bytes,1,opt,name=attacker,proto3" json:"attacker,omitempty"`
+	Matchups []*TypeMatchup `protobuf:"bytes,2,rep,name=matchups,proto3" json:"matchups,omitempty"`
+}
+
+func (x *TypeMatrixRow) Reset() {
+	*x = TypeMatrixRow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypeMatrixRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeMatrixRow) ProtoMessage() {}
+
+func (x *TypeMatrixRow) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeMatrixRow.ProtoReflect.Descriptor instead.
+func (*TypeMatrixRow) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *TypeMatrixRow) GetAttacker() *NamedResource {
+	if x != nil {
+		return x.Attacker
+	}
+	return nil
+}
+
+func (x *TypeMatrixRow) GetMatchups() []*TypeMatchup {
+	if x != nil {
+		return x.Matchups
+	}
+	return nil
+}
+
+type TypeMatchup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Defender   *NamedResource `protobuf:"bytes,1,opt,name=defender,proto3" json:"defender,omitempty"`
+	Multiplier float64        `protobuf:"fixed64,2,opt,name=multiplier,proto3" json:"multiplier,omitempty"`
+}
+
+func (x *TypeMatchup) Reset() {
+	*x = TypeMatchup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypeMatchup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeMatchup) ProtoMessage() {}
+
+func (x *TypeMatchup) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeMatchup.ProtoReflect.Descriptor instead.
+func (*TypeMatchup) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *TypeMatchup) GetDefender() *NamedResource {
+	if x != nil {
+		return x.Defender
+	}
+	return nil
+}
+
+func (x *TypeMatchup) GetMultiplier() float64 {
+	if x != nil {
+		return x.Multiplier
+	}
+	return 0
+}
+
+type TypeCoverage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Attackers []*NamedResource     `protobuf:"bytes,1,rep,name=attackers,proto3" json:"attackers,omitempty"`
+	Coverage  []*TypeCoverageEntry `protobuf:"bytes,2,rep,name=coverage,proto3" json:"coverage,omitempty"`
+}
+
+func (x *TypeCoverage) Reset() {
+	*x = TypeCoverage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypeCoverage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeCoverage) ProtoMessage() {}
+
+func (x *TypeCoverage) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeCoverage.ProtoReflect.Descriptor instead.
+func (*TypeCoverage) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *TypeCoverage) GetAttackers() []*NamedResource {
+	if x != nil {
+		return x.Attackers
+	}
+	return nil
+}
+
+func (x *TypeCoverage) GetCoverage() []*TypeCoverageEntry {
+	if x != nil {
+		return x.Coverage
+	}
+	return nil
+}
+
+type TypeCoverageEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Defender   *NamedResource   `protobuf:"bytes,1,opt,name=defender,proto3" json:"defender,omitempty"`
+	Multiplier float64          `protobuf:"fixed64,2,opt,name=multiplier,proto3" json:"multiplier,omitempty"`
+	Attackers  []*NamedResource `protobuf:"bytes,3,rep,name=attackers,proto3" json:"attackers,omitempty"`
+}
+
+func (x *TypeCoverageEntry) Reset() {
+	*x = TypeCoverageEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypeCoverageEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeCoverageEntry) ProtoMessage() {}
+
+func (x *TypeCoverageEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeCoverageEntry.ProtoReflect.Descriptor instead.
+func (*TypeCoverageEntry) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *TypeCoverageEntry) GetDefender() *NamedResource {
+	if x != nil {
+		return x.Defender
+	}
+	return nil
+}
+
+func (x *TypeCoverageEntry) GetMultiplier() float64 {
+	if x != nil {
+		return x.Multiplier
+	}
+	return 0
+}
+
+func (x *TypeCoverageEntry) GetAttackers() []*NamedResource {
+	if x != nil {
+		return x.Attackers
+	}
+	return nil
+}
+
+type TypeEffectiveness struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Attacker    *NamedResource `protobuf:"bytes,1,opt,name=attacker,proto3" json:"attacker,omitempty"`
+	Defender    *NamedResource `protobuf:"bytes,2,opt,name=defender,proto3" json:"defender,omitempty"`
+	Interaction string         `protobuf:"bytes,3,opt,name=interaction,proto3" json:"interaction,omitempty"`
+	Multiplier  float64        `protobuf:"fixed64,4,opt,name=multiplier,proto3" json:"multiplier,omitempty"`
+}
+
+func (x *TypeEffectiveness) Reset() {
+	*x = TypeEffectiveness{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_resources_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypeEffectiveness) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeEffectiveness) ProtoMessage() {}
+
+func (x *TypeEffectiveness) ProtoReflect() protoreflect.Message {
+	mi := &file_resources_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeEffectiveness.ProtoReflect.Descriptor instead.
+func (*TypeEffectiveness) Descriptor() ([]byte, []int) {
+	return file_resources_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *TypeEffectiveness) GetAttacker() *NamedResource {
+	if x != nil {
+		return x.Attacker
+	}
+	return nil
+}
+
+func (x *TypeEffectiveness) GetDefender() *NamedResource {
+	if x != nil {
+		return x.Defender
+	}
+	return nil
+}
+
+func (x *TypeEffectiveness) GetInteraction() string {
+	if x != nil {
+		return x.Interaction
+	}
+	return ""
+}
+
+func (x *TypeEffectiveness) GetMultiplier() float64 {
+	if x != nil {
+		return x.Multiplier
+	}
+	return 0
+}
+
+var File_resources_proto protoreflect.FileDescriptor
+
+var file_resources_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x06, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x22, 0x9a, 0x01, 0x0a, 0x0d, 0x4e, 0x61,
+	0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72,
+	0x6c, 0x12, 0x1b, 0x0a, 0x06, 0x73, 0x70, 0x72, 0x69, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x06, 0x73, 0x70, 0x72, 0x69, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2b,
+	0x0a, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x52, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x5f,
+	0x73, 0x70, 0x72, 0x69, 0x74, 0x65, 0x22, 0x7f, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01,
+	0x01, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x65, 0x72, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x70, 0x65, 0x72, 0x50, 0x61, 0x67, 0x65, 0x12, 0x2f, 0x0a, 0x07,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x42, 0x08, 0x0a,
+	0x06, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x1d, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xaf, 0x02, 0x0a, 0x0d, 0x53, 0x65, 0x61, 0x72, 0x63,
+	0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x2f, 0x0a, 0x07, 0x70, 0x6f, 0x6b, 0x65,
+	0x6d, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x52, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x2b, 0x0a, 0x05, 0x6d, 0x6f, 0x76,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52,
+	0x05, 0x6d, 0x6f, 0x76, 0x65, 0x73, 0x12, 0x33, 0x0a, 0x09, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x52, 0x09, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x31, 0x0a, 0x08, 0x64,
+	0x75, 0x6e, 0x67, 0x65, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x52, 0x08, 0x64, 0x75, 0x6e, 0x67, 0x65, 0x6f, 0x6e, 0x73, 0x12, 0x2b,
+	0x0a, 0x05, 0x63, 0x61, 0x6d, 0x70, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x52, 0x05, 0x63, 0x61, 0x6d, 0x70, 0x73, 0x12, 0x2b, 0x0a, 0x05, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64,
+	0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x52, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0xbc, 0x01, 0x0a, 0x07, 0x41, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64,
+	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x0a, 0x07, 0x70, 0x6f,
+	0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d,
+	0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x52, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x0d, 0x70,
+	0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x05, 0x48, 0x00, 0x52, 0x0c, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f,
+	0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xfc, 0x01, 0x0a, 0x04, 0x43, 0x61, 0x6d, 0x70,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x75, 0x6e, 0x6c,
+	0x6f, 0x63, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x12, 0x17, 0x0a, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x88, 0x01, 0x01,
+	0x12, 0x2f, 0x0a, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f,
+	0x6e, 0x12, 0x28, 0x0a, 0x0d, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x0c, 0x70, 0x6f, 0x6b, 0x65,
+	0x6d, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f,
+	0x63, 0x6f, 0x73, 0x74, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xf5, 0x02, 0x0a, 0x07, 0x44, 0x75, 0x6e, 0x67, 0x65,
+	0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x12, 0x24,
+	0x0a, 0x0b, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x4c, 0x65, 0x76, 0x65,
+	0x6c, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x74, 0x65, 0x61, 0x6d, 0x53, 0x69, 0x7a,
+	0x65, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x77,
+	0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x41,
+	0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f,
+	0x6e, 0x5f, 0x6a, 0x6f, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0e, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x4a, 0x6f, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x70, 0x5f, 0x76, 0x69, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x6d, 0x61, 0x70, 0x56, 0x69, 0x73, 0x69, 0x62, 0x6c, 0x65,
+	0x12, 0x30, 0x0a, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x75, 0x6e, 0x67, 0x65,
+	0x6f, 0x6e, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x52, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d,
+	0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x0d, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x0c, 0x70, 0x6f, 0x6b,
+	0x65, 0x6d, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c,
+	0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x42, 0x10, 0x0a, 0x0e,
+	0x5f, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x5c,
+	0x0a, 0x0e, 0x44, 0x75, 0x6e, 0x67, 0x65, 0x6f, 0x6e, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e,
+	0x12, 0x2f, 0x0a, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f,
+	0x6e, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x73, 0x75, 0x70, 0x65, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x53, 0x75, 0x70, 0x65, 0x72, 0x22, 0x90, 0x01, 0x0a,
+	0x0b, 0x4d, 0x6f, 0x76, 0x65, 0x73, 0x42, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x34, 0x0a, 0x05,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x6d,
+	0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x73, 0x42, 0x79, 0x54, 0x79, 0x70, 0x65,
+	0x2e, 0x54, 0x79, 0x70, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x1a, 0x4b, 0x0a, 0x0a, 0x54, 0x79, 0x70, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x27, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x4d,
+	0x6f, 0x76, 0x65, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0x4e, 0x0a, 0x09, 0x54, 0x79, 0x70, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x05, 0x6d, 0x6f, 0x76, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x05, 0x6d, 0x6f, 0x76, 0x65, 0x73, 0x22,
+	0xbc, 0x03, 0x0a, 0x04, 0x4d, 0x6f, 0x76, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x61, 0x6e, 0x67,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61,
+	0x6c, 0x5f, 0x70, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x69, 0x6e, 0x69, 0x74,
+	0x69, 0x61, 0x6c, 0x50, 0x50, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c,
+	0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x69, 0x6e,
+	0x69, 0x74, 0x69, 0x61, 0x6c, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63,
+	0x63, 0x75, 0x72, 0x61, 0x63, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x61, 0x63,
+	0x63, 0x75, 0x72, 0x61, 0x63, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e,
+	0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x2e, 0x0a, 0x08, 0x61, 0x74, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4d,
+	0x6f, 0x76, 0x65, 0x41, 0x74, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x07, 0x61, 0x74, 0x4c, 0x65,
+	0x76, 0x65, 0x6c, 0x12, 0x2d, 0x0a, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x18, 0x0c,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x6f,
+	0x76, 0x65, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x52, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d,
+	0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x0d, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0c, 0x70, 0x6f, 0x6b,
+	0x65, 0x6d, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e,
+	0x5f, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x6b,
+	0x0a, 0x0b, 0x4d, 0x6f, 0x76, 0x65, 0x41, 0x74, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x14, 0x0a,
+	0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x65,
+	0x76, 0x65, 0x6c, 0x12, 0x0e, 0x0a, 0x02, 0x70, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x02, 0x70, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x04, 0x6e, 0x6f, 0x74,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x6f, 0x74, 0x65, 0x88,
+	0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x6f, 0x74, 0x65, 0x22, 0x9d, 0x01, 0x0a, 0x0b,
+	0x4d, 0x6f, 0x76, 0x65, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x2f, 0x0a, 0x07, 0x70,
+	0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70,
+	0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x52, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06,
+	0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x12, 0x19, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12,
+	0x17, 0x0a, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52,
+	0x04, 0x63, 0x6f, 0x73, 0x74, 0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6c, 0x65, 0x76,
+	0x65, 0x6c, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x63, 0x6f, 0x73, 0x74, 0x22, 0xef, 0x05, 0x0a, 0x07,
+	0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x06, 0x73,
+	0x70, 0x72, 0x69, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x73,
+	0x70, 0x72, 0x69, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0e, 0x63, 0x6c, 0x61, 0x73,
+	0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0e, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x27, 0x0a, 0x0f, 0x65, 0x76, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x74,
+	0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x65, 0x76, 0x6f, 0x6c, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x76, 0x6f,
+	0x6c, 0x76, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0f, 0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x43, 0x6f, 0x6e, 0x64, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x0c, 0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x5f, 0x6c,
+	0x65, 0x76, 0x65, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x0b, 0x65, 0x76,
+	0x6f, 0x6c, 0x76, 0x65, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f,
+	0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x5f, 0x63, 0x72, 0x79, 0x73, 0x74, 0x61, 0x6c, 0x73, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x0e, 0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x43,
+	0x72, 0x79, 0x73, 0x74, 0x61, 0x6c, 0x73, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x04, 0x63, 0x61,
+	0x6d, 0x70, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52,
+	0x04, 0x63, 0x61, 0x6d, 0x70, 0x12, 0x33, 0x0a, 0x09, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52,
+	0x09, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x08, 0x64, 0x75,
+	0x6e, 0x67, 0x65, 0x6f, 0x6e, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70,
+	0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x44, 0x75, 0x6e,
+	0x67, 0x65, 0x6f, 0x6e, 0x52, 0x08, 0x64, 0x75, 0x6e, 0x67, 0x65, 0x6f, 0x6e, 0x73, 0x12, 0x29,
+	0x0a, 0x05, 0x6d, 0x6f, 0x76, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x4d, 0x6f,
+	0x76, 0x65, 0x52, 0x05, 0x6d, 0x6f, 0x76, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x05, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52,
+	0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x0f, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x03, 0x52, 0x0e, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x64, 0x75, 0x6e, 0x67, 0x65, 0x6f, 0x6e, 0x73,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x48, 0x04, 0x52, 0x0d,
+	0x64, 0x75, 0x6e, 0x67, 0x65, 0x6f, 0x6e, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01,
+	0x12, 0x24, 0x0a, 0x0b, 0x6d, 0x6f, 0x76, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x10, 0x20, 0x01, 0x28, 0x05, 0x48, 0x05, 0x52, 0x0a, 0x6d, 0x6f, 0x76, 0x65, 0x73, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x70, 0x72, 0x69, 0x74,
+	0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x5f, 0x6c, 0x65, 0x76,
+	0x65, 0x6c, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x5f, 0x63, 0x72,
+	0x79, 0x73, 0x74, 0x61, 0x6c, 0x73, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x64,
+	0x75, 0x6e, 0x67, 0x65, 0x6f, 0x6e, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x0e, 0x0a,
+	0x0c, 0x5f, 0x6d, 0x6f, 0x76, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x5c, 0x0a,
+	0x0e, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x44, 0x75, 0x6e, 0x67, 0x65, 0x6f, 0x6e, 0x12,
+	0x2f, 0x0a, 0x07, 0x64, 0x75, 0x6e, 0x67, 0x65, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x07, 0x64, 0x75, 0x6e, 0x67, 0x65, 0x6f, 0x6e,
+	0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x73, 0x75, 0x70, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x53, 0x75, 0x70, 0x65, 0x72, 0x22, 0x97, 0x01, 0x0a, 0x0b,
+	0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x4d, 0x6f, 0x76, 0x65, 0x12, 0x29, 0x0a, 0x04, 0x6d,
+	0x6f, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x52, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x19,
+	0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52,
+	0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x63, 0x6f, 0x73,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x88,
+	0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x42, 0x07, 0x0a, 0x05,
+	0x5f, 0x63, 0x6f, 0x73, 0x74, 0x22, 0x77, 0x0a, 0x11, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e,
+	0x4e, 0x61, 0x6d, 0x65, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x2f, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x6f, 0x74, 0x46, 0x6f, 0x75, 0x6e, 0x64, 0x22, 0x7e,
+	0x0a, 0x0f, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x49, 0x44, 0x4c, 0x6f, 0x6f, 0x6b, 0x75,
+	0x70, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x12, 0x2a, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x34,
+	0x0a, 0x0a, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x22, 0x8f, 0x01, 0x0a, 0x12, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x3e, 0x0a, 0x06, 0x67,
+	0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x70, 0x6d,
+	0x64, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x73, 0x0a, 0x0f, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f,
+	0x6e, 0x44, 0x65, 0x66, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x12, 0x2f, 0x0a, 0x07, 0x70, 0x6f, 0x6b,
+	0x65, 0x6d, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64,
+	0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x52, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x2f, 0x0a, 0x08, 0x64, 0x65,
+	0x66, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70,
+	0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x44, 0x65, 0x66, 0x65, 0x6e, 0x73,
+	0x65, 0x52, 0x08, 0x64, 0x65, 0x66, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x22, 0x60, 0x0a, 0x0b, 0x54,
+	0x79, 0x70, 0x65, 0x44, 0x65, 0x66, 0x65, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x61, 0x74,
+	0x74, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70,
+	0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x52, 0x08, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x1e, 0x0a,
+	0x0a, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0a, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x22, 0x72, 0x0a,
+	0x13, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x46, 0x75, 0x6c, 0x6c, 0x4c, 0x65, 0x61, 0x72,
+	0x6e, 0x73, 0x65, 0x74, 0x12, 0x2f, 0x0a, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e,
+	0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x07, 0x70, 0x6f,
+	0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x05, 0x6d, 0x6f, 0x76, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x65,
+	0x61, 0x72, 0x6e, 0x73, 0x65, 0x74, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x05, 0x6d, 0x6f, 0x76, 0x65,
+	0x73, 0x22, 0x6f, 0x0a, 0x0c, 0x4c, 0x65, 0x61, 0x72, 0x6e, 0x73, 0x65, 0x74, 0x4d, 0x6f, 0x76,
+	0x65, 0x12, 0x29, 0x0a, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x34, 0x0a, 0x0a,
+	0x6c, 0x65, 0x61, 0x72, 0x6e, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x65, 0x61, 0x72, 0x6e, 0x73,
+	0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x6c, 0x65, 0x61, 0x72, 0x6e, 0x65, 0x64,
+	0x42, 0x79, 0x22, 0xc8, 0x01, 0x0a, 0x0d, 0x4c, 0x65, 0x61, 0x72, 0x6e, 0x73, 0x65, 0x74, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x2f, 0x0a, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e,
+	0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x07, 0x70, 0x6f,
+	0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x76, 0x6f, 0x6c, 0x75, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e,
+	0x65, 0x76, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x19, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x88, 0x01,
+	0x01, 0x12, 0x17, 0x0a, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x01, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6c,
+	0x65, 0x76, 0x65, 0x6c, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x63, 0x6f, 0x73, 0x74, 0x22, 0x3f, 0x0a,
+	0x10, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x45, 0x76, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x2b, 0x0a, 0x05, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x6f, 0x6c, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x22, 0x9c,
+	0x02, 0x0a, 0x0d, 0x45, 0x76, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x6f, 0x64, 0x65,
+	0x12, 0x2f, 0x0a, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f,
+	0x6e, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x64,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x65, 0x76, 0x6f,
+	0x6c, 0x76, 0x65, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x0c,
+	0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x48, 0x00, 0x52, 0x0b, 0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x4c, 0x65, 0x76, 0x65,
+	0x6c, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x5f, 0x63,
+	0x72, 0x79, 0x73, 0x74, 0x61, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52,
+	0x0e, 0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x43, 0x72, 0x79, 0x73, 0x74, 0x61, 0x6c, 0x73, 0x88,
+	0x01, 0x01, 0x12, 0x34, 0x0a, 0x0a, 0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x73, 0x5f, 0x74, 0x6f,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x76, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x65,
+	0x76, 0x6f, 0x6c, 0x76, 0x65, 0x73, 0x54, 0x6f, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x65, 0x76, 0x6f,
+	0x6c, 0x76, 0x65, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x65, 0x76,
+	0x6f, 0x6c, 0x76, 0x65, 0x5f, 0x63, 0x72, 0x79, 0x73, 0x74, 0x61, 0x6c, 0x73, 0x22, 0xa1, 0x02,
+	0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x3b, 0x0a, 0x0c, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x32, 0x0a, 0x12, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x11, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x6d,
+	0x6f, 0x76, 0x65, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x01, 0x52, 0x09, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12,
+	0x28, 0x0a, 0x0d, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x0c, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f,
+	0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x6f, 0x76, 0x65, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42,
+	0x10, 0x0a, 0x0e, 0x5f, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x22, 0x86, 0x01, 0x0a, 0x0f, 0x54, 0x79, 0x70, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x31, 0x0a, 0x08, 0x64, 0x65, 0x66, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x08,
+	0x64, 0x65, 0x66, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x75,
+	0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x22, 0x53, 0x0a, 0x0a, 0x54, 0x79,
+	0x70, 0x65, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2f,
+	0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x4d, 0x61, 0x74,
+	0x72, 0x69, 0x78, 0x52, 0x6f, 0x77, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22,
+	0x73, 0x0a, 0x0d, 0x54, 0x79, 0x70, 0x65, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x52, 0x6f, 0x77,
+	0x12, 0x31, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65,
+	0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x08, 0x61, 0x74, 0x74, 0x61, 0x63,
+	0x6b, 0x65, 0x72, 0x12, 0x2f, 0x0a, 0x08, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x75, 0x70, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54,
+	0x79, 0x70, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x75, 0x70, 0x52, 0x08, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x75, 0x70, 0x73, 0x22, 0x60, 0x0a, 0x0b, 0x54, 0x79, 0x70, 0x65, 0x4d, 0x61, 0x74, 0x63,
+	0x68, 0x75, 0x70, 0x12, 0x31, 0x0a, 0x08, 0x64, 0x65, 0x66, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e,
+	0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x08, 0x64, 0x65,
+	0x66, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70,
+	0x6c, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x6d, 0x75, 0x6c, 0x74,
+	0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x22, 0x7a, 0x0a, 0x0c, 0x54, 0x79, 0x70, 0x65, 0x43, 0x6f,
+	0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x33, 0x0a, 0x09, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b,
+	0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x52, 0x09, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x35, 0x0a, 0x08, 0x63,
+	0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x43, 0x6f, 0x76, 0x65, 0x72,
+	0x61, 0x67, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x61,
+	0x67, 0x65, 0x22, 0x9b, 0x01, 0x0a, 0x11, 0x54, 0x79, 0x70, 0x65, 0x43, 0x6f, 0x76, 0x65, 0x72,
+	0x61, 0x67, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x31, 0x0a, 0x08, 0x64, 0x65, 0x66, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64,
+	0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x52, 0x08, 0x64, 0x65, 0x66, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x6d,
+	0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0a, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x12, 0x33, 0x0a, 0x09, 0x61,
+	0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x09, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x73,
+	0x22, 0xbb, 0x01, 0x0a, 0x11, 0x54, 0x79, 0x70, 0x65, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x31, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52,
+	0x08, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x08, 0x64, 0x65, 0x66,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6d,
+	0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x52, 0x08, 0x64, 0x65, 0x66, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e,
+	0x0a, 0x0a, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0a, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x42, 0x26,
+	0x5a, 0x24, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x61, 0x6e,
+	0x65, 0x6b, 0x36, 0x34, 0x2f, 0x70, 0x6d, 0x64, 0x2d, 0x64, 0x78, 0x2d, 0x61, 0x70, 0x69, 0x2f,
+	0x61, 0x70, 0x69, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_resources_proto_rawDescOnce sync.Once
+	file_resources_proto_rawDescData = file_resources_proto_rawDesc
+)
+
+func file_resources_proto_rawDescGZIP() []byte {
+	file_resources_proto_rawDescOnce.Do(func() {
+		file_resources_proto_rawDescData = protoimpl.X.CompressGZIP(file_resources_proto_rawDescData)
+	})
+	return file_resources_proto_rawDescData
+}
+
+var file_resources_proto_msgTypes = make([]protoimpl.MessageInfo, 37)
+var file_resources_proto_goTypes = []interface{}{
+	(*NamedResource)(nil),       // 0: pmd.v1.NamedResource
+	(*ResourceList)(nil),        // 1: pmd.v1.ResourceList
+	(*Count)(nil),               // 2: pmd.v1.Count
+	(*SearchResults)(nil),       // 3: pmd.v1.SearchResults
+	(*Ability)(nil),             // 4: pmd.v1.Ability
+	(*Camp)(nil),                // 5: pmd.v1.Camp
+	(*Dungeon)(nil),             // 6: pmd.v1.Dungeon
+	(*DungeonPokemon)(nil),      // 7: pmd.v1.DungeonPokemon
+	(*MovesByType)(nil),         // 8: pmd.v1.MovesByType
+	(*TypeMoves)(nil),           // 9: pmd.v1.TypeMoves
+	(*Move)(nil),                // 10: pmd.v1.Move
+	(*MoveAtLevel)(nil),         // 11: pmd.v1.MoveAtLevel
+	(*MovePokemon)(nil),         // 12: pmd.v1.MovePokemon
+	(*Pokemon)(nil),             // 13: pmd.v1.Pokemon
+	(*PokemonDungeon)(nil),      // 14: pmd.v1.PokemonDungeon
+	(*PokemonMove)(nil),         // 15: pmd.v1.PokemonMove
+	(*PokemonNameLookup)(nil),   // 16: pmd.v1.PokemonNameLookup
+	(*PokemonIDLookup)(nil),     // 17: pmd.v1.PokemonIDLookup
+	(*BatchError)(nil),          // 18: pmd.v1.BatchError
+	(*PokemonGroupCounts)(nil),  // 19: pmd.v1.PokemonGroupCounts
+	(*PokemonDefenses)(nil),     // 20: pmd.v1.PokemonDefenses
+	(*TypeDefense)(nil),         // 21: pmd.v1.TypeDefense
+	(*PokemonFullLearnset)(nil), // 22: pmd.v1.PokemonFullLearnset
+	(*LearnsetMove)(nil),        // 23: pmd.v1.LearnsetMove
+	(*LearnsetEntry)(nil),       // 24: pmd.v1.LearnsetEntry
+	(*PokemonEvolution)(nil),    // 25: pmd.v1.PokemonEvolution
+	(*EvolutionNode)(nil),       // 26: pmd.v1.EvolutionNode
+	(*Type)(nil),                // 27: pmd.v1.Type
+	(*TypeInteraction)(nil),     // 28: pmd.v1.TypeInteraction
+	(*TypeMatrix)(nil),          // 29: pmd.v1.TypeMatrix
+	(*TypeMatrixRow)(nil),       // 30: pmd.v1.TypeMatrixRow
+	(*TypeMatchup)(nil),         // 31: pmd.v1.TypeMatchup
+	(*TypeCoverage)(nil),        // 32: pmd.v1.TypeCoverage
+	(*TypeCoverageEntry)(nil),   // 33: pmd.v1.TypeCoverageEntry
+	(*TypeEffectiveness)(nil),   // 34: pmd.v1.TypeEffectiveness
+	nil,                         // 35: pmd.v1.MovesByType.TypesEntry
+	nil,                         // 36: pmd.v1.PokemonGroupCounts.GroupsEntry
+}
+var file_resources_proto_depIdxs = []int32{
+	0,  // 0: pmd.v1.NamedResource.types:type_name -> pmd.v1.NamedResource
+	0,  // 1: pmd.v1.ResourceList.results:type_name -> pmd.v1.NamedResource
+	0,  // 2: pmd.v1.SearchResults.pokemon:type_name -> pmd.v1.NamedResource
+	0,  // 3: pmd.v1.SearchResults.moves:type_name -> pmd.v1.NamedResource
+	0,  // 4: pmd.v1.SearchResults.abilities:type_name -> pmd.v1.NamedResource
+	0,  // 5: pmd.v1.SearchResults.dungeons:type_name -> pmd.v1.NamedResource
+	0,  // 6: pmd.v1.SearchResults.camps:type_name -> pmd.v1.NamedResource
+	0,  // 7: pmd.v1.SearchResults.types:type_name -> pmd.v1.NamedResource
+	0,  // 8: pmd.v1.Ability.pokemon:type_name -> pmd.v1.NamedResource
+	0,  // 9: pmd.v1.Camp.pokemon:type_name -> pmd.v1.NamedResource
+	7,  // 10: pmd.v1.Dungeon.pokemon:type_name -> pmd.v1.DungeonPokemon
+	0,  // 11: pmd.v1.DungeonPokemon.pokemon:type_name -> pmd.v1.NamedResource
+	35, // 12: pmd.v1.MovesByType.types:type_name -> pmd.v1.MovesByType.TypesEntry
+	0,  // 13: pmd.v1.TypeMoves.moves:type_name -> pmd.v1.NamedResource
+	0,  // 14: pmd.v1.Move.type:type_name -> pmd.v1.NamedResource
+	11, // 15: pmd.v1.Move.at_level:type_name -> pmd.v1.MoveAtLevel
+	12, // 16: pmd.v1.Move.pokemon:type_name -> pmd.v1.MovePokemon
+	0,  // 17: pmd.v1.MovePokemon.pokemon:type_name -> pmd.v1.NamedResource
+	0,  // 18: pmd.v1.Pokemon.camp:type_name -> pmd.v1.NamedResource
+	0,  // 19: pmd.v1.Pokemon.abilities:type_name -> pmd.v1.NamedResource
+	14, // 20: pmd.v1.Pokemon.dungeons:type_name -> pmd.v1.PokemonDungeon
+	15, // 21: pmd.v1.Pokemon.moves:type_name -> pmd.v1.PokemonMove
+	0,  // 22: pmd.v1.Pokemon.types:type_name -> pmd.v1.NamedResource
+	0,  // 23: pmd.v1.PokemonDungeon.dungeon:type_name -> pmd.v1.NamedResource
+	0,  // 24: pmd.v1.PokemonMove.move:type_name -> pmd.v1.NamedResource
+	0,  // 25: pmd.v1.PokemonNameLookup.results:type_name -> pmd.v1.NamedResource
+	13, // 26: pmd.v1.PokemonIDLookup.results:type_name -> pmd.v1.Pokemon
+	18, // 27: pmd.v1.PokemonIDLookup.errors:type_name -> pmd.v1.BatchError
+	36, // 28: pmd.v1.PokemonGroupCounts.groups:type_name -> pmd.v1.PokemonGroupCounts.GroupsEntry
+	0,  // 29: pmd.v1.PokemonDefenses.pokemon:type_name -> pmd.v1.NamedResource
+	21, // 30: pmd.v1.PokemonDefenses.defenses:type_name -> pmd.v1.TypeDefense
+	0,  // 31: pmd.v1.TypeDefense.attacker:type_name -> pmd.v1.NamedResource
+	0,  // 32: pmd.v1.PokemonFullLearnset.pokemon:type_name -> pmd.v1.NamedResource
+	23, // 33: pmd.v1.PokemonFullLearnset.moves:type_name -> pmd.v1.LearnsetMove
+	0,  // 34: pmd.v1.LearnsetMove.move:type_name -> pmd.v1.NamedResource
+	24, // 35: pmd.v1.LearnsetMove.learned_by:type_name -> pmd.v1.LearnsetEntry
+	0,  // 36: pmd.v1.LearnsetEntry.pokemon:type_name -> pmd.v1.NamedResource
+	26, // 37: pmd.v1.PokemonEvolution.chain:type_name -> pmd.v1.EvolutionNode
+	0,  // 38: pmd.v1.EvolutionNode.pokemon:type_name -> pmd.v1.NamedResource
+	26, // 39: pmd.v1.EvolutionNode.evolves_to:type_name -> pmd.v1.EvolutionNode
+	28, // 40: pmd.v1.Type.interactions:type_name -> pmd.v1.TypeInteraction
+	0,  // 41: pmd.v1.TypeInteraction.defender:type_name -> pmd.v1.NamedResource
+	30, // 42: pmd.v1.TypeMatrix.results:type_name -> pmd.v1.TypeMatrixRow
+	0,  // 43: pmd.v1.TypeMatrixRow.attacker:type_name -> pmd.v1.NamedResource
+	31, // 44: pmd.v1.TypeMatrixRow.matchups:type_name -> pmd.v1.TypeMatchup
+	0,  // 45: pmd.v1.TypeMatchup.defender:type_name -> pmd.v1.NamedResource
+	0,  // 46: pmd.v1.TypeCoverage.attackers:type_name -> pmd.v1.NamedResource
+	33, // 47: pmd.v1.TypeCoverage.coverage:type_name -> pmd.v1.TypeCoverageEntry
+	0,  // 48: pmd.v1.TypeCoverageEntry.defender:type_name -> pmd.v1.NamedResource
+	0,  // 49: pmd.v1.TypeCoverageEntry.attackers:type_name -> pmd.v1.NamedResource
+	0,  // 50: pmd.v1.TypeEffectiveness.attacker:type_name -> pmd.v1.NamedResource
+	0,  // 51: pmd.v1.TypeEffectiveness.defender:type_name -> pmd.v1.NamedResource
+	9,  // 52: pmd.v1.MovesByType.TypesEntry.value:type_name -> pmd.v1.TypeMoves
+	53, // [53:53] is the sub-list for method output_type
+	53, // [53:53] is the sub-list for method input_type
+	53, // [53:53] is the sub-list for extension type_name
+	53, // [53:53] is the sub-list for extension extendee
+	0,  // [0:53] is the sub-list for field type_name
+}
+
+func init() { file_resources_proto_init() }
+func file_resources_proto_init() {
+	if File_resources_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_resources_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NamedResource); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Count); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchResults); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Ability); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Camp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Dungeon); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DungeonPokemon); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MovesByType); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypeMoves); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Move); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MoveAtLevel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MovePokemon); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Pokemon); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PokemonDungeon); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PokemonMove); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PokemonNameLookup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PokemonIDLookup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PokemonGroupCounts); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PokemonDefenses); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypeDefense); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PokemonFullLearnset); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LearnsetMove); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LearnsetEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PokemonEvolution); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvolutionNode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Type); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypeInteraction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypeMatrix); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypeMatrixRow); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypeMatchup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypeCoverage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypeCoverageEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_resources_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypeEffectiveness); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_resources_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	file_resources_proto_msgTypes[1].OneofWrappers = []interface{}{}
+	file_resources_proto_msgTypes[4].OneofWrappers = []interface{}{}
+	file_resources_proto_msgTypes[5].OneofWrappers = []interface{}{}
+	file_resources_proto_msgTypes[6].OneofWrappers = []interface{}{}
+	file_resources_proto_msgTypes[10].OneofWrappers = []interface{}{}
+	file_resources_proto_msgTypes[11].OneofWrappers = []interface{}{}
+	file_resources_proto_msgTypes[12].OneofWrappers = []interface{}{}
+	file_resources_proto_msgTypes[13].OneofWrappers = []interface{}{}
+	file_resources_proto_msgTypes[15].OneofWrappers = []interface{}{}
+	file_resources_proto_msgTypes[24].OneofWrappers = []interface{}{}
+	file_resources_proto_msgTypes[26].OneofWrappers = []interface{}{}
+	file_resources_proto_msgTypes[27].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_resources_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   37,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_resources_proto_goTypes,
+		DependencyIndexes: file_resources_proto_depIdxs,
+		MessageInfos:      file_resources_proto_msgTypes,
+	}.Build()
+	File_resources_proto = out.File
+	file_resources_proto_rawDesc = nil
+	file_resources_proto_goTypes = nil
+	file_resources_proto_depIdxs = nil
+}
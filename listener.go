@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/janek64/pmd-dx-api/api/logger"
+)
+
+// limitLogInterval is the minimum interval between two logs of a reached connection limit.
+const limitLogInterval = time.Minute
+
+// limitListener is a net.Listener accepting at most limit simultaneous connections, like
+// netutil.LimitListener. Further connections are not accepted until an open connection is closed.
+type limitListener struct {
+	net.Listener
+	limit     int
+	slots     chan struct{}
+	lastLog   time.Time
+	lastLogMu sync.Mutex
+}
+
+// newLimitListener returns a listener accepting at most limit simultaneous connections from the listener.
+func newLimitListener(listener net.Listener, limit int) net.Listener {
+	return &limitListener{Listener: listener, limit: limit, slots: make(chan struct{}, limit)}
+}
+
+// Accept waits for a free slot and then for the next connection.
+func (l *limitListener) Accept() (net.Conn, error) {
+	select {
+	case l.slots <- struct{}{}:
+	default:
+		// All slots are taken, log it and wait until a connection is closed
+		l.logLimitReached()
+		l.slots <- struct{}{}
+	}
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		<-l.slots
+		return nil, err
+	}
+	return &limitListenerConn{Conn: conn, release: func() { <-l.slots }}, nil
+}
+
+// logLimitReached logs the reached limit to the error log, at most once per limitLogInterval.
+func (l *limitListener) logLimitReached() {
+	l.lastLogMu.Lock()
+	defer l.lastLogMu.Unlock()
+	if time.Since(l.lastLog) < limitLogInterval {
+		return
+	}
+	l.lastLog = time.Now()
+	pc, file, line, ok := runtime.Caller(0)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "limitListener: failed to fetch caller information")
+		return
+	}
+	caller := logger.CallerInformation{Pc: pc, File: file, Line: line}
+	logger.LogWarning(fmt.Errorf("connection limit of %v reached, new connections are delayed", l.limit), caller)
+}
+
+// limitListenerConn is a connection of a limitListener that frees its slot when it is closed.
+type limitListenerConn struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+// Close closes the connection and frees its slot once.
+func (c *limitListenerConn) Close() error {
+	err := c.Conn.Close()
+	c.releaseOnce.Do(c.release)
+	return err
+}
@@ -3,9 +3,18 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/janek64/pmd-dx-api/api/cache"
 	"github.com/janek64/pmd-dx-api/api/db"
@@ -24,6 +33,60 @@ func getEnv(key string, defaultValue string) string {
 	return value
 }
 
+// refreshMaterializedViews refreshes the materialized views of the database in the provided interval
+// and logs all errors to the error log. A refresh taking longer than the interval is canceled, so the
+// refreshes do not pile up.
+func refreshMaterializedViews(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		err := db.RefreshMaterializedViews(ctx)
+		cancel()
+		if err != nil {
+			pc, file, line, ok := runtime.Caller(0)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "refreshMaterializedViews: failed to fetch caller information")
+				continue
+			}
+			caller := logger.CallerInformation{Pc: pc, File: file, Line: line}
+			logger.LogError(err, caller)
+		}
+	}
+}
+
+// defaultWarmURLs are the URLs that are fetched when warming the cache if CACHE_WARM_URLS is not set.
+const defaultWarmURLs = "/v1/abilities,/v1/camps,/v1/dungeons,/v1/moves,/v1/pokemon,/v1/types"
+
+// warmCache fills the cache with the responses of the URLs by requesting them from the router,
+// so they pass through the same middleware as requests of clients.
+func warmCache(ctx context.Context, router http.Handler, urls []string, config cache.WarmConfig) {
+	fmt.Printf("Warming the cache for %v URLs in batches of %v\n", len(urls), config.BatchSize)
+	config.Progress = func(processed int, failed int) {
+		fmt.Printf("Cache warming: %v/%v URLs processed, %v failed\n", processed, len(urls), failed)
+	}
+	fetched, err := cache.Warm(ctx, urls, config, func(ctx context.Context, url string) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		// Identify the warming requests in the access log and the rate limit
+		request.RemoteAddr = "cache-warming"
+		request.Header.Set("User-Agent", "pmd-dx-api cache warming")
+		response := cache.NewCacheResponseRecorder()
+		router.ServeHTTP(response, request)
+		if response.Status != http.StatusOK {
+			return fmt.Errorf("unexpected status %v", response.Status)
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("Cache warming stopped after %v of %v URLs: %v\n", fetched, len(urls), err)
+		return
+	}
+	fmt.Printf("Cache warming finished, %v of %v URLs cached\n", fetched, len(urls))
+}
+
 func main() {
 
 	// Initialize the logger
@@ -50,6 +113,27 @@ func main() {
 		}
 	}()
 
+	// Refresh the materialized views periodically if they are used
+	if db.MaterializedViewsEnabled() {
+		interval, err := time.ParseDuration(getEnv("VIEW_REFRESH_INTERVAL", "24h"))
+		if err != nil || interval <= 0 {
+			fmt.Fprintf(os.Stderr, "Invalid VIEW_REFRESH_INTERVAL, expected a positive duration\n")
+			os.Exit(1)
+		}
+		go refreshMaterializedViews(interval)
+	}
+
+	// Read the handler and middleware configuration
+	handler.InitHandler()
+	middleware.InitMiddleware()
+	// Register the response templates if a template file is configured
+	if path, ok := os.LookupEnv("RESPONSE_TEMPLATES"); ok && path != "" {
+		if err := handler.LoadResponseTemplates(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to load RESPONSE_TEMPLATES: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Initialize the redis connection
 	err = cache.InitRedis()
 	if err != nil {
@@ -71,32 +155,165 @@ func main() {
 	// Create a new httprouter that will handle requests
 	router := httprouter.New()
 
+	// Check if the experimental diff responses are enabled
+	diffResponses, _ := strconv.ParseBool(getEnv("DIFF_RESPONSES", "false"))
+
+	// REQUEST_TIMEOUT bounds the duration of each request, including its database queries
+	requestTimeout, err := time.ParseDuration(getEnv("REQUEST_TIMEOUT", "30s"))
+	if err != nil || requestTimeout <= 0 {
+		fmt.Fprintf(os.Stderr, "Invalid REQUEST_TIMEOUT, expected a positive duration\n")
+		os.Exit(1)
+	}
+
+	// CACHE_TTL defaults to 0, which keeps cached responses until they are evicted or purged
+	cacheTTL, err := time.ParseDuration(getEnv("CACHE_TTL", "0"))
+	if err != nil || cacheTTL < 0 {
+		fmt.Fprintf(os.Stderr, "Invalid CACHE_TTL, expected a non-negative duration\n")
+		os.Exit(1)
+	}
+	// CACHE_LIST_TTL overrides the TTL of the resource lists, which change with every added resource
+	cacheListTTL, err := time.ParseDuration(getEnv("CACHE_LIST_TTL", cacheTTL.String()))
+	if err != nil || cacheListTTL < 0 {
+		fmt.Fprintf(os.Stderr, "Invalid CACHE_LIST_TTL, expected a non-negative duration\n")
+		os.Exit(1)
+	}
+
 	// Define the middleware chains
-	defaultMiddleware := func(h httprouter.Handle) httprouter.Handle {
-		return middleware.LogRequest(middleware.CacheResponse(middleware.FieldLimitingParams(h)))
-	}
-	resourceListMiddleware := func(h httprouter.Handle) httprouter.Handle {
-		return defaultMiddleware(middleware.ResourceListParams(h))
-	}
-
-	// Register all handlers
-	router.GET("/v1/abilities", resourceListMiddleware(handler.AbilityListHandler))
-	router.GET("/v1/abilities/:searcharg", defaultMiddleware(handler.AbilitySearchHandler))
-	router.GET("/v1/camps", resourceListMiddleware(handler.CampListHandler))
-	router.GET("/v1/camps/:searcharg", defaultMiddleware(handler.CampSearchHandler))
-	router.GET("/v1/dungeons", resourceListMiddleware(handler.DungeonListHandler))
-	router.GET("/v1/dungeons/:searcharg", defaultMiddleware(handler.DungeonSearchHandler))
-	router.GET("/v1/moves", resourceListMiddleware(handler.MoveListHandler))
-	router.GET("/v1/moves/:searcharg", defaultMiddleware(handler.MoveSearchHandler))
-	router.GET("/v1/pokemon", resourceListMiddleware(handler.PokemonListHandler))
-	router.GET("/v1/pokemon/:searcharg", defaultMiddleware(handler.PokemonSearchHandler))
-	router.GET("/v1/types", resourceListMiddleware(handler.PokemonTypeListHandler))
-	router.GET("/v1/types/:searcharg", defaultMiddleware(handler.PokemonTypeSearchHandler))
-
-	// Overwrite the default NotFound handler to log 404 requests
-	router.NotFound = http.HandlerFunc(handler.Default404Handler)
+	cachedMiddlewareWithTTL := func(ttl time.Duration, h httprouter.Handle) httprouter.Handle {
+		chain := middleware.CacheResponse(ttl, middleware.FieldLimitingParams(middleware.FormatParams(h)))
+		if diffResponses {
+			chain = middleware.DiffResponse(chain)
+		}
+		return middleware.LogRequest(middleware.DataVersionHeaders(middleware.CORS(middleware.RateLimit(middleware.Timeout(requestTimeout, chain)))))
+	}
+	registerRoutes(router, middlewareChains{
+		cached: func(h httprouter.Handle) httprouter.Handle {
+			return cachedMiddlewareWithTTL(cacheTTL, h)
+		},
+		resourceList: func(h httprouter.Handle) httprouter.Handle {
+			return cachedMiddlewareWithTTL(cacheListTTL, middleware.ResourceListParams(h))
+		},
+		uncached: func(h httprouter.Handle) httprouter.Handle {
+			return middleware.LogRequest(middleware.DataVersionHeaders(middleware.CORS(middleware.RateLimit(middleware.Timeout(requestTimeout, middleware.FieldLimitingParams(middleware.FormatParams(h)))))))
+		},
+	})
+
+	// Configure the server for high-throughput clients
+	// MAX_HEADER_BYTES defaults to 1 MB (http.DefaultMaxHeaderBytes), which leaves room for many cookies or long conditional headers
+	maxHeaderBytes, err := strconv.Atoi(getEnv("MAX_HEADER_BYTES", strconv.Itoa(http.DefaultMaxHeaderBytes)))
+	if err != nil || maxHeaderBytes <= 0 {
+		fmt.Fprintf(os.Stderr, "Invalid MAX_HEADER_BYTES, expected a positive integer\n")
+		os.Exit(1)
+	}
+	// KEEP_ALIVE defaults to true so clients can reuse connections for multiple requests
+	keepAlive, err := strconv.ParseBool(getEnv("KEEP_ALIVE", "true"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid KEEP_ALIVE, expected a boolean\n")
+		os.Exit(1)
+	}
+	// IDLE_TIMEOUT defaults to 2 minutes to close unused keep-alive connections instead of keeping them open forever
+	idleTimeout, err := time.ParseDuration(getEnv("IDLE_TIMEOUT", "2m"))
+	if err != nil || idleTimeout <= 0 {
+		fmt.Fprintf(os.Stderr, "Invalid IDLE_TIMEOUT, expected a positive duration\n")
+		os.Exit(1)
+	}
+	server := &http.Server{
+		Addr:           ":" + port,
+		Handler:        middleware.RequestID(router),
+		MaxHeaderBytes: maxHeaderBytes,
+		IdleTimeout:    idleTimeout,
+	}
+	server.SetKeepAlivesEnabled(keepAlive)
+
+	// MAX_CONNECTIONS limits the simultaneous connections as backpressure under extreme load, 0 means unlimited
+	maxConnections, err := strconv.Atoi(getEnv("MAX_CONNECTIONS", "0"))
+	if err != nil || maxConnections < 0 {
+		fmt.Fprintf(os.Stderr, "Invalid MAX_CONNECTIONS, expected a non-negative integer\n")
+		os.Exit(1)
+	}
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to listen on port %v: %v\n", port, err)
+		os.Exit(1)
+	}
+	if maxConnections > 0 {
+		listener = newLimitListener(listener, maxConnections)
+	}
+
+	// CACHE_WARM fills the cache with the hot endpoints of CACHE_WARM_URLS after the start,
+	// CACHE_WARM_BATCH_SIZE and CACHE_WARM_DELAY spread the requests so the database is not overloaded
+	warm, err := strconv.ParseBool(getEnv("CACHE_WARM", "false"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid CACHE_WARM, expected a boolean\n")
+		os.Exit(1)
+	}
+	var warmURLs []string
+	var warmConfig cache.WarmConfig
+	if warm {
+		// The responses are cached for the base URL of the request, so the warming requests need to use the one of the clients
+		if value := os.Getenv("PUBLIC_BASE_URL"); !strings.Contains(value, "://") {
+			fmt.Fprintf(os.Stderr, "CACHE_WARM requires PUBLIC_BASE_URL to be set with a scheme\n")
+			os.Exit(1)
+		}
+		for _, url := range strings.Split(getEnv("CACHE_WARM_URLS", defaultWarmURLs), ",") {
+			if url = strings.TrimSpace(url); url != "" {
+				warmURLs = append(warmURLs, url)
+			}
+		}
+		warmConfig.BatchSize, err = strconv.Atoi(getEnv("CACHE_WARM_BATCH_SIZE", "2"))
+		if err != nil || warmConfig.BatchSize <= 0 {
+			fmt.Fprintf(os.Stderr, "Invalid CACHE_WARM_BATCH_SIZE, expected a positive integer\n")
+			os.Exit(1)
+		}
+		warmConfig.Delay, err = time.ParseDuration(getEnv("CACHE_WARM_DELAY", "1s"))
+		if err != nil || warmConfig.Delay < 0 {
+			fmt.Fprintf(os.Stderr, "Invalid CACHE_WARM_DELAY, expected a non-negative duration\n")
+			os.Exit(1)
+		}
+	}
+
+	// SHUTDOWN_TIMEOUT bounds the time active requests get to finish when the server is stopped
+	shutdownTimeout, err := time.ParseDuration(getEnv("SHUTDOWN_TIMEOUT", "15s"))
+	if err != nil || shutdownTimeout <= 0 {
+		fmt.Fprintf(os.Stderr, "Invalid SHUTDOWN_TIMEOUT, expected a positive duration\n")
+		os.Exit(1)
+	}
+	// Stop the server on SIGINT and SIGTERM instead of terminating immediately
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Start the server with the created router and specified port
 	fmt.Printf("pmd-dx-api listening on port %v\n", port)
-	http.ListenAndServe(":"+port, router)
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Serve(listener)
+	}()
+	// Warm the cache in the background, it is stopped when the server shuts down
+	if warm {
+		go warmCache(ctx, router, warmURLs, warmConfig)
+	}
+	select {
+	case err = <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Server stopped unexpectedly: %v\n", err)
+		}
+		return
+	case <-ctx.Done():
+	}
+	// Reset the signal handling so a second signal terminates immediately
+	stop()
+
+	// Stop accepting new connections and wait for the active requests to finish,
+	// the deferred functions then close the redis connection, the database pool and the logs.
+	// The event streams never finish on their own, so they are ended when the shutdown starts
+	server.RegisterOnShutdown(handler.StopEventStreams)
+	fmt.Printf("Shutting down, waiting up to %v for active requests\n", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err = server.Shutdown(shutdownCtx); err != nil {
+		// Connections that are still active are closed forcefully
+		fmt.Fprintf(os.Stderr, "Active requests did not finish in time, closing the remaining connections: %v\n", err)
+		server.Close()
+	}
+	fmt.Printf("Server stopped, closing connections to redis and the database\n")
 }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/janek64/pmd-dx-api/api/db"
+	"github.com/janek64/pmd-dx-api/api/debug"
+	"github.com/janek64/pmd-dx-api/api/handler"
+	"github.com/janek64/pmd-dx-api/api/middleware"
+	"github.com/julienschmidt/httprouter"
+)
+
+// middlewareChains are the middleware chains the routes of the API are registered with.
+type middlewareChains struct {
+	// cached serves the responses from the redis cache, which is only suitable
+	// for responses that depend on nothing but the URL and the data
+	cached func(httprouter.Handle) httprouter.Handle
+	// resourceList is the cached chain of resource lists and their counts, which parses the
+	// ResourceListParams and caches with CACHE_LIST_TTL since they change with every added resource
+	resourceList func(httprouter.Handle) httprouter.Handle
+	// uncached always calls the handler, e.g. for dynamic or streamed responses
+	uncached func(httprouter.Handle) httprouter.Handle
+}
+
+// registerRoutes registers all routes of the API with their middleware chains.
+func registerRoutes(router *httprouter.Router, chains middlewareChains) {
+	cached, resourceList, uncached := chains.cached, chains.resourceList, chains.uncached
+
+	// get registers the handler for GET requests and for HEAD requests, which run the same handler without sending the body
+	get := func(path string, h httprouter.Handle) {
+		router.GET(path, h)
+		router.HEAD(path, middleware.HeadRequest(h))
+	}
+
+	// Register the health probes without middleware, so they are neither logged, cached nor limited
+	get("/healthz", handler.HealthHandler)
+	get("/readyz", handler.ReadyHandler)
+
+	// Register all handlers
+	// The counts are registered like the lists, so they are cached for the same duration
+	get("/v1/search", resourceList(handler.SearchAllHandler))
+	get("/v1/abilities", resourceList(handler.AbilityListHandler))
+	get("/v1/abilities/:searcharg", handler.DispatchStaticRoutes(cached(handler.AbilitySearchHandler), map[string]httprouter.Handle{
+		"count": resourceList(handler.CountHandler(db.AbilityTable)),
+	}))
+	get("/v1/camps", resourceList(handler.CampListHandler))
+	get("/v1/camps/:searcharg", handler.DispatchStaticRoutes(cached(handler.CampSearchHandler), map[string]httprouter.Handle{
+		"count": resourceList(handler.CountHandler(db.CampTable)),
+	}))
+	get("/v1/dungeons", resourceList(middleware.DungeonListParams(handler.DungeonListHandler)))
+	get("/v1/dungeons/:searcharg", handler.DispatchStaticRoutes(cached(handler.DungeonSearchHandler), map[string]httprouter.Handle{
+		"count": resourceList(middleware.DungeonListParams(handler.CountHandler(db.DungeonTable))),
+	}))
+	get("/v1/moves", resourceList(middleware.MoveListParams(handler.MoveListHandler)))
+	get("/v1/moves/:searcharg", handler.DispatchStaticRoutes(cached(handler.MoveSearchHandler), map[string]httprouter.Handle{
+		"by-type": cached(handler.MovesByTypeHandler),
+		"count":   resourceList(middleware.MoveListParams(handler.CountHandler(db.MoveTable))),
+	}))
+	get("/v1/pokemon", resourceList(handler.PokemonListHandler))
+	pokemonListParams := func(h httprouter.Handle) httprouter.Handle {
+		return middleware.NestedListParams("abilities", nil, middleware.NestedListParams("dungeons", nil, middleware.NestedListParams("moves", nil, h)))
+	}
+	// The random pokemon changes with each request, so it is not cached
+	get("/v1/pokemon/:searcharg", handler.DispatchStaticRoutes(cached(pokemonListParams(handler.PokemonSearchHandler)), map[string]httprouter.Handle{
+		"random": uncached(pokemonListParams(handler.RandomPokemonHandler)),
+		"stats":  cached(handler.PokemonStatsHandler),
+		"count":  resourceList(handler.CountHandler(db.PokemonTable)),
+	}))
+	get("/v1/pokemon/:searcharg/defenses", cached(handler.PokemonDefensesHandler))
+	get("/v1/pokemon/:searcharg/full-learnset", cached(handler.PokemonFullLearnsetHandler))
+	get("/v1/pokemon/:searcharg/evolution", cached(handler.PokemonEvolutionHandler))
+	get("/v1/types", resourceList(handler.PokemonTypeListHandler))
+	// The type matrix can be streamed, so it is not buffered by the cache
+	get("/v1/types/:searcharg", handler.DispatchStaticRoutes(cached(middleware.NestedListParams("interactions", &handler.InteractionSortParameter, handler.PokemonTypeSearchHandler)), map[string]httprouter.Handle{
+		"matrix":   uncached(handler.PokemonTypeMatrixHandler),
+		"coverage": cached(handler.PokemonTypeCoverageHandler),
+		"count":    resourceList(handler.CountHandler(db.TypeTable)),
+	}))
+	get("/v1/types/:searcharg/effectiveness/:defender", cached(handler.PokemonTypeEffectivenessHandler))
+
+	// The event stream is long-lived, so it is neither cached nor limited by the request timeout
+	// and is not available for HEAD requests
+	router.GET("/v1/events", middleware.LogRequest(middleware.CORS(middleware.RateLimit(handler.EventsHandler))))
+
+	// Register the admin routes inspecting and purging the cache
+	router.GET("/admin/cache/stats", middleware.LogRequest(middleware.RequireAdminToken(handler.CacheStatsHandler)))
+	router.DELETE("/admin/cache", middleware.LogRequest(middleware.RequireAdminToken(handler.CachePurgeHandler)))
+
+	// Register the debug routes, which are only included in builds with the build tag "debug"
+	debug.RegisterRoutes(router, func(h httprouter.Handle) httprouter.Handle {
+		return middleware.LogRequest(middleware.RequireAdminToken(h))
+	})
+
+	// Register the handlers listing the supported query parameters of each resource
+	for resourceTypeName := range handler.ParameterRegistry {
+		router.OPTIONS("/v1/"+resourceTypeName, middleware.LogRequest(middleware.CORS(handler.ParametersHandler(resourceTypeName))))
+	}
+	// Answer CORS preflight requests on all other routes
+	router.GlobalOPTIONS = http.HandlerFunc(middleware.PreflightHandler)
+
+	// Overwrite the default NotFound handler to log 404 requests
+	router.NotFound = http.HandlerFunc(handler.Default404Handler)
+}
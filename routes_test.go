@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// chainRecorder returns a middleware chain that answers with its name in the header X-Chain instead of calling the handler.
+func chainRecorder(name string) func(httprouter.Handle) httprouter.Handle {
+	return func(httprouter.Handle) httprouter.Handle {
+		return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+			w.Header().Set("X-Chain", name)
+		}
+	}
+}
+
+func TestRegisterRoutesChains(t *testing.T) {
+	router := httprouter.New()
+	registerRoutes(router, middlewareChains{
+		cached:       chainRecorder("cached"),
+		resourceList: chainRecorder("resourceList"),
+		uncached:     chainRecorder("uncached"),
+	})
+	tests := []struct {
+		path string
+		want string
+	}{
+		// All counts are cached like the lists
+		{path: "/v1/abilities/count", want: "resourceList"},
+		{path: "/v1/camps/count", want: "resourceList"},
+		{path: "/v1/dungeons/count", want: "resourceList"},
+		{path: "/v1/moves/count", want: "resourceList"},
+		{path: "/v1/pokemon/count", want: "resourceList"},
+		{path: "/v1/types/count", want: "resourceList"},
+		{path: "/v1/abilities", want: "resourceList"},
+		{path: "/v1/pokemon", want: "resourceList"},
+		{path: "/v1/abilities/1", want: "cached"},
+		{path: "/v1/moves/by-type", want: "cached"},
+		{path: "/v1/pokemon/pikachu", want: "cached"},
+		{path: "/v1/pokemon/stats", want: "cached"},
+		{path: "/v1/pokemon/random", want: "uncached"},
+		{path: "/v1/types/matrix", want: "uncached"},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if got := w.Header().Get("X-Chain"); got != test.want {
+				t.Errorf("chain = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+-- Add an updated_at column to all resource tables
+-- Done after the import since the .csv files do not contain the column
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS trigger AS $$
+BEGIN
+  NEW.updated_at = now();
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE ability ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now();
+CREATE TRIGGER ability_updated_at BEFORE UPDATE ON ability FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+ALTER TABLE camp ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now();
+CREATE TRIGGER camp_updated_at BEFORE UPDATE ON camp FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+ALTER TABLE dungeon ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now();
+CREATE TRIGGER dungeon_updated_at BEFORE UPDATE ON dungeon FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+ALTER TABLE attack_move ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now();
+CREATE TRIGGER attack_move_updated_at BEFORE UPDATE ON attack_move FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+ALTER TABLE pokemon ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now();
+CREATE TRIGGER pokemon_updated_at BEFORE UPDATE ON pokemon FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+ALTER TABLE pokemon_type ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now();
+CREATE TRIGGER pokemon_type_updated_at BEFORE UPDATE ON pokemon_type FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- Create indices for all updated_at columns to speed up filtering
+CREATE INDEX ability_updated_at_idx ON ability (updated_at);
+
+CREATE INDEX camp_updated_at_idx ON camp (updated_at);
+
+CREATE INDEX dungeon_updated_at_idx ON dungeon (updated_at);
+
+CREATE INDEX move_updated_at_idx ON attack_move (updated_at);
+
+CREATE INDEX pokemon_updated_at_idx ON pokemon (updated_at);
+
+CREATE INDEX type_updated_at_idx ON pokemon_type (updated_at);
@@ -112,6 +112,13 @@ CREATE TABLE pokemon_has_type (
   PRIMARY KEY(dex_number, type_ID)
 );
 
+DROP TABLE IF EXISTS evolves_into;
+CREATE TABLE evolves_into (
+  dex_number smallint NOT NULL REFERENCES pokemon (dex_number),
+  evolved_dex_number smallint NOT NULL REFERENCES pokemon (dex_number),
+  PRIMARY KEY(dex_number, evolved_dex_number)
+);
+
 DROP TABLE IF EXISTS effectiveness;
 CREATE TABLE effectiveness (
   attacker smallint REFERENCES pokemon_type (type_ID),
@@ -134,6 +141,9 @@ CREATE INDEX type_name_idx ON pokemon_type (type_name);
 
 CREATE INDEX move_name_index ON attack_move (move_name);
 
+-- Create an index for filtering moves by category and sorting them by power
+CREATE INDEX move_category_power_idx ON attack_move (category, initial_power);
+
 CREATE INDEX ability_name_idx ON ability (ability_name);
 
 CREATE INDEX dungeon_name_idx ON dungeon (dungeon_name);
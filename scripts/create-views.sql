@@ -0,0 +1,38 @@
+-- Declare materialized views for expensive derived data
+-- Refreshed by the API if USE_MATERIALIZED_VIEWS is enabled
+DROP MATERIALIZED VIEW IF EXISTS pokemon_defenses_view;
+CREATE MATERIALIZED VIEW pokemon_defenses_view AS
+SELECT P.dex_number, P.pokemon_name, AT.type_ID AS attacker_ID, AT.type_name AS attacker_name,
+  PT.type_ID AS defender_ID, COALESCE(TT.interaction::text, '') AS interaction
+FROM pokemon P
+CROSS JOIN pokemon_type AT
+LEFT JOIN pokemon_has_type PT ON P.dex_number = PT.dex_number
+LEFT JOIN effectiveness TT ON AT.type_ID = TT.attacker AND PT.type_ID = TT.defender;
+
+DROP MATERIALIZED VIEW IF EXISTS pokemon_group_counts_view;
+CREATE MATERIALIZED VIEW pokemon_group_counts_view AS
+SELECT 'type' AS dimension, T.type_name AS group_name, T.type_ID AS group_order, COUNT(*) AS count
+FROM pokemon_has_type PT INNER JOIN pokemon_type T ON PT.type_ID = T.type_ID
+GROUP BY T.type_ID, T.type_name
+UNION ALL
+SELECT 'camp' AS dimension, C.camp_name AS group_name, C.camp_ID AS group_order, COUNT(*) AS count
+FROM pokemon P INNER JOIN camp C ON P.camp_ID = C.camp_ID
+GROUP BY C.camp_ID, C.camp_name
+UNION ALL
+SELECT 'evolution_stage' AS dimension, COALESCE(evolution_stage::text, 'none') AS group_name,
+  evolution_stage AS group_order, COUNT(*) AS count
+FROM pokemon
+GROUP BY evolution_stage;
+
+-- Create the unique indices required to refresh the views concurrently
+-- A pokemon has a row for each attacking type and each of its own types
+CREATE UNIQUE INDEX defenses_view_unique_idx ON pokemon_defenses_view (dex_number, attacker_ID, defender_ID);
+
+CREATE UNIQUE INDEX group_counts_view_unique_idx ON pokemon_group_counts_view (dimension, group_name);
+
+-- Create indices for the columns used for searches
+CREATE INDEX defenses_view_dex_number_idx ON pokemon_defenses_view (dex_number);
+
+CREATE INDEX defenses_view_pokemon_name_idx ON pokemon_defenses_view (pokemon_name);
+
+CREATE INDEX group_counts_view_dimension_idx ON pokemon_group_counts_view (dimension);
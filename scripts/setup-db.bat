@@ -39,4 +39,13 @@ psql -c "\copy encountered_in FROM '%DATAPATH%\encountered_in.csv' CSV HEADER"
 psql -c "\copy learns FROM '%DATAPATH%\learns.csv' CSV HEADER"
 psql -c "\copy pokemon_has_ability FROM '%DATAPATH%\pokemon_has_ability.csv' CSV HEADER"
 psql -c "\copy pokemon_has_type FROM '%DATAPATH%\pokemon_has_type.csv' CSV HEADER"
+psql -c "\copy evolves_into FROM '%DATAPATH%\evolves_into.csv' CSV HEADER"
+@echo Done.
+
+@echo Adding update timestamps...
+psql -f add-timestamps.sql
+@echo Done.
+
+@echo Creating materialized views...
+psql -f create-views.sql
 @echo Done.
\ No newline at end of file